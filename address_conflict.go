@@ -0,0 +1,247 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+)
+
+// Kind of a conflict reported by Interface.CheckAddressConflicts() and Interface.CheckRouteConflicts().
+type AddressConflictKind uint32
+
+const (
+	// The proposed address is already assigned to some other interface.
+	AddressConflictDuplicateAddress AddressConflictKind = 1
+	// The proposed subnet overlaps an on-link prefix of some other interface.
+	AddressConflictSubnetOverlap AddressConflictKind = 2
+)
+
+var addressConflictKindTable = &enumTable{
+	typeName:    "AddressConflictKind",
+	unknown:     "AddressConflictKind_UNKNOWN",
+	shortPrefix: "AddressConflict",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(AddressConflictDuplicateAddress), "AddressConflictDuplicateAddress"},
+		{int64(AddressConflictSubnetOverlap), "AddressConflictSubnetOverlap"},
+	},
+}
+
+func (kind AddressConflictKind) String() string {
+	return addressConflictKindTable.format(int64(kind))
+}
+
+func (kind AddressConflictKind) MarshalText() ([]byte, error) {
+	return []byte(kind.String()), nil
+}
+
+func (kind *AddressConflictKind) UnmarshalText(text []byte) error {
+
+	value, err := addressConflictKindTable.parse(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*kind = AddressConflictKind(value)
+
+	return nil
+}
+
+// Describes a conflict between a proposed address (or route destination) and a unicast address that is already
+// assigned to some other interface.
+type AddressConflict struct {
+	Kind AddressConflictKind
+
+	// The address or route destination that has been checked.
+	Proposed net.IPNet
+
+	// The conflicting unicast address, with mask set according to its on-link prefix length.
+	Existing net.IPNet
+
+	// The interface the conflicting address is assigned to.
+	InterfaceLuid  uint64
+	InterfaceIndex uint32
+	FriendlyName   string
+}
+
+// Checks whether adding 'address' to the interface would collide with unicast addresses assigned to other interfaces.
+// It reports both addresses which are already assigned elsewhere and subnets which overlap on-link prefixes of other
+// interfaces. Link-local addresses are ignored, since every interface has them. The method doesn't change anything, so
+// it's up to the caller to decide whether to refuse, warn or proceed with Interface.AddAddress().
+func (ifc *Interface) CheckAddressConflicts(address *net.IPNet) ([]*AddressConflict, error) {
+
+	if address == nil {
+		return nil, fmt.Errorf("CheckAddressConflicts() - input argument is nil")
+	}
+
+	rows, names, err := getAddressConflictData()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return findAddressConflicts(ifc.Luid, address, false, rows, names), nil
+}
+
+// Checks whether adding a route described by 'routeData' to the interface would hijack traffic for a subnet which is
+// on-link on some other interface (i.e. a tunnel /24 which collides with the local Wi-Fi /24). Only routes that are at
+// least as specific as the on-link prefix are reported, because broader routes (including the default route) lose
+// against the on-link route anyway.
+func (ifc *Interface) CheckRouteConflicts(routeData *RouteData) ([]*AddressConflict, error) {
+
+	if routeData == nil {
+		return nil, fmt.Errorf("CheckRouteConflicts() - input argument is nil")
+	}
+
+	rows, names, err := getAddressConflictData()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return findAddressConflicts(ifc.Luid, &routeData.Destination, true, rows, names), nil
+}
+
+func getAddressConflictData() ([]*UnicastIpAddressRow, map[uint64]string, error) {
+
+	ifcs, err := GetInterfaces()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[uint64]string, len(ifcs))
+
+	for _, ifc := range ifcs {
+		names[ifc.Luid] = ifc.FriendlyName
+	}
+
+	rows, err := GetUnicastAddresses(AF_UNSPEC)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return rows, names, nil
+}
+
+func findAddressConflicts(interfaceLuid uint64, proposed *net.IPNet, route bool, rows []*UnicastIpAddressRow,
+	names map[uint64]string) []*AddressConflict {
+
+	proposedNet := normalizeIPNet(proposed)
+
+	if proposedNet == nil || proposedNet.IP.IsLinkLocalUnicast() {
+		return nil
+	}
+
+	proposedOnes, _ := proposedNet.Mask.Size()
+
+	var conflicts []*AddressConflict
+
+	for _, row := range rows {
+
+		if row == nil || row.Address == nil || row.InterfaceLuid == interfaceLuid {
+			continue
+		}
+
+		existing := unicastIpAddressRowToIPNet(row)
+
+		if existing == nil || existing.IP.IsLinkLocalUnicast() || len(existing.IP) != len(proposedNet.IP) {
+			continue
+		}
+
+		conflict := &AddressConflict{
+			Proposed:       *proposed,
+			Existing:       *existing,
+			InterfaceLuid:  row.InterfaceLuid,
+			InterfaceIndex: row.InterfaceIndex,
+			FriendlyName:   names[row.InterfaceLuid],
+		}
+
+		if !route && existing.IP.Equal(proposedNet.IP) {
+			conflict.Kind = AddressConflictDuplicateAddress
+			conflicts = append(conflicts, conflict)
+			continue
+		}
+
+		if route {
+			// Only routes at least as specific as the on-link prefix take the traffic over.
+			if proposedOnes < int(row.OnLinkPrefixLength) || !existing.Contains(proposedNet.IP) {
+				continue
+			}
+		} else if !existing.Contains(proposedNet.IP) && !proposedNet.Contains(existing.IP) {
+			continue
+		}
+
+		conflict.Kind = AddressConflictSubnetOverlap
+		conflicts = append(conflicts, conflict)
+	}
+
+	return conflicts
+}
+
+func unicastIpAddressRowToIPNet(row *UnicastIpAddressRow) *net.IPNet {
+
+	ip := row.Address.Address.To4()
+	bits := 32
+
+	if ip == nil {
+		ip = row.Address.Address.To16()
+		bits = 128
+	}
+
+	if ip == nil {
+		return nil
+	}
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(int(row.OnLinkPrefixLength), bits)}
+}
+
+// Returns a copy of 'ipnet' with IPv4 addresses in their 4-byte form, so that net.IPNet.Contains() behaves, or nil if
+// 'ipnet' isn't valid.
+func normalizeIPNet(ipnet *net.IPNet) *net.IPNet {
+
+	if ipnet == nil {
+		return nil
+	}
+
+	ones, bits := ipnet.Mask.Size()
+
+	if bits == 0 {
+		return nil
+	}
+
+	if ip := ipnet.IP.To4(); ip != nil {
+
+		if bits == 128 {
+			ones -= 96
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, 32)}
+	}
+
+	if ip := ipnet.IP.To16(); ip != nil && bits == 128 {
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, 128)}
+	}
+
+	return nil
+}
+
+func (conflict *AddressConflict) String() string {
+
+	if conflict == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("%s: %s conflicts with %s on interface %q (LUID: %d; index: %d)", conflict.Kind.String(),
+		conflict.Proposed.String(), conflict.Existing.String(), conflict.FriendlyName, conflict.InterfaceLuid,
+		conflict.InterfaceIndex)
+}
@@ -0,0 +1,130 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"testing"
+)
+
+const (
+	addressConflict_tunnelLuid = uint64(1)
+	addressConflict_wifiLuid   = uint64(2)
+)
+
+func addressConflictRow(luid uint64, ip string, onLinkPrefixLength uint8) *UnicastIpAddressRow {
+
+	sainet, _ := createSockaddrInet(net.ParseIP(ip))
+
+	return &UnicastIpAddressRow{
+		Address:            sainet,
+		InterfaceLuid:      luid,
+		InterfaceIndex:     uint32(luid) + 10,
+		OnLinkPrefixLength: onLinkPrefixLength,
+	}
+}
+
+func addressConflictRows() []*UnicastIpAddressRow {
+	return []*UnicastIpAddressRow{
+		addressConflictRow(addressConflict_wifiLuid, "192.168.1.23", 24),
+		addressConflictRow(addressConflict_wifiLuid, "fe80::1234", 64),
+		addressConflictRow(addressConflict_wifiLuid, "2001:db8:1::23", 64),
+		addressConflictRow(addressConflict_tunnelLuid, "10.0.0.1", 24),
+	}
+}
+
+func addressConflictNames() map[uint64]string {
+	return map[uint64]string{
+		addressConflict_tunnelLuid: "Tunnel",
+		addressConflict_wifiLuid:   "Wi-Fi",
+	}
+}
+
+func mustParseCIDR(t *testing.T, cidr string) *net.IPNet {
+
+	ip, ipnet, err := net.ParseCIDR(cidr)
+
+	if err != nil {
+		t.Fatalf("net.ParseCIDR(%q) returned an error: %v", cidr, err)
+	}
+
+	ipnet.IP = ip
+
+	return ipnet
+}
+
+func TestFindAddressConflictsSubnetOverlap(t *testing.T) {
+
+	conflicts := findAddressConflicts(addressConflict_tunnelLuid, mustParseCIDR(t, "192.168.1.77/24"), false,
+		addressConflictRows(), addressConflictNames())
+
+	if len(conflicts) != 1 {
+		t.Fatalf("findAddressConflicts() returned %d conflicts although 1 is expected.", len(conflicts))
+	}
+
+	if conflicts[0].Kind != AddressConflictSubnetOverlap {
+		t.Errorf("findAddressConflicts() returned %s although %s is expected.", conflicts[0].Kind,
+			AddressConflictSubnetOverlap)
+	}
+
+	if conflicts[0].FriendlyName != "Wi-Fi" || conflicts[0].InterfaceLuid != addressConflict_wifiLuid {
+		t.Errorf("findAddressConflicts() reported interface %q (LUID %d) although \"Wi-Fi\" is expected.",
+			conflicts[0].FriendlyName, conflicts[0].InterfaceLuid)
+	}
+
+	if conflicts[0].Existing.String() != "192.168.1.23/24" {
+		t.Errorf("findAddressConflicts() reported existing address %s although 192.168.1.23/24 is expected.",
+			conflicts[0].Existing.String())
+	}
+}
+
+func TestFindAddressConflictsDuplicateAddress(t *testing.T) {
+
+	conflicts := findAddressConflicts(addressConflict_tunnelLuid, mustParseCIDR(t, "2001:db8:1::23/128"), false,
+		addressConflictRows(), addressConflictNames())
+
+	if len(conflicts) != 1 || conflicts[0].Kind != AddressConflictDuplicateAddress {
+		t.Errorf("findAddressConflicts() returned %v although a single duplicate address is expected.", conflicts)
+	}
+}
+
+func TestFindAddressConflictsIgnored(t *testing.T) {
+
+	for _, cidr := range []string{"192.168.2.1/24", "fe80::1234/64", "10.0.0.1/24", "2001:db8:2::1/64"} {
+
+		conflicts := findAddressConflicts(addressConflict_tunnelLuid, mustParseCIDR(t, cidr), false,
+			addressConflictRows(), addressConflictNames())
+
+		if len(conflicts) != 0 {
+			t.Errorf("findAddressConflicts() returned %v for %s although no conflicts are expected.", conflicts,
+				cidr)
+		}
+	}
+}
+
+func TestFindAddressConflictsRoute(t *testing.T) {
+
+	conflicts := findAddressConflicts(addressConflict_tunnelLuid, mustParseCIDR(t, "192.168.1.0/24"), true,
+		addressConflictRows(), addressConflictNames())
+
+	if len(conflicts) != 1 || conflicts[0].Kind != AddressConflictSubnetOverlap {
+		t.Errorf("findAddressConflicts() returned %v although a single subnet overlap is expected.", conflicts)
+	}
+
+	for _, cidr := range []string{"0.0.0.0/0", "192.168.0.0/16", "192.168.2.0/24"} {
+
+		conflicts = findAddressConflicts(addressConflict_tunnelLuid, mustParseCIDR(t, cidr), true,
+			addressConflictRows(), addressConflictNames())
+
+		if len(conflicts) != 0 {
+			t.Errorf("findAddressConflicts() returned %v for route %s although no conflicts are expected.",
+				conflicts, cidr)
+		}
+	}
+}
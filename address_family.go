@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // Defined in ws2def.h as AddressFamily
 type AddressFamily uint16 // Windows type: USHORT
 
@@ -17,15 +15,39 @@ const (
 	AF_INET6  AddressFamily = 23
 )
 
+var addressFamilyTable = &enumTable{
+	typeName:    "AddressFamily",
+	unknown:     "ADDRESS_FAMILY_UNKNOWN",
+	shortPrefix: "AF_",
+	kind:        enumUint16,
+	entries: []enumEntry{
+		{int64(AF_UNSPEC), "AF_UNSPEC"},
+		{int64(AF_INET), "AF_INET"},
+		{int64(AF_INET6), "AF_INET6"},
+	},
+	aliases: map[string]int64{
+		"ipv4": int64(AF_INET),
+		"ipv6": int64(AF_INET6),
+	},
+}
+
 func (family AddressFamily) String() string {
-	switch family {
-	case AF_UNSPEC:
-		return "AF_UNSPEC"
-	case AF_INET:
-		return "AF_INET"
-	case AF_INET6:
-		return "AF_INET6"
-	default:
-		return fmt.Sprintf("ADDRESS_FAMILY_UNKNOWN(%d)", family)
+	return addressFamilyTable.format(int64(family))
+}
+
+func (family AddressFamily) MarshalText() ([]byte, error) {
+	return []byte(family.String()), nil
+}
+
+func (family *AddressFamily) UnmarshalText(text []byte) error {
+
+	value, err := addressFamilyTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*family = AddressFamily(value)
+
+	return nil
 }
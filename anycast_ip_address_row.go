@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -16,14 +19,14 @@ type AnycastIpAddressRow struct {
 	//
 	// Key Structure.
 	//
-	Address        SockaddrInet
-	InterfaceLuid  uint64
-	InterfaceIndex uint32
+	Address        SockaddrInet `json:"address"`
+	InterfaceLuid  uint64       `json:"interfaceLuid"`
+	InterfaceIndex uint32       `json:"interfaceIndex"`
 
 	//
 	// Read-Only Fields.
 	//
-	ScopeId uint32
+	ScopeId uint32 `json:"scopeId"`
 }
 
 // Returns all anycast IP addresses from the system. GetAnycastIpAddressTable function
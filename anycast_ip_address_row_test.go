@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -0,0 +1,36 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import "time"
+
+// Source of time for types which schedule work on their own. It exists so that such types can be driven by a fake
+// clock in tests; SystemClock should be used otherwise.
+type Clock interface {
+	Now() time.Time
+
+	// Calls f in its own goroutine after duration d has elapsed, the same way time.AfterFunc() does.
+	AfterFunc(d time.Duration, f func()) ClockTimer
+}
+
+// Timer created by Clock.AfterFunc().
+type ClockTimer interface {
+	// Prevents the timer from firing. Returns false if the timer has already fired or has been stopped.
+	Stop() bool
+}
+
+type systemClock struct{}
+
+// Clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	return time.AfterFunc(d, f)
+}
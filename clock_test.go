@@ -0,0 +1,77 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock which only moves when advance() is called. Timers due are fired synchronously by advance().
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeClockTimer
+}
+
+type fakeClockTimer struct {
+	clock *fakeClock
+	when  time.Time
+	f     func()
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	timer := &fakeClockTimer{clock: c, when: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+func (t *fakeClockTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	for i, timer := range t.clock.timers {
+		if timer == t {
+			t.clock.timers = append(t.clock.timers[:i], t.clock.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Number of timers which haven't fired nor have been stopped yet.
+func (c *fakeClock) pendingTimers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.timers)
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mutex.Lock()
+	c.now = c.now.Add(d)
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].when.Before(c.timers[j].when) })
+	var due []*fakeClockTimer
+	for len(c.timers) > 0 && !c.timers[0].when.After(c.now) {
+		due = append(due, c.timers[0])
+		c.timers = c.timers[1:]
+	}
+	c.mutex.Unlock()
+	for _, timer := range due {
+		timer.f()
+	}
+}
@@ -0,0 +1,106 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"golang.org/x/sys/windows"
+	"os"
+	"runtime"
+)
+
+// Special values of network compartment IDs (Windows type NET_IF_COMPARTMENT_ID), as used by Interface.CompartmentId
+// and the functions below.
+const (
+	NET_IF_COMPARTMENT_ID_UNSPECIFIED uint32 = 0
+	NET_IF_COMPARTMENT_ID_PRIMARY     uint32 = 1
+)
+
+// Returns ID of the network compartment the calling thread is in. Note that goroutines can move between threads, so
+// the result is only meaningful for goroutines locked to their thread by runtime.LockOSThread(); RunInCompartment()
+// should be used instead where possible.
+// Corresponds to GetCurrentThreadCompartmentId function
+// (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getcurrentthreadcompartmentid).
+func GetCurrentThreadCompartmentId() uint32 {
+	return getCurrentThreadCompartmentId()
+}
+
+// Moves the calling thread to network compartment 'compartmentId'. All the functions of this package called from the
+// thread afterwards (routes, addresses, IpInterface operations...) act on that compartment. Like
+// GetCurrentThreadCompartmentId(), it is only meaningful for goroutines locked to their thread.
+// Corresponds to SetCurrentThreadCompartmentId function
+// (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-setcurrentthreadcompartmentid).
+func SetCurrentThreadCompartmentId(compartmentId uint32) error {
+
+	result := setCurrentThreadCompartmentId(compartmentId)
+
+	if result != 0 {
+		return os.NewSyscallError("iphlpapi.SetCurrentThreadCompartmentId", windows.Errno(result))
+	}
+
+	return nil
+}
+
+// Returns ID of the network compartment assigned to session 'sessionId'.
+// Corresponds to GetSessionCompartmentId function
+// (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getsessioncompartmentid).
+func GetSessionCompartmentId(sessionId uint32) (uint32, error) {
+
+	compartmentId := getSessionCompartmentId(sessionId)
+
+	if compartmentId == NET_IF_COMPARTMENT_ID_UNSPECIFIED {
+		return 0, fmt.Errorf("GetSessionCompartmentId() - session %d has no compartment", sessionId)
+	}
+
+	return compartmentId, nil
+}
+
+// Returns ID of the default network compartment.
+// Corresponds to GetDefaultCompartmentId function
+// (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getdefaultcompartmentid).
+func GetDefaultCompartmentId() uint32 {
+	return getDefaultCompartmentId()
+}
+
+// Calls 'f' on an OS thread moved to network compartment 'compartmentId', and returns its error. 'f' runs in its own
+// goroutine locked to that thread, so it must not hand work over to other goroutines if the work has to be done in the
+// compartment. If the thread cannot be moved back to its original compartment afterwards, it is terminated instead of
+// being reused by other goroutines.
+func RunInCompartment(compartmentId uint32, f func() error) error {
+
+	errs := make(chan error, 1)
+
+	go func() {
+
+		runtime.LockOSThread()
+
+		previous := GetCurrentThreadCompartmentId()
+
+		if err := SetCurrentThreadCompartmentId(compartmentId); err != nil {
+			runtime.UnlockOSThread()
+			errs <- err
+			return
+		}
+
+		err := f()
+
+		if restoreErr := SetCurrentThreadCompartmentId(previous); restoreErr != nil {
+			// The thread stays locked, so it exits together with this goroutine.
+			if err == nil {
+				err = fmt.Errorf("RunInCompartment() - cannot restore the thread's compartment: %v", restoreErr)
+			}
+		} else {
+			runtime.UnlockOSThread()
+		}
+
+		errs <- err
+	}()
+
+	return <-errs
+}
@@ -0,0 +1,60 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+)
+
+func TestGetCurrentThreadCompartmentId(t *testing.T) {
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	compartmentId := GetCurrentThreadCompartmentId()
+
+	if compartmentId == NET_IF_COMPARTMENT_ID_UNSPECIFIED {
+		t.Error("GetCurrentThreadCompartmentId() returned NET_IF_COMPARTMENT_ID_UNSPECIFIED.")
+	}
+
+	if err := SetCurrentThreadCompartmentId(compartmentId); err != nil {
+		t.Errorf("SetCurrentThreadCompartmentId() returned an error: %v", err)
+	}
+
+	if defaultId := GetDefaultCompartmentId(); defaultId != NET_IF_COMPARTMENT_ID_PRIMARY {
+		t.Logf("GetDefaultCompartmentId() returned %d.", defaultId)
+	}
+}
+
+func TestRunInCompartment(t *testing.T) {
+
+	compartmentId := GetDefaultCompartmentId()
+
+	err := RunInCompartment(compartmentId, func() error {
+
+		if current := GetCurrentThreadCompartmentId(); current != compartmentId {
+			return fmt.Errorf("thread is in compartment %d instead of %d", current, compartmentId)
+		}
+
+		_, err := GetInterfaces()
+
+		return err
+	})
+
+	if err != nil {
+		t.Errorf("RunInCompartment() returned an error: %v", err)
+	}
+
+	// Compartment IDs are small numbers; this one doesn't exist.
+	if err = RunInCompartment(0xfffffff0, func() error { return nil }); err == nil {
+		t.Error("RunInCompartment() should have failed for nonexistent compartment.")
+	}
+}
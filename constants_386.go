@@ -101,4 +101,13 @@ const (
 	wtIpAdapterWinsServerAddressLh_Reserved_Offset = 4
 	wtIpAdapterWinsServerAddressLh_Next_Offset     = 8
 	wtIpAdapterWinsServerAddressLh_Address_Offset  = 12
+
+	wtDnsInterfaceSettings_Size = 48
+
+	wtDnsInterfaceSettings_Flags_Offset               = 8
+	wtDnsInterfaceSettings_Domain_Offset              = 16
+	wtDnsInterfaceSettings_NameServer_Offset          = 20
+	wtDnsInterfaceSettings_SearchList_Offset          = 24
+	wtDnsInterfaceSettings_RegistrationEnabled_Offset = 28
+	wtDnsInterfaceSettings_ProfileNameServer_Offset   = 44
 )
@@ -102,4 +102,13 @@ const (
 	wtIpAdapterWinsServerAddressLh_Reserved_Offset = 4
 	wtIpAdapterWinsServerAddressLh_Next_Offset     = 8
 	wtIpAdapterWinsServerAddressLh_Address_Offset  = 16
+
+	wtDnsInterfaceSettings_Size = 64
+
+	wtDnsInterfaceSettings_Flags_Offset               = 8
+	wtDnsInterfaceSettings_Domain_Offset              = 16
+	wtDnsInterfaceSettings_NameServer_Offset          = 24
+	wtDnsInterfaceSettings_SearchList_Offset          = 32
+	wtDnsInterfaceSettings_RegistrationEnabled_Offset = 40
+	wtDnsInterfaceSettings_ProfileNameServer_Offset   = 56
 )
@@ -0,0 +1,82 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+)
+
+// Backend configuring static DNS servers of interfaces, used by Interface.SetDNS(), AddDNS() and FlushDNS(). See
+// SetDnsBackend().
+type DnsBackend interface {
+	// Replaces static DNS servers of interface 'ifc' with 'dnses'. IPv4 servers are configured for IPv4 and IPv6
+	// servers for IPv6; empty 'dnses' removes the servers of both address families.
+	SetDNS(ifc *Interface, dnses []net.IP) error
+
+	// Adds 'dnses' to static DNS servers of interface 'ifc'.
+	AddDNS(ifc *Interface, dnses []net.IP) error
+
+	// Removes all static DNS servers of interface 'ifc'.
+	FlushDNS(ifc *Interface) error
+}
+
+type fallbackDnsBackend struct {
+	primary  DnsBackend
+	fallback DnsBackend
+}
+
+// Returns DnsBackend which performs operations by 'primary', and retries those which failed by 'fallback', unless
+// 'primary' has failed after changing some of the servers already.
+func DnsBackendWithFallback(primary DnsBackend, fallback DnsBackend) DnsBackend {
+	return &fallbackDnsBackend{primary: primary, fallback: fallback}
+}
+
+func (b *fallbackDnsBackend) SetDNS(ifc *Interface, dnses []net.IP) error {
+	return b.run(func(backend DnsBackend) error { return backend.SetDNS(ifc, dnses) })
+}
+
+func (b *fallbackDnsBackend) AddDNS(ifc *Interface, dnses []net.IP) error {
+	return b.run(func(backend DnsBackend) error { return backend.AddDNS(ifc, dnses) })
+}
+
+func (b *fallbackDnsBackend) FlushDNS(ifc *Interface) error {
+	return b.run(func(backend DnsBackend) error { return backend.FlushDNS(ifc) })
+}
+
+func (b *fallbackDnsBackend) run(operation func(backend DnsBackend) error) error {
+
+	err := operation(b.primary)
+
+	if err == nil {
+		return nil
+	}
+
+	// Retrying an operation which has been performed partially could i.e. add servers twice.
+	if _, ok := err.(*dnsPartialUpdateError); ok {
+		return err
+	}
+
+	if fallbackErr := operation(b.fallback); fallbackErr != nil {
+		return fmt.Errorf("%v; fallback failed as well: %v", err, fallbackErr)
+	}
+
+	return nil
+}
+
+// Splits 'dnses' into IPv4 and IPv6 addresses. Invalid addresses are skipped.
+func splitDnses(dnses []net.IP) (ipv4 []net.IP, ipv6 []net.IP) {
+
+	for _, dns := range dnses {
+		if v4 := dns.To4(); v4 != nil {
+			ipv4 = append(ipv4, v4)
+		} else if v6 := dns.To16(); v6 != nil {
+			ipv6 = append(ipv6, v6)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,314 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testDnsAdapterName = "{4D36E972-E325-11CE-BFC1-08002BE10318}"
+
+// dnsRegistry keeping values in memory. Only keys present in 'values' exist.
+type fakeDnsRegistry struct {
+	values map[string]map[string]string
+	writes int
+	// Writes to this key fail.
+	failingKey string
+}
+
+func newFakeDnsRegistry() *fakeDnsRegistry {
+	return &fakeDnsRegistry{values: map[string]map[string]string{
+		tcpipInterfacesKey + testDnsAdapterName:  {},
+		tcpip6InterfacesKey + testDnsAdapterName: {},
+	}}
+}
+
+func (r *fakeDnsRegistry) getStringValue(path string, name string) (string, error) {
+
+	key, ok := r.values[path]
+
+	if !ok {
+		return "", fmt.Errorf("key %s doesn't exist", path)
+	}
+
+	return key[name], nil
+}
+
+func (r *fakeDnsRegistry) keyExists(path string) (bool, error) {
+	_, ok := r.values[path]
+	return ok, nil
+}
+
+func (r *fakeDnsRegistry) setStringValue(path string, name string, value string) error {
+
+	key, ok := r.values[path]
+
+	if !ok {
+		return fmt.Errorf("key %s doesn't exist", path)
+	}
+
+	if path == r.failingKey {
+		return fmt.Errorf("writing key %s failed", path)
+	}
+
+	key[name] = value
+	r.writes++
+
+	return nil
+}
+
+func (r *fakeDnsRegistry) nameServers(family AddressFamily) string {
+
+	if family == AF_INET6 {
+		return r.values[tcpip6InterfacesKey+testDnsAdapterName][nameServerValue]
+	}
+
+	return r.values[tcpipInterfacesKey+testDnsAdapterName][nameServerValue]
+}
+
+func newTestRegistryDnsBackend(registry dnsRegistry, flushes *int) *RegistryDnsBackend {
+	return &RegistryDnsBackend{
+		registry: registry,
+		flushResolverCache: func() error {
+			*flushes++
+			return nil
+		},
+	}
+}
+
+func TestRegistryDnsBackend(t *testing.T) {
+
+	registry := newFakeDnsRegistry()
+	flushes := 0
+	backend := newTestRegistryDnsBackend(registry, &flushes)
+	ifc := &Interface{AdapterName: testDnsAdapterName}
+
+	err := backend.SetDNS(ifc, []net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("2606:4700:4700::1111"),
+		net.ParseIP("8.8.8.8"), nil})
+
+	if err != nil {
+		t.Errorf("RegistryDnsBackend.SetDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "1.1.1.1,8.8.8.8" ||
+		registry.nameServers(AF_INET6) != "2606:4700:4700::1111" || flushes != 1 {
+		t.Errorf("RegistryDnsBackend.SetDNS() wrote '%s' and '%s', flushing %d times.",
+			registry.nameServers(AF_INET), registry.nameServers(AF_INET6), flushes)
+	}
+
+	// Existing servers are kept, duplicates aren't added.
+	err = backend.AddDNS(ifc, []net.IP{net.ParseIP("8.8.8.8"), net.ParseIP("9.9.9.9"),
+		net.ParseIP("2606:4700:4700::1001")})
+
+	if err != nil {
+		t.Errorf("RegistryDnsBackend.AddDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "1.1.1.1,8.8.8.8,9.9.9.9" ||
+		registry.nameServers(AF_INET6) != "2606:4700:4700::1111,2606:4700:4700::1001" || flushes != 2 {
+		t.Errorf("RegistryDnsBackend.AddDNS() wrote '%s' and '%s', flushing %d times.",
+			registry.nameServers(AF_INET), registry.nameServers(AF_INET6), flushes)
+	}
+
+	if err = backend.FlushDNS(ifc); err != nil {
+		t.Errorf("RegistryDnsBackend.FlushDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "" || registry.nameServers(AF_INET6) != "" || flushes != 3 {
+		t.Errorf("RegistryDnsBackend.FlushDNS() wrote '%s' and '%s', flushing %d times.",
+			registry.nameServers(AF_INET), registry.nameServers(AF_INET6), flushes)
+	}
+}
+
+func TestRegistryDnsBackendAddDNSParsesExistingValue(t *testing.T) {
+
+	registry := newFakeDnsRegistry()
+	registry.values[tcpipInterfacesKey+testDnsAdapterName][nameServerValue] = "1.1.1.1 8.8.8.8"
+	flushes := 0
+	backend := newTestRegistryDnsBackend(registry, &flushes)
+	ifc := &Interface{AdapterName: testDnsAdapterName}
+
+	if err := backend.AddDNS(ifc, []net.IP{net.ParseIP("1.1.1.1")}); err != nil {
+		t.Errorf("RegistryDnsBackend.AddDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "1.1.1.1,8.8.8.8" {
+		t.Errorf("RegistryDnsBackend.AddDNS() wrote '%s'.", registry.nameServers(AF_INET))
+	}
+
+	registry.values[tcpipInterfacesKey+testDnsAdapterName][nameServerValue] = "1.1.1.1,dns.example"
+	writes := registry.writes
+
+	if err := backend.AddDNS(ifc, []net.IP{net.ParseIP("9.9.9.9")}); err == nil ||
+		!strings.Contains(err.Error(), "dns.example") {
+		t.Errorf("RegistryDnsBackend.AddDNS() returned error %v for an invalid existing value.", err)
+	} else if registry.writes != writes {
+		t.Errorf("RegistryDnsBackend.AddDNS() wrote the registry despite the invalid existing value.")
+	}
+}
+
+func TestRegistryDnsBackendErrors(t *testing.T) {
+
+	flushes := 0
+
+	backend := newTestRegistryDnsBackend(newFakeDnsRegistry(), &flushes)
+
+	if err := backend.SetDNS(&Interface{Luid: 1}, nil); err == nil {
+		t.Errorf("RegistryDnsBackend.SetDNS() succeeded for an interface without AdapterName.")
+	}
+
+	// Interface with IPv6 disabled has no Tcpip6 key; setting IPv6 servers writes nothing.
+	registry := newFakeDnsRegistry()
+	delete(registry.values, tcpip6InterfacesKey+testDnsAdapterName)
+	backend = newTestRegistryDnsBackend(registry, &flushes)
+
+	err := backend.SetDNS(&Interface{AdapterName: testDnsAdapterName}, []net.IP{net.ParseIP("1.1.1.1"),
+		net.ParseIP("2606:4700:4700::1111")})
+
+	if err == nil {
+		t.Errorf("RegistryDnsBackend.SetDNS() succeeded although the Tcpip6 key doesn't exist.")
+	} else if _, ok := err.(*dnsPartialUpdateError); ok || registry.writes != 0 {
+		t.Errorf("RegistryDnsBackend.SetDNS() wrote the registry %d times and returned %#v although the Tcpip6 key "+
+			"doesn't exist.", registry.writes, err)
+	}
+
+	// Writing IPv6 servers fails after IPv4 servers have been written.
+	registry = newFakeDnsRegistry()
+	registry.failingKey = tcpip6InterfacesKey + testDnsAdapterName
+	backend = newTestRegistryDnsBackend(registry, &flushes)
+
+	err = backend.SetDNS(&Interface{AdapterName: testDnsAdapterName}, []net.IP{net.ParseIP("1.1.1.1")})
+
+	if _, ok := err.(*dnsPartialUpdateError); !ok {
+		t.Errorf("RegistryDnsBackend.SetDNS() returned %#v after a partial update.", err)
+	}
+
+	if flushes != 0 {
+		t.Errorf("RegistryDnsBackend flushed the resolver cache %d times after failures.", flushes)
+	}
+}
+
+func TestRegistryDnsBackendIpv6Disabled(t *testing.T) {
+
+	registry := newFakeDnsRegistry()
+	delete(registry.values, tcpip6InterfacesKey+testDnsAdapterName)
+	flushes := 0
+	backend := newTestRegistryDnsBackend(registry, &flushes)
+	ifc := &Interface{AdapterName: testDnsAdapterName}
+
+	// Without IPv6 servers, the missing Tcpip6 key means IPv6 is disabled, and only IPv4 servers are written.
+	if err := backend.SetDNS(ifc, []net.IP{net.ParseIP("1.1.1.1")}); err != nil {
+		t.Errorf("RegistryDnsBackend.SetDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "1.1.1.1" || flushes != 1 {
+		t.Errorf("RegistryDnsBackend.SetDNS() wrote '%s', flushing %d times.", registry.nameServers(AF_INET),
+			flushes)
+	}
+
+	if err := backend.AddDNS(ifc, []net.IP{net.ParseIP("8.8.8.8")}); err != nil {
+		t.Errorf("RegistryDnsBackend.AddDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "1.1.1.1,8.8.8.8" {
+		t.Errorf("RegistryDnsBackend.AddDNS() wrote '%s'.", registry.nameServers(AF_INET))
+	}
+
+	if err := backend.FlushDNS(ifc); err != nil {
+		t.Errorf("RegistryDnsBackend.FlushDNS() returned an error: %v", err)
+	} else if registry.nameServers(AF_INET) != "" {
+		t.Errorf("RegistryDnsBackend.FlushDNS() left '%s'.", registry.nameServers(AF_INET))
+	}
+}
+
+func TestRegistryDnsBackendSetInterfaceDnsSettings(t *testing.T) {
+
+	registry := newFakeDnsRegistry()
+	flushes := 0
+	backend := newTestRegistryDnsBackend(registry, &flushes)
+
+	type call struct {
+		guid       GUID
+		family     AddressFamily
+		nameServer string
+	}
+
+	calls := make([]call, 0)
+
+	backend.setInterfaceDnsSettings = func(guid *GUID, family AddressFamily, nameServer string) error {
+		calls = append(calls, call{*guid, family, nameServer})
+		return nil
+	}
+
+	err := backend.SetDNS(&Interface{AdapterName: testDnsAdapterName},
+		[]net.IP{net.ParseIP("1.1.1.1"), net.ParseIP("2606:4700:4700::1111")})
+
+	guid, _ := ParseGUID(testDnsAdapterName)
+	expected := []call{{guid, AF_INET, "1.1.1.1"}, {guid, AF_INET6, "2606:4700:4700::1111"}}
+
+	if err != nil {
+		t.Errorf("RegistryDnsBackend.SetDNS() returned an error: %v", err)
+	} else if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("RegistryDnsBackend.SetDNS() called SetInterfaceDnsSettings with %v instead of %v.", calls, expected)
+	}
+
+	if registry.writes != 0 || flushes != 0 {
+		t.Errorf("RegistryDnsBackend.SetDNS() wrote the registry %d times and flushed the cache %d times although "+
+			"SetInterfaceDnsSettings is available.", registry.writes, flushes)
+	}
+}
+
+// DnsBackend recording the operations.
+type fakeDnsBackend struct {
+	operations []string
+	err        error
+}
+
+func (b *fakeDnsBackend) SetDNS(ifc *Interface, dnses []net.IP) error {
+	b.operations = append(b.operations, fmt.Sprintf("SetDNS %v", dnses))
+	return b.err
+}
+
+func (b *fakeDnsBackend) AddDNS(ifc *Interface, dnses []net.IP) error {
+	b.operations = append(b.operations, fmt.Sprintf("AddDNS %v", dnses))
+	return b.err
+}
+
+func (b *fakeDnsBackend) FlushDNS(ifc *Interface) error {
+	b.operations = append(b.operations, "FlushDNS")
+	return b.err
+}
+
+func TestDnsBackendWithFallback(t *testing.T) {
+
+	primary := &fakeDnsBackend{}
+	fallback := &fakeDnsBackend{}
+	backend := DnsBackendWithFallback(primary, fallback)
+	ifc := &Interface{}
+	dnses := []net.IP{net.ParseIP("1.1.1.1")}
+
+	if err := backend.SetDNS(ifc, dnses); err != nil || len(primary.operations) != 1 ||
+		len(fallback.operations) != 0 {
+		t.Errorf("DnsBackendWithFallback().SetDNS() returned %v; primary: %v; fallback: %v.", err,
+			primary.operations, fallback.operations)
+	}
+
+	primary.err = errors.New("primary failed")
+
+	if err := backend.AddDNS(ifc, dnses); err != nil ||
+		!reflect.DeepEqual(fallback.operations, []string{"AddDNS [1.1.1.1]"}) {
+		t.Errorf("DnsBackendWithFallback().AddDNS() returned %v; fallback: %v.", err, fallback.operations)
+	}
+
+	fallback.err = errors.New("fallback failed")
+
+	if err := backend.FlushDNS(ifc); err == nil || !strings.Contains(err.Error(), "primary failed") ||
+		!strings.Contains(err.Error(), "fallback failed") {
+		t.Errorf("DnsBackendWithFallback().FlushDNS() returned %v although both backends failed.", err)
+	}
+
+	// Operation performed partially by the primary backend isn't retried.
+	primary.err = &dnsPartialUpdateError{err: errors.New("primary failed partially")}
+	fallback.operations = nil
+
+	if err := backend.AddDNS(ifc, dnses); err != primary.err || len(fallback.operations) != 0 {
+		t.Errorf("DnsBackendWithFallback().AddDNS() returned %v after a partial update; fallback: %v.", err,
+			fallback.operations)
+	}
+}
@@ -0,0 +1,56 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"sync"
+)
+
+// DnsBackend running netsh.exe. It's slow, and it detects errors by matching netsh's English output, so it's
+// unreliable on localized Windows.
+type NetshDnsBackend struct{}
+
+func (NetshDnsBackend) SetDNS(ifc *Interface, dnses []net.IP) error {
+	return runNetsh(append(flushDnsCmds(ifc), addDnsCmds(ifc, dnses)...))
+}
+
+func (NetshDnsBackend) AddDNS(ifc *Interface, dnses []net.IP) error {
+	return runNetsh(addDnsCmds(ifc, dnses))
+}
+
+func (NetshDnsBackend) FlushDNS(ifc *Interface) error {
+	return runNetsh(flushDnsCmds(ifc))
+}
+
+var (
+	dnsBackendMutex = sync.Mutex{}
+	dnsBackend      DnsBackend
+)
+
+// Sets the backend used by Interface.SetDNS(), AddDNS() and FlushDNS(). Nil 'backend' restores the default one, which
+// is RegistryDnsBackend falling back to NetshDnsBackend.
+func SetDnsBackend(backend DnsBackend) {
+
+	dnsBackendMutex.Lock()
+	defer dnsBackendMutex.Unlock()
+
+	dnsBackend = backend
+}
+
+// Returns the backend used by Interface.SetDNS(), AddDNS() and FlushDNS(); see SetDnsBackend().
+func GetDnsBackend() DnsBackend {
+
+	dnsBackendMutex.Lock()
+	defer dnsBackendMutex.Unlock()
+
+	if dnsBackend == nil {
+		// Created on first use, since NewRegistryDnsBackend() loads iphlpapi.dll to look for SetInterfaceDnsSettings.
+		dnsBackend = DnsBackendWithFallback(NewRegistryDnsBackend(), NetshDnsBackend{})
+	}
+
+	return dnsBackend
+}
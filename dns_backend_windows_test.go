@@ -0,0 +1,37 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestSetDnsBackend(t *testing.T) {
+
+	backend := &fakeDnsBackend{}
+
+	SetDnsBackend(backend)
+	defer SetDnsBackend(nil)
+
+	ifc := &Interface{}
+	dnses := []net.IP{net.ParseIP("1.1.1.1")}
+
+	_ = ifc.SetDNS(dnses)
+	_ = ifc.AddDNS(dnses)
+	_ = ifc.FlushDNS()
+
+	expected := []string{"SetDNS [1.1.1.1]", "AddDNS [1.1.1.1]", "FlushDNS"}
+
+	if !reflect.DeepEqual(backend.operations, expected) {
+		t.Errorf("Interface DNS methods performed %v instead of %v.", backend.operations, expected)
+	}
+
+	if GetDnsBackend() != backend {
+		t.Errorf("GetDnsBackend() didn't return the backend set by SetDnsBackend().")
+	}
+}
@@ -0,0 +1,244 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Registry keys (under HKEY_LOCAL_MACHINE) of interfaces' TCP/IP parameters, to be followed by the interface's
+// AdapterName.
+const (
+	tcpipInterfacesKey  = `SYSTEM\CurrentControlSet\Services\Tcpip\Parameters\Interfaces\`
+	tcpip6InterfacesKey = `SYSTEM\CurrentControlSet\Services\Tcpip6\Parameters\Interfaces\`
+)
+
+// Registry value holding static DNS servers of an interface, separated by commas (or spaces).
+const nameServerValue = "NameServer"
+
+// Registry access needed by RegistryDnsBackend. Paths are relative to HKEY_LOCAL_MACHINE.
+type dnsRegistry interface {
+	// Returns string value 'name' of key 'path', or empty string if the value doesn't exist.
+	getStringValue(path string, name string) (string, error)
+
+	keyExists(path string) (bool, error)
+
+	setStringValue(path string, name string, value string) error
+}
+
+// DnsBackend writing the NameServer values of interfaces' keys under Tcpip and Tcpip6 registry keys, which is where
+// Windows keeps static DNS servers. Where iphlpapi.SetInterfaceDnsSettings is available (Windows 10 2004 and newer),
+// the values are written by it; otherwise they are written directly, and the DNS resolver cache is flushed
+// afterwards. Unlike NetshDnsBackend, errors don't depend on the language of Windows.
+type RegistryDnsBackend struct {
+	// Replaceable for tests.
+	registry dnsRegistry
+	// Nil if iphlpapi.SetInterfaceDnsSettings isn't available.
+	setInterfaceDnsSettings func(guid *GUID, family AddressFamily, nameServer string) error
+	flushResolverCache      func() error
+}
+
+func (b *RegistryDnsBackend) SetDNS(ifc *Interface, dnses []net.IP) error {
+
+	ipv4, ipv6 := splitDnses(dnses)
+
+	return b.setNameServers(ifc, ipv4, ipv6)
+}
+
+func (b *RegistryDnsBackend) AddDNS(ifc *Interface, dnses []net.IP) error {
+
+	ipv4, ipv6 := splitDnses(dnses)
+
+	existingIpv4, err := b.getNameServers(ifc, AF_INET)
+
+	if err != nil {
+		return err
+	}
+
+	existingIpv6, err := b.getNameServers(ifc, AF_INET6)
+
+	if err != nil {
+		return err
+	}
+
+	return b.setNameServers(ifc, appendMissingIPs(existingIpv4, ipv4), appendMissingIPs(existingIpv6, ipv6))
+}
+
+func (b *RegistryDnsBackend) FlushDNS(ifc *Interface) error {
+	return b.setNameServers(ifc, nil, nil)
+}
+
+// Returns registry key of the interface's TCP/IP parameters for 'family'.
+func interfaceParametersKey(ifc *Interface, family AddressFamily) (string, error) {
+
+	if ifc.AdapterName == "" {
+		return "", fmt.Errorf("RegistryDnsBackend - interface %d has no AdapterName", ifc.Luid)
+	}
+
+	if family == AF_INET6 {
+		return tcpip6InterfacesKey + ifc.AdapterName, nil
+	}
+
+	return tcpipInterfacesKey + ifc.AdapterName, nil
+}
+
+func (b *RegistryDnsBackend) getNameServers(ifc *Interface, family AddressFamily) ([]net.IP, error) {
+
+	key, err := interfaceParametersKey(ifc, family)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// The family is disabled on the interface.
+	if exists, err := b.registry.keyExists(key); err != nil || !exists {
+		return nil, err
+	}
+
+	value, err := b.registry.getStringValue(key, nameServerValue)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+	ips := make([]net.IP, 0, len(fields))
+
+	for _, field := range fields {
+
+		ip := net.ParseIP(field)
+
+		if ip == nil {
+			return nil, fmt.Errorf("RegistryDnsBackend - invalid %s value '%s' of key %s", nameServerValue,
+				value, key)
+		}
+
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+// Error returned by a DnsBackend which has failed after changing some of the interface's DNS servers already, so that
+// the operation can't simply be retried by another backend.
+type dnsPartialUpdateError struct {
+	err error
+}
+
+func (e *dnsPartialUpdateError) Error() string {
+	return e.err.Error()
+}
+
+func (b *RegistryDnsBackend) setNameServers(ifc *Interface, ipv4 []net.IP, ipv6 []net.IP) error {
+
+	families := []AddressFamily{AF_INET, AF_INET6}
+	ipsByFamily := [][]net.IP{ipv4, ipv6}
+	keys := make([]string, len(families))
+
+	// Validate everything before writing anything, so that failures leave the servers of both families unchanged.
+	for i, family := range families {
+
+		key, err := interfaceParametersKey(ifc, family)
+
+		if err != nil {
+			return err
+		}
+
+		exists, err := b.registry.keyExists(key)
+
+		if err != nil {
+			return err
+		}
+
+		// The key doesn't exist if the family is disabled on the interface (typically IPv6), which only matters if
+		// there are servers of the family to set.
+		if !exists && len(ipsByFamily[i]) > 0 {
+			return fmt.Errorf("RegistryDnsBackend - %s servers cannot be set since key %s doesn't exist",
+				family.String(), key)
+		}
+
+		if exists {
+			keys[i] = key
+		}
+	}
+
+	var guid GUID
+
+	if b.setInterfaceDnsSettings != nil {
+
+		var err error
+
+		if guid, err = ParseGUID(ifc.AdapterName); err != nil {
+			return err
+		}
+	}
+
+	var written []string
+
+	for i, family := range families {
+
+		if keys[i] == "" {
+			continue
+		}
+
+		values := make([]string, len(ipsByFamily[i]))
+
+		for j, ip := range ipsByFamily[i] {
+			values[j] = ip.String()
+		}
+
+		value := strings.Join(values, ",")
+
+		var err error
+
+		if b.setInterfaceDnsSettings != nil {
+			err = b.setInterfaceDnsSettings(&guid, family, value)
+		} else {
+			err = b.registry.setStringValue(keys[i], nameServerValue, value)
+		}
+
+		if err != nil && len(written) > 0 {
+			return &dnsPartialUpdateError{err: fmt.Errorf("RegistryDnsBackend - %s servers have been updated, "+
+				"but updating %s servers failed: %v", strings.Join(written, " and "), family.String(), err)}
+		}
+
+		if err != nil {
+			return err
+		}
+
+		written = append(written, family.String())
+	}
+
+	if b.setInterfaceDnsSettings == nil && len(written) > 0 {
+		return b.flushResolverCache()
+	}
+
+	return nil
+}
+
+// Returns 'ips' followed by those of 'added' which aren't among them.
+func appendMissingIPs(ips []net.IP, added []net.IP) []net.IP {
+
+	for _, ip := range added {
+
+		found := false
+
+		for _, existing := range ips {
+			if existing.Equal(ip) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			ips = append(ips, ip)
+		}
+	}
+
+	return ips
+}
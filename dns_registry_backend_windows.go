@@ -0,0 +1,123 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"os"
+)
+
+// Creates new RegistryDnsBackend, using iphlpapi.SetInterfaceDnsSettings if it's available.
+func NewRegistryDnsBackend() *RegistryDnsBackend {
+
+	backend := &RegistryDnsBackend{
+		registry:           systemDnsRegistry{},
+		flushResolverCache: flushResolverCache,
+	}
+
+	if procSetInterfaceDnsSettings.Find() == nil {
+		backend.setInterfaceDnsSettings = setInterfaceDnsSettings
+	}
+
+	return backend
+}
+
+type systemDnsRegistry struct{}
+
+func (systemDnsRegistry) getStringValue(path string, name string) (string, error) {
+
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+
+	if err != nil {
+		return "", fmt.Errorf("RegistryDnsBackend - opening key %s failed: %v", path, err)
+	}
+
+	defer key.Close()
+
+	value, _, err := key.GetStringValue(name)
+
+	if err == registry.ErrNotExist {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("RegistryDnsBackend - reading value %s of key %s failed: %v", name, path, err)
+	}
+
+	return value, nil
+}
+
+func (systemDnsRegistry) keyExists(path string) (bool, error) {
+
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.QUERY_VALUE)
+
+	if err == registry.ErrNotExist {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("RegistryDnsBackend - opening key %s failed: %v", path, err)
+	}
+
+	key.Close()
+
+	return true, nil
+}
+
+func (systemDnsRegistry) setStringValue(path string, name string, value string) error {
+
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, path, registry.SET_VALUE)
+
+	if err != nil {
+		return fmt.Errorf("RegistryDnsBackend - opening key %s failed: %v", path, err)
+	}
+
+	defer key.Close()
+
+	if err = key.SetStringValue(name, value); err != nil {
+		return fmt.Errorf("RegistryDnsBackend - writing value %s of key %s failed: %v", name, path, err)
+	}
+
+	return nil
+}
+
+func setInterfaceDnsSettings(guid *GUID, family AddressFamily, nameServer string) error {
+
+	nameServer16, err := windows.UTF16PtrFromString(nameServer)
+
+	if err != nil {
+		return err
+	}
+
+	settings := wtDnsInterfaceSettings{
+		Version:    dnsInterfaceSettingsVersion1,
+		Flags:      dnsSettingNameserver,
+		NameServer: nameServer16,
+	}
+
+	if family == AF_INET6 {
+		settings.Flags |= dnsSettingIpv6
+	}
+
+	result := callSetInterfaceDnsSettings(guid, &settings)
+
+	if result != 0 {
+		return os.NewSyscallError("iphlpapi.SetInterfaceDnsSettings", windows.Errno(result))
+	}
+
+	return nil
+}
+
+func flushResolverCache() error {
+
+	if dnsFlushResolverCache() == 0 {
+		return fmt.Errorf("flushResolverCache() - dnsapi.DnsFlushResolverCache failed")
+	}
+
+	return nil
+}
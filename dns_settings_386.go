@@ -0,0 +1,14 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import "unsafe"
+
+// On 386, GUID passed by value is pushed onto the stack as four 32-bit words.
+func callSetInterfaceDnsSettings(guid *GUID, settings *wtDnsInterfaceSettings) int32 {
+	words := (*[4]uint32)(unsafe.Pointer(guid))
+	return setInterfaceDnsSettingsByValue(words[0], words[1], words[2], words[3], settings)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import "golang.org/x/sys/windows"
+
+// On amd64, GUID passed by value is passed by a pointer to a copy.
+func callSetInterfaceDnsSettings(guid *GUID, settings *wtDnsInterfaceSettings) int32 {
+	copied := windows.GUID(*guid)
+	return setInterfaceDnsSettingsByRef(&copied, settings)
+}
@@ -0,0 +1,153 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Range of values an enum type can hold.
+type enumKind int
+
+const (
+	enumUint16 enumKind = iota
+	enumUint32
+	enumInt32
+)
+
+type enumEntry struct {
+	value int64
+	name  string
+}
+
+// Names of an enum type's values, which its String(), MarshalText() and UnmarshalText() methods are all based on.
+//
+// String() returns the constant name for known values and "<unknown>(<value>)" for the others. UnmarshalText() accepts
+// (case insensitive, and ignoring '_' and '-' characters):
+//   - constant names;
+//   - short names, which are constant names without 'shortPrefix' (i.e. "up" for IfOperStatusUp);
+//   - 'aliases';
+//   - "<unknown>(<value>)" strings, as returned by String() for unknown values;
+//   - plain decimal numbers.
+//
+// Plain numbers take precedence, so short names which consist of digits only cannot be used.
+type enumTable struct {
+	typeName    string
+	unknown     string
+	shortPrefix string
+	kind        enumKind
+	entries     []enumEntry
+	aliases     map[string]int64
+
+	once    sync.Once
+	names   map[int64]string
+	lookups map[string]int64
+}
+
+func (t *enumTable) init() {
+	t.once.Do(func() {
+
+		t.names = make(map[int64]string, len(t.entries))
+		t.lookups = make(map[string]int64, 2*len(t.entries)+len(t.aliases))
+
+		add := func(key string, value int64) {
+
+			key = normalizeEnumText(key)
+
+			if key == "" {
+				return
+			}
+
+			if existing, ok := t.lookups[key]; ok && existing != value {
+				panic(fmt.Sprintf("%s has ambiguous name '%s'", t.typeName, key))
+			}
+
+			t.lookups[key] = value
+		}
+
+		for _, entry := range t.entries {
+
+			t.names[entry.value] = entry.name
+
+			add(entry.name, entry.value)
+
+			if t.shortPrefix != "" && strings.HasPrefix(entry.name, t.shortPrefix) {
+				add(strings.TrimPrefix(entry.name, t.shortPrefix), entry.value)
+			}
+		}
+
+		for alias, value := range t.aliases {
+			add(alias, value)
+		}
+	})
+}
+
+func normalizeEnumText(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.Replace(text, "_", "", -1)
+	return strings.Replace(text, "-", "", -1)
+}
+
+func (t *enumTable) format(value int64) string {
+
+	t.init()
+
+	if name, ok := t.names[value]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("%s(%d)", t.unknown, value)
+}
+
+func (t *enumTable) parse(text string) (int64, error) {
+
+	t.init()
+
+	trimmed := strings.TrimSpace(text)
+
+	if value, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+		return t.checkRange(text, value)
+	}
+
+	if strings.HasPrefix(trimmed, t.unknown+"(") && strings.HasSuffix(trimmed, ")") {
+
+		value, err := strconv.ParseInt(trimmed[len(t.unknown)+1:len(trimmed)-1], 10, 64)
+
+		if err == nil {
+			return t.checkRange(text, value)
+		}
+	}
+
+	if value, ok := t.lookups[normalizeEnumText(trimmed)]; ok {
+		return value, nil
+	}
+
+	return 0, fmt.Errorf("%s.UnmarshalText() - unknown value '%s'", t.typeName, text)
+}
+
+func (t *enumTable) checkRange(text string, value int64) (int64, error) {
+
+	var min, max int64
+
+	switch t.kind {
+	case enumUint16:
+		min, max = 0, math.MaxUint16
+	case enumUint32:
+		min, max = 0, math.MaxUint32
+	default:
+		min, max = math.MinInt32, math.MaxInt32
+	}
+
+	if value < min || value > max {
+		return 0, fmt.Errorf("%s.UnmarshalText() - value '%s' is out of range", t.typeName, text)
+	}
+
+	return value, nil
+}
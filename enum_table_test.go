@@ -0,0 +1,156 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var enumTable_all = []*enumTable{
+	addressConflictKindTable,
+	addressFamilyTable,
+	ifOperStatusTable,
+	ifTypeTable,
+	interfaceCategoryTable,
+	mibIfEntryLevelTable,
+	ndisMediumTable,
+	ndisPhysicalMediumTable,
+	netIfAccessTypeTable,
+	netIfAdminStatusTable,
+	netIfConnectionTypeTable,
+	netIfDirectionTypeTable,
+	netIfMediaConnectStateTable,
+	nlDadStateTable,
+	nlLinkLocalAddressBehaviorTable,
+	nlPrefixOriginTable,
+	nlRouteOriginTable,
+	nlRouteProtocolTable,
+	nlRouterDiscoveryBehaviorTable,
+	nlSuffixOriginTable,
+	mibNotificationTypeTable,
+	sourceAddressSelectionRuleTable,
+	tunnelTypeTable,
+}
+
+// Every name of every table has to parse back to its own value; enumTable.init() panics if names are ambiguous.
+func TestEnumTablesRoundTrip(t *testing.T) {
+
+	for _, table := range enumTable_all {
+
+		for _, entry := range table.entries {
+
+			value, err := table.parse(table.format(entry.value))
+
+			if err != nil {
+				t.Errorf("%s: parse(%q) returned an error: %v", table.typeName, entry.name, err)
+			} else if value != entry.value {
+				t.Errorf("%s: parse(%q) returned %d although %d is expected.", table.typeName, entry.name, value,
+					entry.value)
+			}
+		}
+
+		unknown := table.format(12345)
+
+		if value, err := table.parse(unknown); err != nil || value != 12345 {
+			t.Errorf("%s: parse(%q) returned %d, %v although 12345 is expected.", table.typeName, unknown, value, err)
+		}
+	}
+}
+
+func TestEnumUnmarshalText(t *testing.T) {
+
+	vectors := []struct {
+		text     string
+		value    encoding.TextUnmarshaler
+		expected string
+	}{
+		{"IF_TYPE_ETHERNET_CSMACD", new(IfType), "IF_TYPE_ETHERNET_CSMACD"},
+		{"ethernet", new(IfType), "IF_TYPE_ETHERNET_CSMACD"},
+		{"Ethernet_CSMACD", new(IfType), "IF_TYPE_ETHERNET_CSMACD"},
+		{"wifi", new(IfType), "IF_TYPE_IEEE80211"},
+		{"6", new(IfType), "IF_TYPE_ETHERNET_CSMACD"},
+		{"IfType_UNKNOWN(300)", new(IfType), "IfType_UNKNOWN(300)"},
+		{"up", new(IfOperStatus), "IfOperStatusUp"},
+		{"lower-layer-down", new(IfOperStatus), "IfOperStatusLowerLayerDown"},
+		{"NT_STATIC", new(NlRouteProtocol), "NT_STATIC"},
+		{"static", new(NlRouteProtocol), "NT_STATIC"},
+		{"ospf", new(NlRouteProtocol), "RouteProtocolOspf"},
+		{"preferred", new(IpDadState), "IpDadStatePreferred"},
+		{"random", new(IpSuffixOrigin), "IpSuffixOriginRandom"},
+		{"teredo", new(TunnelType), "TUNNEL_TYPE_TEREDO"},
+		{"ipv6", new(AddressFamily), "AF_INET6"},
+		{"unchanged", new(NlLinkLocalAddressBehavior), "LinkLocalUnchanged"},
+		{"-1", new(NlRouterDiscoveryBehavior), "RouterDiscoveryUnchanged"},
+		{"AddInstance", new(MibNotificationType), "MibAddInstance"},
+	}
+
+	for _, vector := range vectors {
+
+		if err := vector.value.UnmarshalText([]byte(vector.text)); err != nil {
+			t.Errorf("UnmarshalText(%q) returned an error: %v", vector.text, err)
+			continue
+		}
+
+		if s := vector.value.(interface{ String() string }).String(); s != vector.expected {
+			t.Errorf("UnmarshalText(%q) produced %s although %s is expected.", vector.text, s, vector.expected)
+		}
+	}
+
+	invalid := []struct {
+		text  string
+		value encoding.TextUnmarshaler
+	}{
+		{"bogus", new(IfType)},
+		{"", new(IfOperStatus)},
+		{"-1", new(IfType)},
+		{"65536", new(AddressFamily)},
+		{"IfOperStatus_UNKNOWN(x)", new(IfOperStatus)},
+		{"2147483648", new(NlLinkLocalAddressBehavior)},
+	}
+
+	for _, vector := range invalid {
+		if err := vector.value.UnmarshalText([]byte(vector.text)); err == nil {
+			t.Errorf("UnmarshalText(%q) didn't return an error.", vector.text)
+		}
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+
+	type container struct {
+		Type     IfType
+		Protocol NlRouteProtocol
+		Unknown  TunnelType
+	}
+
+	data, err := json.Marshal(&container{Type: IF_TYPE_SOFTWARE_LOOPBACK, Protocol: NT_STATIC, Unknown: 42})
+
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	expected := `{"Type":"IF_TYPE_SOFTWARE_LOOPBACK","Protocol":"NT_STATIC","Unknown":"TunnelType_UNKNOWN(42)"}`
+
+	if string(data) != expected {
+		t.Errorf("json.Marshal() returned %s although %s is expected.", data, expected)
+	}
+
+	var decoded container
+
+	if err = json.Unmarshal([]byte(`{"Type":"loopback","Protocol":"static","Unknown":"TunnelType_UNKNOWN(42)"}`),
+		&decoded); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if decoded.Type != IF_TYPE_SOFTWARE_LOOPBACK || decoded.Protocol != NT_STATIC || decoded.Unknown != 42 {
+		t.Errorf("json.Unmarshal() returned %+v.", decoded)
+	}
+}
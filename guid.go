@@ -0,0 +1,95 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// GUID which formats and parses itself in the canonical braced, upper-case form (i.e.
+// "{4D36E972-E325-11CE-BFC1-08002BE10318}"), including when marshaled to text or JSON. It has the same fields and
+// memory layout as windows.GUID, so the two can be converted to each other; the conversion is done at the syscall
+// boundary, and GUID itself doesn't depend on golang.org/x/sys/windows.
+type GUID struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+// Parses 'str' as a GUID. Accepted forms are braced ("{4D36E972-E325-11CE-BFC1-08002BE10318}"), unbraced
+// ("4d36e972-e325-11ce-bfc1-08002be10318") and forms with a prefix ending with a braced GUID, as found in the registry
+// and device names (i.e. "\DEVICE\TCPIP_{4D36E972-E325-11CE-BFC1-08002BE10318}" or
+// "SYSTEM\CurrentControlSet\Services\Tcpip\Parameters\Interfaces\{4D36E972-E325-11CE-BFC1-08002BE10318}"). Parsing
+// is case insensitive.
+func ParseGUID(str string) (GUID, error) {
+
+	s := strings.TrimSpace(str)
+
+	if strings.HasSuffix(s, "}") {
+
+		brace := strings.LastIndex(s, "{")
+
+		if brace < 0 {
+			return GUID{}, fmt.Errorf("ParseGUID() - invalid GUID '%s'", str)
+		}
+
+		s = s[brace+1 : len(s)-1]
+	}
+
+	parts := strings.Split(s, "-")
+
+	if len(s) != 36 || len(parts) != 5 || len(parts[0]) != 8 || len(parts[1]) != 4 || len(parts[2]) != 4 ||
+		len(parts[3]) != 4 {
+		return GUID{}, fmt.Errorf("ParseGUID() - invalid GUID '%s'", str)
+	}
+
+	b, err := hex.DecodeString(strings.Join(parts, ""))
+
+	if err != nil {
+		return GUID{}, fmt.Errorf("ParseGUID() - invalid GUID '%s'", str)
+	}
+
+	guid := GUID{
+		Data1: binary.BigEndian.Uint32(b[0:4]),
+		Data2: binary.BigEndian.Uint16(b[4:6]),
+		Data3: binary.BigEndian.Uint16(b[6:8]),
+	}
+
+	copy(guid.Data4[:], b[8:16])
+
+	return guid, nil
+}
+
+func (guid GUID) String() string {
+	return fmt.Sprintf("{%08X-%04X-%04X-%04X-%012X}", guid.Data1, guid.Data2, guid.Data3, guid.Data4[:2],
+		guid.Data4[2:])
+}
+
+// Returns true if all GUID bits are zero (i.e. NetworkGuid of an interface which isn't connected to any network).
+func (guid GUID) IsZero() bool {
+	return guid == GUID{}
+}
+
+func (guid GUID) MarshalText() ([]byte, error) {
+	return []byte(guid.String()), nil
+}
+
+func (guid *GUID) UnmarshalText(text []byte) error {
+
+	parsed, err := ParseGUID(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*guid = parsed
+
+	return nil
+}
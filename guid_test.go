@@ -0,0 +1,108 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var guid_expected = GUID{
+	Data1: 0x4d36e972,
+	Data2: 0xe325,
+	Data3: 0x11ce,
+	Data4: [8]byte{0xbf, 0xc1, 0x08, 0x00, 0x2b, 0xe1, 0x03, 0x18},
+}
+
+func TestParseGUID(t *testing.T) {
+
+	valid := []string{
+		"{4D36E972-E325-11CE-BFC1-08002BE10318}",
+		"{4d36e972-e325-11ce-bfc1-08002be10318}",
+		"4D36E972-E325-11CE-BFC1-08002BE10318",
+		" 4d36e972-e325-11ce-bfc1-08002be10318 ",
+		`\DEVICE\TCPIP_{4D36E972-E325-11CE-BFC1-08002BE10318}`,
+		`SYSTEM\CurrentControlSet\Services\Tcpip\Parameters\Interfaces\{4D36E972-E325-11CE-BFC1-08002BE10318}`,
+	}
+
+	for _, str := range valid {
+
+		guid, err := ParseGUID(str)
+
+		if err != nil {
+			t.Errorf("ParseGUID(%q) returned an error: %v", str, err)
+		} else if guid != guid_expected {
+			t.Errorf("ParseGUID(%q) returned %s although %s is expected.", str, guid.String(),
+				guid_expected.String())
+		}
+	}
+
+	invalid := []string{
+		"",
+		"{}",
+		"4D36E972-E325-11CE-BFC1-08002BE1031",
+		"4D36E972E325-11CE-BFC1-08002BE103188",
+		"4D36E972-E325-11CE-BFC1-08002BE1031G",
+		"{4D36E972-E325-11CE-BFC1-08002BE10318",
+		"4D36E972-E325-11CE-BFC1-08002BE10318}",
+	}
+
+	for _, str := range invalid {
+		if _, err := ParseGUID(str); err == nil {
+			t.Errorf("ParseGUID(%q) didn't return an error.", str)
+		}
+	}
+}
+
+func TestGUIDString(t *testing.T) {
+
+	if guid_expected.String() != "{4D36E972-E325-11CE-BFC1-08002BE10318}" {
+		t.Errorf("GUID.String() returned %s although {4D36E972-E325-11CE-BFC1-08002BE10318} is expected.",
+			guid_expected.String())
+	}
+
+	// Leading zeros are kept.
+	guid := GUID{Data1: 0x1, Data2: 0x2, Data3: 0x3}
+
+	if guid.String() != "{00000001-0002-0003-0000-000000000000}" {
+		t.Errorf("GUID.String() returned %s although {00000001-0002-0003-0000-000000000000} is expected.",
+			guid.String())
+	}
+}
+
+func TestGUIDJSON(t *testing.T) {
+
+	type container struct {
+		Guid    GUID
+		GuidPtr *GUID
+	}
+
+	data, err := json.Marshal(&container{Guid: guid_expected, GuidPtr: &guid_expected})
+
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	expected := `{"Guid":"{4D36E972-E325-11CE-BFC1-08002BE10318}","GuidPtr":"{4D36E972-E325-11CE-BFC1-08002BE10318}"}`
+
+	if string(data) != expected {
+		t.Errorf("json.Marshal() returned %s although %s is expected.", data, expected)
+	}
+
+	var decoded container
+
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if decoded.Guid != guid_expected || decoded.GuidPtr == nil || *decoded.GuidPtr != guid_expected {
+		t.Errorf("json.Unmarshal() returned %+v.", decoded)
+	}
+
+	if err = json.Unmarshal([]byte(`{"Guid":"not a GUID"}`), &decoded); err == nil {
+		t.Error("json.Unmarshal() didn't return an error for an invalid GUID.")
+	}
+}
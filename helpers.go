@@ -7,17 +7,14 @@ package winipcfg
 
 import (
 	"bytes"
+	"encoding/hex"
 	"fmt"
-	"golang.org/x/sys/windows"
-	"os"
+	"net"
+	"strconv"
 	"strings"
 	"unsafe"
 )
 
-func wcharToString(wchar *uint16, maxLength uint32) string {
-	return windows.UTF16ToString((*(*[1<<30 - 1]uint16)(unsafe.Pointer(wchar)))[:maxLength])
-}
-
 func charToString(char *uint8, maxLength uint32) string {
 	slice := (*(*[1<<30 - 1]uint8)(unsafe.Pointer(char)))[:maxLength]
 	null := bytes.IndexByte(slice, 0)
@@ -27,15 +24,6 @@ func charToString(char *uint8, maxLength uint32) string {
 	return string(slice)
 }
 
-func guidToString(guid *windows.GUID) string {
-	if guid == nil {
-		return "<nil>"
-	} else {
-		return fmt.Sprintf("{%06X-%04X-%04X-%04X-%012X}", guid.Data1, guid.Data2, guid.Data3, guid.Data4[:2],
-			guid.Data4[2:])
-	}
-}
-
 func toIndentedText(text, indent string) string {
 	indented := strings.TrimSpace(text)
 	indented = strings.Replace(indented, "\n", fmt.Sprintf("\n%s", indent), -1)
@@ -64,39 +52,82 @@ func allZeroBytes(bytes []byte) bool {
 	return true
 }
 
-func guidsEqual(guid1, guid2 *windows.GUID) bool {
-	if guid1 == nil {
-		return guid2 == nil
-	}
+// Formats 'ip' as a string, followed by "%<zone>" if 'zone' isn't 0.
+func ipWithZoneToString(ip net.IP, zone uint32) string {
 
-	if guid2 == nil {
-		return false
+	if zone == 0 {
+		return ip.String()
 	}
 
-	return guid1.Data1 == guid2.Data1 && guid1.Data2 == guid2.Data2 && guid1.Data3 == guid2.Data3 &&
-		guid1.Data4 == guid2.Data4
+	return fmt.Sprintf("%s%%%d", ip.String(), zone)
 }
 
-func InterfaceLuidToGuid(luid uint64) (*windows.GUID, error) {
-	guid := windows.GUID{}
+// Parses strings returned by ipWithZoneToString(). IPv4 addresses are returned in their 16-byte form, the same one
+// Windows structs are converted to.
+func parseIPWithZone(str string) (net.IP, uint32, error) {
 
-	result := convertInterfaceLuidToGuid(&luid, &guid)
+	var zone uint32
 
-	if result == 0 {
-		return &guid, nil
-	} else {
-		return nil, os.NewSyscallError("iphlpapi.ConvertInterfaceLuidToGuid", windows.Errno(result))
+	if i := strings.IndexByte(str, '%'); i != -1 {
+
+		z, err := strconv.ParseUint(str[i+1:], 10, 32)
+
+		if err != nil {
+			return nil, 0, fmt.Errorf("parseIPWithZone() - invalid zone in '%s'", str)
+		}
+
+		zone = uint32(z)
+		str = str[:i]
+	}
+
+	ip := net.ParseIP(str)
+
+	if ip == nil {
+		return nil, 0, fmt.Errorf("parseIPWithZone() - invalid IP address '%s'", str)
+	}
+
+	if zone != 0 && !isIPv6Text(str) {
+		return nil, 0, fmt.Errorf("parseIPWithZone() - IPv4 address '%s' cannot have a zone", str)
 	}
+
+	return ip, zone, nil
 }
 
-func InterfaceGuidToLuid(guid *windows.GUID) (uint64, error) {
-	luid := uint64(0)
+// Formats a physical address of any length as colon separated hex bytes, i.e. "00:15:5d:01:02:03".
+func physicalAddressToString(address []byte) string {
+	return net.HardwareAddr(address).String()
+}
 
-	result := convertInterfaceGuidToLuid(guid, &luid)
+// Parses physical addresses of any length, formatted as hex bytes separated by ':' or '-'.
+func parsePhysicalAddress(str string) (net.HardwareAddr, error) {
 
-	if result == 0 {
-		return luid, nil
-	} else {
-		return 0, os.NewSyscallError("iphlpapi.ConvertInterfaceGuidToLuid", windows.Errno(result))
+	if str == "" {
+		return nil, nil
+	}
+
+	separator := ":"
+
+	if !strings.Contains(str, separator) {
+		separator = "-"
+	}
+
+	parts := strings.Split(str, separator)
+	address := make(net.HardwareAddr, len(parts))
+
+	for i, part := range parts {
+
+		if len(part) != 2 {
+			return nil, fmt.Errorf("parsePhysicalAddress() - invalid physical address '%s'", str)
+		}
+
+		b, err := hex.DecodeString(part)
+
+		if err != nil {
+			return nil, fmt.Errorf("parsePhysicalAddress() - invalid physical address '%s'", str)
+		}
+
+		address[i] = b[0]
 	}
+
+	return address, nil
 }
@@ -0,0 +1,180 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"golang.org/x/sys/windows"
+	"os"
+	"unsafe"
+)
+
+func wcharToString(wchar *uint16, maxLength uint32) string {
+	return windows.UTF16ToString((*(*[1<<30 - 1]uint16)(unsafe.Pointer(wchar)))[:maxLength])
+}
+
+func InterfaceLuidToGuid(luid uint64) (*GUID, error) {
+	guid := windows.GUID{}
+
+	result := convertInterfaceLuidToGuid(&luid, &guid)
+
+	if result == 0 {
+		return (*GUID)(&guid), nil
+	} else {
+		return nil, os.NewSyscallError("iphlpapi.ConvertInterfaceLuidToGuid", windows.Errno(result))
+	}
+}
+
+func InterfaceGuidToLuid(guid *GUID) (uint64, error) {
+	luid := uint64(0)
+
+	result := convertInterfaceGuidToLuid((*windows.GUID)(guid), &luid)
+
+	if result == 0 {
+		return luid, nil
+	} else {
+		return 0, os.NewSyscallError("iphlpapi.ConvertInterfaceGuidToLuid", windows.Errno(result))
+	}
+}
+
+func InterfaceLuidToIndex(luid uint64) (uint32, error) {
+	index := uint32(0)
+
+	result := convertInterfaceLuidToIndex(&luid, &index)
+
+	if result == 0 {
+		return index, nil
+	} else {
+		return 0, os.NewSyscallError("iphlpapi.ConvertInterfaceLuidToIndex", windows.Errno(result))
+	}
+}
+
+func InterfaceIndexToLuid(index uint32) (uint64, error) {
+	luid := uint64(0)
+
+	result := convertInterfaceIndexToLuid(index, &luid)
+
+	if result == 0 {
+		return luid, nil
+	} else {
+		return 0, os.NewSyscallError("iphlpapi.ConvertInterfaceIndexToLuid", windows.Errno(result))
+	}
+}
+
+// Returns the interface alias, which is the same as Interface.FriendlyName and IfRow.Alias.
+func InterfaceLuidToAlias(luid uint64) (string, error) {
+	alias := make([]uint16, if_max_string_size+1)
+
+	result := convertInterfaceLuidToAlias(&luid, &alias[0], uintptr(len(alias)))
+
+	if result == 0 {
+		return windows.UTF16ToString(alias), nil
+	} else {
+		return "", os.NewSyscallError("iphlpapi.ConvertInterfaceLuidToAlias", windows.Errno(result))
+	}
+}
+
+func InterfaceAliasToLuid(alias string) (uint64, error) {
+	luid := uint64(0)
+
+	alias16, err := windows.UTF16PtrFromString(alias)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result := convertInterfaceAliasToLuid(alias16, &luid)
+
+	if result == 0 {
+		return luid, nil
+	} else {
+		return 0, os.NewSyscallError("iphlpapi.ConvertInterfaceAliasToLuid", windows.Errno(result))
+	}
+}
+
+// Returns the interface name (i.e. "ethernet_32768"), which is neither FriendlyName nor AdapterName.
+func InterfaceLuidToName(luid uint64) (string, error) {
+	name := make([]uint16, if_max_string_size+1)
+
+	result := convertInterfaceLuidToName(&luid, &name[0], uintptr(len(name)))
+
+	if result == 0 {
+		return windows.UTF16ToString(name), nil
+	} else {
+		return "", os.NewSyscallError("iphlpapi.ConvertInterfaceLuidToNameW", windows.Errno(result))
+	}
+}
+
+func InterfaceNameToLuid(name string) (uint64, error) {
+	luid := uint64(0)
+
+	name16, err := windows.UTF16PtrFromString(name)
+
+	if err != nil {
+		return 0, err
+	}
+
+	result := convertInterfaceNameToLuid(name16, &luid)
+
+	if result == 0 {
+		return luid, nil
+	} else {
+		return 0, os.NewSyscallError("iphlpapi.ConvertInterfaceNameToLuidW", windows.Errno(result))
+	}
+}
+
+// The same as InterfaceLuidToGuid(), taking Luid.
+func (luid Luid) GUID() (*GUID, error) {
+	return InterfaceLuidToGuid(uint64(luid))
+}
+
+// The same as InterfaceGuidToLuid(), returning Luid.
+func LuidFromGUID(guid *GUID) (Luid, error) {
+	luid, err := InterfaceGuidToLuid(guid)
+	return Luid(luid), err
+}
+
+// The same as InterfaceLuidToIndex(), taking Luid.
+func (luid Luid) Index() (uint32, error) {
+	return InterfaceLuidToIndex(uint64(luid))
+}
+
+// The same as InterfaceIndexToLuid(), returning Luid.
+func LuidFromIndex(index uint32) (Luid, error) {
+	luid, err := InterfaceIndexToLuid(index)
+	return Luid(luid), err
+}
+
+// The same as InterfaceLuidToAlias(), taking Luid.
+func (luid Luid) Alias() (string, error) {
+	return InterfaceLuidToAlias(uint64(luid))
+}
+
+// The same as InterfaceAliasToLuid(), returning Luid.
+func LuidFromAlias(alias string) (Luid, error) {
+	luid, err := InterfaceAliasToLuid(alias)
+	return Luid(luid), err
+}
+
+// The same as InterfaceLuidToName(), taking Luid.
+func (luid Luid) Name() (string, error) {
+	return InterfaceLuidToName(uint64(luid))
+}
+
+// The same as InterfaceNameToLuid(), returning Luid.
+func LuidFromName(name string) (Luid, error) {
+	luid, err := InterfaceNameToLuid(name)
+	return Luid(luid), err
+}
+
+// Returns true if 'err' is returned because the requested row doesn't exist.
+func isNotFoundError(err error) bool {
+
+	if syscallErr, ok := err.(*os.SyscallError); ok {
+		err = syscallErr.Err
+	}
+
+	return err == windows.ERROR_NOT_FOUND
+}
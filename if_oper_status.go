@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-if_oper_status
 // IF_OPER_STATUS defined in ifdef.h
 type IfOperStatus uint32
@@ -21,23 +19,39 @@ const (
 	IfOperStatusLowerLayerDown IfOperStatus = 7
 )
 
+var ifOperStatusTable = &enumTable{
+	typeName:    "IfOperStatus",
+	unknown:     "IfOperStatus_UNKNOWN",
+	shortPrefix: "IfOperStatus",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(IfOperStatusUp), "IfOperStatusUp"},
+		{int64(IfOperStatusDown), "IfOperStatusDown"},
+		{int64(IfOperStatusTesting), "IfOperStatusTesting"},
+		{int64(IfOperStatusUnknown), "IfOperStatusUnknown"},
+		{int64(IfOperStatusDormant), "IfOperStatusDormant"},
+		{int64(IfOperStatusNotPresent), "IfOperStatusNotPresent"},
+		{int64(IfOperStatusLowerLayerDown), "IfOperStatusLowerLayerDown"},
+	},
+}
+
 func (s IfOperStatus) String() string {
-	switch s {
-	case IfOperStatusUp:
-		return "IfOperStatusUp"
-	case IfOperStatusDown:
-		return "IfOperStatusDown"
-	case IfOperStatusTesting:
-		return "IfOperStatusTesting"
-	case IfOperStatusUnknown:
-		return "IfOperStatusUnknown"
-	case IfOperStatusDormant:
-		return "IfOperStatusDormant"
-	case IfOperStatusNotPresent:
-		return "IfOperStatusNotPresent"
-	case IfOperStatusLowerLayerDown:
-		return "IfOperStatusLowerLayerDown"
-	default:
-		return fmt.Sprintf("IfOperStatus_UNKNOWN(%d)", s)
+	return ifOperStatusTable.format(int64(s))
+}
+
+func (s IfOperStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *IfOperStatus) UnmarshalText(text []byte) error {
+
+	value, err := ifOperStatusTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*s = IfOperStatus(value)
+
+	return nil
 }
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -6,8 +9,8 @@
 package winipcfg
 
 import (
+	"encoding/json"
 	"fmt"
-	"golang.org/x/sys/windows"
 )
 
 // Corresponds to MIB_IF_ROW2 struct defined in netioapi.h
@@ -16,57 +19,57 @@ type IfRow struct {
 	//
 	// Key structure.  Sorted by preference.
 	//
-	InterfaceLuid  uint64
-	InterfaceIndex uint32
+	InterfaceLuid  uint64 `json:"interfaceLuid"`
+	InterfaceIndex uint32 `json:"interfaceIndex"`
 
 	//
 	// Read-Only fields.
 	//
-	InterfaceGuid            windows.GUID
-	Alias                    string
-	Description              string
-	PhysicalAddress          string
-	PermanentPhysicalAddress string
-
-	Mtu                         uint32
-	Type                        IfType     // Interface Type.
-	TunnelType                  TunnelType // Tunnel Type, if Type = IF_TUNNEL.
-	MediaType                   NdisMedium
-	PhysicalMediumType          NdisPhysicalMedium
-	AccessType                  NetIfAccessType
-	DirectionType               NetIfDirectionType
-	InterfaceAndOperStatusFlags InterfaceAndOperStatusFlags
-
-	OperStatus        IfOperStatus
-	AdminStatus       NetIfAdminStatus
-	MediaConnectState NetIfMediaConnectState
-	NetworkGuid       windows.GUID
-	ConnectionType    NetIfConnectionType
+	InterfaceGuid            GUID   `json:"interfaceGuid"`
+	Alias                    string `json:"alias"`
+	Description              string `json:"description"`
+	PhysicalAddress          string `json:"physicalAddress"`
+	PermanentPhysicalAddress string `json:"permanentPhysicalAddress"`
+
+	Mtu                         uint32                      `json:"mtu"`
+	Type                        IfType                      `json:"type"`       // Interface Type.
+	TunnelType                  TunnelType                  `json:"tunnelType"` // Tunnel Type, if Type = IF_TUNNEL.
+	MediaType                   NdisMedium                  `json:"mediaType"`
+	PhysicalMediumType          NdisPhysicalMedium          `json:"physicalMediumType"`
+	AccessType                  NetIfAccessType             `json:"accessType"`
+	DirectionType               NetIfDirectionType          `json:"directionType"`
+	InterfaceAndOperStatusFlags InterfaceAndOperStatusFlags `json:"interfaceAndOperStatusFlags"`
+
+	OperStatus        IfOperStatus           `json:"operStatus"`
+	AdminStatus       NetIfAdminStatus       `json:"adminStatus"`
+	MediaConnectState NetIfMediaConnectState `json:"mediaConnectState"`
+	NetworkGuid       GUID                   `json:"networkGuid"`
+	ConnectionType    NetIfConnectionType    `json:"connectionType"`
 
 	//
 	// Statistics.
 	//
-	TransmitLinkSpeed uint64
-	ReceiveLinkSpeed  uint64
-
-	InOctets           uint64
-	InUcastPkts        uint64
-	InNUcastPkts       uint64
-	InDiscards         uint64
-	InErrors           uint64
-	InUnknownProtos    uint64
-	InUcastOctets      uint64
-	InMulticastOctets  uint64
-	InBroadcastOctets  uint64
-	OutOctets          uint64
-	OutUcastPkts       uint64
-	OutNUcastPkts      uint64
-	OutDiscards        uint64
-	OutErrors          uint64
-	OutUcastOctets     uint64
-	OutMulticastOctets uint64
-	OutBroadcastOctets uint64
-	OutQLen            uint64
+	TransmitLinkSpeed uint64 `json:"transmitLinkSpeed"`
+	ReceiveLinkSpeed  uint64 `json:"receiveLinkSpeed"`
+
+	InOctets           uint64 `json:"inOctets"`
+	InUcastPkts        uint64 `json:"inUcastPkts"`
+	InNUcastPkts       uint64 `json:"inNUcastPkts"`
+	InDiscards         uint64 `json:"inDiscards"`
+	InErrors           uint64 `json:"inErrors"`
+	InUnknownProtos    uint64 `json:"inUnknownProtos"`
+	InUcastOctets      uint64 `json:"inUcastOctets"`
+	InMulticastOctets  uint64 `json:"inMulticastOctets"`
+	InBroadcastOctets  uint64 `json:"inBroadcastOctets"`
+	OutOctets          uint64 `json:"outOctets"`
+	OutUcastPkts       uint64 `json:"outUcastPkts"`
+	OutNUcastPkts      uint64 `json:"outNUcastPkts"`
+	OutDiscards        uint64 `json:"outDiscards"`
+	OutErrors          uint64 `json:"outErrors"`
+	OutUcastOctets     uint64 `json:"outUcastOctets"`
+	OutMulticastOctets uint64 `json:"outMulticastOctets"`
+	OutBroadcastOctets uint64 `json:"outBroadcastOctets"`
+	OutQLen            uint64 `json:"outQLen"`
 }
 
 // Returns IfRow struct with specified InterfaceLuid. Corresponds to GetIfEntry2Ex function
@@ -103,6 +106,54 @@ func GetIfRows(level MibIfEntryLevel) ([]*IfRow, error) {
 	return ifrows, nil
 }
 
+type ifRowJSONAlias IfRow
+
+type ifRowJSON struct {
+	*ifRowJSONAlias
+	PhysicalAddress          string `json:"physicalAddress"`
+	PermanentPhysicalAddress string `json:"permanentPhysicalAddress"`
+}
+
+// Encodes the row as a JSON object whose members are named after the fields in camel case. Enums are encoded by name,
+// GUIDs in their canonical form and physical addresses as colon separated hex bytes.
+func (ifr IfRow) MarshalJSON() ([]byte, error) {
+
+	alias := ifRowJSONAlias(ifr)
+
+	return json.Marshal(&ifRowJSON{
+		ifRowJSONAlias:           &alias,
+		PhysicalAddress:          physicalAddressToString([]byte(ifr.PhysicalAddress)),
+		PermanentPhysicalAddress: physicalAddressToString([]byte(ifr.PermanentPhysicalAddress)),
+	})
+}
+
+func (ifr *IfRow) UnmarshalJSON(data []byte) error {
+
+	irj := ifRowJSON{ifRowJSONAlias: &ifRowJSONAlias{}}
+
+	if err := json.Unmarshal(data, &irj); err != nil {
+		return err
+	}
+
+	physicalAddress, err := parsePhysicalAddress(irj.PhysicalAddress)
+
+	if err != nil {
+		return err
+	}
+
+	permanentPhysicalAddress, err := parsePhysicalAddress(irj.PermanentPhysicalAddress)
+
+	if err != nil {
+		return err
+	}
+
+	*ifr = IfRow(*irj.ifRowJSONAlias)
+	ifr.PhysicalAddress = string(physicalAddress)
+	ifr.PermanentPhysicalAddress = string(permanentPhysicalAddress)
+
+	return nil
+}
+
 func (ifr *IfRow) String() string {
 
 	if ifr == nil {
@@ -149,11 +200,11 @@ OutErrors: %d
 OutUcastOctets: %d
 OutMulticastOctets: %d
 OutBroadcastOctets: %d
-OutQLen: %d`, ifr.InterfaceLuid, ifr.InterfaceIndex, guidToString(&ifr.InterfaceGuid), ifr.Alias, ifr.Description,
+OutQLen: %d`, ifr.InterfaceLuid, ifr.InterfaceIndex, ifr.InterfaceGuid.String(), ifr.Alias, ifr.Description,
 		ifr.PhysicalAddress, ifr.PermanentPhysicalAddress, ifr.Mtu, ifr.Type.String(), ifr.TunnelType.String(),
 		ifr.MediaType.String(), ifr.PhysicalMediumType.String(), ifr.AccessType.String(), ifr.DirectionType.String(),
 		toIndentedText(ifr.InterfaceAndOperStatusFlags.String(), "    "), ifr.OperStatus.String(),
-		ifr.AdminStatus.String(), ifr.MediaConnectState.String(), guidToString(&ifr.NetworkGuid),
+		ifr.AdminStatus.String(), ifr.MediaConnectState.String(), ifr.NetworkGuid.String(),
 		ifr.ConnectionType.String(), ifr.TransmitLinkSpeed, ifr.ReceiveLinkSpeed, ifr.InOctets, ifr.InUcastPkts,
 		ifr.InNUcastPkts, ifr.InDiscards, ifr.InErrors, ifr.InUnknownProtos, ifr.InUcastOctets, ifr.InMulticastOctets,
 		ifr.InBroadcastOctets, ifr.OutOctets, ifr.OutUcastPkts, ifr.OutNUcastPkts, ifr.OutDiscards, ifr.OutErrors,
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // IFTYPE (of type ULONG), Defined in ipifcons.h
 type IfType uint32
 
@@ -214,411 +212,240 @@ const (
 	IF_TYPE_XBOX_WIRELESS                    IfType = 281
 )
 
+var ifTypeTable = &enumTable{
+	typeName:    "IfType",
+	unknown:     "IfType_UNKNOWN",
+	shortPrefix: "IF_TYPE_",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(IF_TYPE_OTHER), "IF_TYPE_OTHER"},
+		{int64(IF_TYPE_REGULAR_1822), "IF_TYPE_REGULAR_1822"},
+		{int64(IF_TYPE_HDH_1822), "IF_TYPE_HDH_1822"},
+		{int64(IF_TYPE_DDN_X25), "IF_TYPE_DDN_X25"},
+		{int64(IF_TYPE_RFC877_X25), "IF_TYPE_RFC877_X25"},
+		{int64(IF_TYPE_ETHERNET_CSMACD), "IF_TYPE_ETHERNET_CSMACD"},
+		{int64(IF_TYPE_IS088023_CSMACD), "IF_TYPE_IS088023_CSMACD"},
+		{int64(IF_TYPE_ISO88024_TOKENBUS), "IF_TYPE_ISO88024_TOKENBUS"},
+		{int64(IF_TYPE_ISO88025_TOKENRING), "IF_TYPE_ISO88025_TOKENRING"},
+		{int64(IF_TYPE_ISO88026_MAN), "IF_TYPE_ISO88026_MAN"},
+		{int64(IF_TYPE_STARLAN), "IF_TYPE_STARLAN"},
+		{int64(IF_TYPE_PROTEON_10MBIT), "IF_TYPE_PROTEON_10MBIT"},
+		{int64(IF_TYPE_PROTEON_80MBIT), "IF_TYPE_PROTEON_80MBIT"},
+		{int64(IF_TYPE_HYPERCHANNEL), "IF_TYPE_HYPERCHANNEL"},
+		{int64(IF_TYPE_FDDI), "IF_TYPE_FDDI"},
+		{int64(IF_TYPE_LAP_B), "IF_TYPE_LAP_B"},
+		{int64(IF_TYPE_SDLC), "IF_TYPE_SDLC"},
+		{int64(IF_TYPE_DS1), "IF_TYPE_DS1"},
+		{int64(IF_TYPE_E1), "IF_TYPE_E1"},
+		{int64(IF_TYPE_BASIC_ISDN), "IF_TYPE_BASIC_ISDN"},
+		{int64(IF_TYPE_PRIMARY_ISDN), "IF_TYPE_PRIMARY_ISDN"},
+		{int64(IF_TYPE_PROP_POINT2POINT_SERIAL), "IF_TYPE_PROP_POINT2POINT_SERIAL"},
+		{int64(IF_TYPE_PPP), "IF_TYPE_PPP"},
+		{int64(IF_TYPE_SOFTWARE_LOOPBACK), "IF_TYPE_SOFTWARE_LOOPBACK"},
+		{int64(IF_TYPE_EON), "IF_TYPE_EON"},
+		{int64(IF_TYPE_ETHERNET_3MBIT), "IF_TYPE_ETHERNET_3MBIT"},
+		{int64(IF_TYPE_NSIP), "IF_TYPE_NSIP"},
+		{int64(IF_TYPE_SLIP), "IF_TYPE_SLIP"},
+		{int64(IF_TYPE_ULTRA), "IF_TYPE_ULTRA"},
+		{int64(IF_TYPE_DS3), "IF_TYPE_DS3"},
+		{int64(IF_TYPE_SIP), "IF_TYPE_SIP"},
+		{int64(IF_TYPE_FRAMERELAY), "IF_TYPE_FRAMERELAY"},
+		{int64(IF_TYPE_RS232), "IF_TYPE_RS232"},
+		{int64(IF_TYPE_PARA), "IF_TYPE_PARA"},
+		{int64(IF_TYPE_ARCNET), "IF_TYPE_ARCNET"},
+		{int64(IF_TYPE_ARCNET_PLUS), "IF_TYPE_ARCNET_PLUS"},
+		{int64(IF_TYPE_ATM), "IF_TYPE_ATM"},
+		{int64(IF_TYPE_MIO_X25), "IF_TYPE_MIO_X25"},
+		{int64(IF_TYPE_SONET), "IF_TYPE_SONET"},
+		{int64(IF_TYPE_X25_PLE), "IF_TYPE_X25_PLE"},
+		{int64(IF_TYPE_ISO88022_LLC), "IF_TYPE_ISO88022_LLC"},
+		{int64(IF_TYPE_LOCALTALK), "IF_TYPE_LOCALTALK"},
+		{int64(IF_TYPE_SMDS_DXI), "IF_TYPE_SMDS_DXI"},
+		{int64(IF_TYPE_FRAMERELAY_SERVICE), "IF_TYPE_FRAMERELAY_SERVICE"},
+		{int64(IF_TYPE_V35), "IF_TYPE_V35"},
+		{int64(IF_TYPE_HSSI), "IF_TYPE_HSSI"},
+		{int64(IF_TYPE_HIPPI), "IF_TYPE_HIPPI"},
+		{int64(IF_TYPE_MODEM), "IF_TYPE_MODEM"},
+		{int64(IF_TYPE_AAL5), "IF_TYPE_AAL5"},
+		{int64(IF_TYPE_SONET_PATH), "IF_TYPE_SONET_PATH"},
+		{int64(IF_TYPE_SONET_VT), "IF_TYPE_SONET_VT"},
+		{int64(IF_TYPE_SMDS_ICIP), "IF_TYPE_SMDS_ICIP"},
+		{int64(IF_TYPE_PROP_VIRTUAL), "IF_TYPE_PROP_VIRTUAL"},
+		{int64(IF_TYPE_PROP_MULTIPLEXOR), "IF_TYPE_PROP_MULTIPLEXOR"},
+		{int64(IF_TYPE_IEEE80212), "IF_TYPE_IEEE80212"},
+		{int64(IF_TYPE_FIBRECHANNEL), "IF_TYPE_FIBRECHANNEL"},
+		{int64(IF_TYPE_HIPPIINTERFACE), "IF_TYPE_HIPPIINTERFACE"},
+		{int64(IF_TYPE_FRAMERELAY_INTERCONNECT), "IF_TYPE_FRAMERELAY_INTERCONNECT"},
+		{int64(IF_TYPE_AFLANE_8023), "IF_TYPE_AFLANE_8023"},
+		{int64(IF_TYPE_AFLANE_8025), "IF_TYPE_AFLANE_8025"},
+		{int64(IF_TYPE_CCTEMUL), "IF_TYPE_CCTEMUL"},
+		{int64(IF_TYPE_FASTETHER), "IF_TYPE_FASTETHER"},
+		{int64(IF_TYPE_ISDN), "IF_TYPE_ISDN"},
+		{int64(IF_TYPE_V11), "IF_TYPE_V11"},
+		{int64(IF_TYPE_V36), "IF_TYPE_V36"},
+		{int64(IF_TYPE_G703_64K), "IF_TYPE_G703_64K"},
+		{int64(IF_TYPE_G703_2MB), "IF_TYPE_G703_2MB"},
+		{int64(IF_TYPE_QLLC), "IF_TYPE_QLLC"},
+		{int64(IF_TYPE_FASTETHER_FX), "IF_TYPE_FASTETHER_FX"},
+		{int64(IF_TYPE_CHANNEL), "IF_TYPE_CHANNEL"},
+		{int64(IF_TYPE_IEEE80211), "IF_TYPE_IEEE80211"},
+		{int64(IF_TYPE_IBM370PARCHAN), "IF_TYPE_IBM370PARCHAN"},
+		{int64(IF_TYPE_ESCON), "IF_TYPE_ESCON"},
+		{int64(IF_TYPE_DLSW), "IF_TYPE_DLSW"},
+		{int64(IF_TYPE_ISDN_S), "IF_TYPE_ISDN_S"},
+		{int64(IF_TYPE_ISDN_U), "IF_TYPE_ISDN_U"},
+		{int64(IF_TYPE_LAP_D), "IF_TYPE_LAP_D"},
+		{int64(IF_TYPE_IPSWITCH), "IF_TYPE_IPSWITCH"},
+		{int64(IF_TYPE_RSRB), "IF_TYPE_RSRB"},
+		{int64(IF_TYPE_ATM_LOGICAL), "IF_TYPE_ATM_LOGICAL"},
+		{int64(IF_TYPE_DS0), "IF_TYPE_DS0"},
+		{int64(IF_TYPE_DS0_BUNDLE), "IF_TYPE_DS0_BUNDLE"},
+		{int64(IF_TYPE_BSC), "IF_TYPE_BSC"},
+		{int64(IF_TYPE_ASYNC), "IF_TYPE_ASYNC"},
+		{int64(IF_TYPE_CNR), "IF_TYPE_CNR"},
+		{int64(IF_TYPE_ISO88025R_DTR), "IF_TYPE_ISO88025R_DTR"},
+		{int64(IF_TYPE_EPLRS), "IF_TYPE_EPLRS"},
+		{int64(IF_TYPE_ARAP), "IF_TYPE_ARAP"},
+		{int64(IF_TYPE_PROP_CNLS), "IF_TYPE_PROP_CNLS"},
+		{int64(IF_TYPE_HOSTPAD), "IF_TYPE_HOSTPAD"},
+		{int64(IF_TYPE_TERMPAD), "IF_TYPE_TERMPAD"},
+		{int64(IF_TYPE_FRAMERELAY_MPI), "IF_TYPE_FRAMERELAY_MPI"},
+		{int64(IF_TYPE_X213), "IF_TYPE_X213"},
+		{int64(IF_TYPE_ADSL), "IF_TYPE_ADSL"},
+		{int64(IF_TYPE_RADSL), "IF_TYPE_RADSL"},
+		{int64(IF_TYPE_SDSL), "IF_TYPE_SDSL"},
+		{int64(IF_TYPE_VDSL), "IF_TYPE_VDSL"},
+		{int64(IF_TYPE_ISO88025_CRFPRINT), "IF_TYPE_ISO88025_CRFPRINT"},
+		{int64(IF_TYPE_MYRINET), "IF_TYPE_MYRINET"},
+		{int64(IF_TYPE_VOICE_EM), "IF_TYPE_VOICE_EM"},
+		{int64(IF_TYPE_VOICE_FXO), "IF_TYPE_VOICE_FXO"},
+		{int64(IF_TYPE_VOICE_FXS), "IF_TYPE_VOICE_FXS"},
+		{int64(IF_TYPE_VOICE_ENCAP), "IF_TYPE_VOICE_ENCAP"},
+		{int64(IF_TYPE_VOICE_OVERIP), "IF_TYPE_VOICE_OVERIP"},
+		{int64(IF_TYPE_ATM_DXI), "IF_TYPE_ATM_DXI"},
+		{int64(IF_TYPE_ATM_FUNI), "IF_TYPE_ATM_FUNI"},
+		{int64(IF_TYPE_ATM_IMA), "IF_TYPE_ATM_IMA"},
+		{int64(IF_TYPE_PPPMULTILINKBUNDLE), "IF_TYPE_PPPMULTILINKBUNDLE"},
+		{int64(IF_TYPE_IPOVER_CDLC), "IF_TYPE_IPOVER_CDLC"},
+		{int64(IF_TYPE_IPOVER_CLAW), "IF_TYPE_IPOVER_CLAW"},
+		{int64(IF_TYPE_STACKTOSTACK), "IF_TYPE_STACKTOSTACK"},
+		{int64(IF_TYPE_VIRTUALIPADDRESS), "IF_TYPE_VIRTUALIPADDRESS"},
+		{int64(IF_TYPE_MPC), "IF_TYPE_MPC"},
+		{int64(IF_TYPE_IPOVER_ATM), "IF_TYPE_IPOVER_ATM"},
+		{int64(IF_TYPE_ISO88025_FIBER), "IF_TYPE_ISO88025_FIBER"},
+		{int64(IF_TYPE_TDLC), "IF_TYPE_TDLC"},
+		{int64(IF_TYPE_GIGABITETHERNET), "IF_TYPE_GIGABITETHERNET"},
+		{int64(IF_TYPE_HDLC), "IF_TYPE_HDLC"},
+		{int64(IF_TYPE_LAP_F), "IF_TYPE_LAP_F"},
+		{int64(IF_TYPE_V37), "IF_TYPE_V37"},
+		{int64(IF_TYPE_X25_MLP), "IF_TYPE_X25_MLP"},
+		{int64(IF_TYPE_X25_HUNTGROUP), "IF_TYPE_X25_HUNTGROUP"},
+		{int64(IF_TYPE_TRANSPHDLC), "IF_TYPE_TRANSPHDLC"},
+		{int64(IF_TYPE_INTERLEAVE), "IF_TYPE_INTERLEAVE"},
+		{int64(IF_TYPE_FAST), "IF_TYPE_FAST"},
+		{int64(IF_TYPE_IP), "IF_TYPE_IP"},
+		{int64(IF_TYPE_DOCSCABLE_MACLAYER), "IF_TYPE_DOCSCABLE_MACLAYER"},
+		{int64(IF_TYPE_DOCSCABLE_DOWNSTREAM), "IF_TYPE_DOCSCABLE_DOWNSTREAM"},
+		{int64(IF_TYPE_DOCSCABLE_UPSTREAM), "IF_TYPE_DOCSCABLE_UPSTREAM"},
+		{int64(IF_TYPE_A12MPPSWITCH), "IF_TYPE_A12MPPSWITCH"},
+		{int64(IF_TYPE_TUNNEL), "IF_TYPE_TUNNEL"},
+		{int64(IF_TYPE_COFFEE), "IF_TYPE_COFFEE"},
+		{int64(IF_TYPE_CES), "IF_TYPE_CES"},
+		{int64(IF_TYPE_ATM_SUBINTERFACE), "IF_TYPE_ATM_SUBINTERFACE"},
+		{int64(IF_TYPE_L2_VLAN), "IF_TYPE_L2_VLAN"},
+		{int64(IF_TYPE_L3_IPVLAN), "IF_TYPE_L3_IPVLAN"},
+		{int64(IF_TYPE_L3_IPXVLAN), "IF_TYPE_L3_IPXVLAN"},
+		{int64(IF_TYPE_DIGITALPOWERLINE), "IF_TYPE_DIGITALPOWERLINE"},
+		{int64(IF_TYPE_MEDIAMAILOVERIP), "IF_TYPE_MEDIAMAILOVERIP"},
+		{int64(IF_TYPE_DTM), "IF_TYPE_DTM"},
+		{int64(IF_TYPE_DCN), "IF_TYPE_DCN"},
+		{int64(IF_TYPE_IPFORWARD), "IF_TYPE_IPFORWARD"},
+		{int64(IF_TYPE_MSDSL), "IF_TYPE_MSDSL"},
+		{int64(IF_TYPE_IEEE1394), "IF_TYPE_IEEE1394"},
+		{int64(IF_TYPE_IF_GSN), "IF_TYPE_IF_GSN"},
+		{int64(IF_TYPE_DVBRCC_MACLAYER), "IF_TYPE_DVBRCC_MACLAYER"},
+		{int64(IF_TYPE_DVBRCC_DOWNSTREAM), "IF_TYPE_DVBRCC_DOWNSTREAM"},
+		{int64(IF_TYPE_DVBRCC_UPSTREAM), "IF_TYPE_DVBRCC_UPSTREAM"},
+		{int64(IF_TYPE_ATM_VIRTUAL), "IF_TYPE_ATM_VIRTUAL"},
+		{int64(IF_TYPE_MPLS_TUNNEL), "IF_TYPE_MPLS_TUNNEL"},
+		{int64(IF_TYPE_SRP), "IF_TYPE_SRP"},
+		{int64(IF_TYPE_VOICEOVERATM), "IF_TYPE_VOICEOVERATM"},
+		{int64(IF_TYPE_VOICEOVERFRAMERELAY), "IF_TYPE_VOICEOVERFRAMERELAY"},
+		{int64(IF_TYPE_IDSL), "IF_TYPE_IDSL"},
+		{int64(IF_TYPE_COMPOSITELINK), "IF_TYPE_COMPOSITELINK"},
+		{int64(IF_TYPE_SS7_SIGLINK), "IF_TYPE_SS7_SIGLINK"},
+		{int64(IF_TYPE_PROP_WIRELESS_P2P), "IF_TYPE_PROP_WIRELESS_P2P"},
+		{int64(IF_TYPE_FR_FORWARD), "IF_TYPE_FR_FORWARD"},
+		{int64(IF_TYPE_RFC1483), "IF_TYPE_RFC1483"},
+		{int64(IF_TYPE_USB), "IF_TYPE_USB"},
+		{int64(IF_TYPE_IEEE8023AD_LAG), "IF_TYPE_IEEE8023AD_LAG"},
+		{int64(IF_TYPE_BGP_POLICY_ACCOUNTING), "IF_TYPE_BGP_POLICY_ACCOUNTING"},
+		{int64(IF_TYPE_FRF16_MFR_BUNDLE), "IF_TYPE_FRF16_MFR_BUNDLE"},
+		{int64(IF_TYPE_H323_GATEKEEPER), "IF_TYPE_H323_GATEKEEPER"},
+		{int64(IF_TYPE_H323_PROXY), "IF_TYPE_H323_PROXY"},
+		{int64(IF_TYPE_MPLS), "IF_TYPE_MPLS"},
+		{int64(IF_TYPE_MF_SIGLINK), "IF_TYPE_MF_SIGLINK"},
+		{int64(IF_TYPE_HDSL2), "IF_TYPE_HDSL2"},
+		{int64(IF_TYPE_SHDSL), "IF_TYPE_SHDSL"},
+		{int64(IF_TYPE_DS1_FDL), "IF_TYPE_DS1_FDL"},
+		{int64(IF_TYPE_POS), "IF_TYPE_POS"},
+		{int64(IF_TYPE_DVB_ASI_IN), "IF_TYPE_DVB_ASI_IN"},
+		{int64(IF_TYPE_DVB_ASI_OUT), "IF_TYPE_DVB_ASI_OUT"},
+		{int64(IF_TYPE_PLC), "IF_TYPE_PLC"},
+		{int64(IF_TYPE_NFAS), "IF_TYPE_NFAS"},
+		{int64(IF_TYPE_TR008), "IF_TYPE_TR008"},
+		{int64(IF_TYPE_GR303_RDT), "IF_TYPE_GR303_RDT"},
+		{int64(IF_TYPE_GR303_IDT), "IF_TYPE_GR303_IDT"},
+		{int64(IF_TYPE_ISUP), "IF_TYPE_ISUP"},
+		{int64(IF_TYPE_PROP_DOCS_WIRELESS_MACLAYER), "IF_TYPE_PROP_DOCS_WIRELESS_MACLAYER"},
+		{int64(IF_TYPE_PROP_DOCS_WIRELESS_DOWNSTREAM), "IF_TYPE_PROP_DOCS_WIRELESS_DOWNSTREAM"},
+		{int64(IF_TYPE_PROP_DOCS_WIRELESS_UPSTREAM), "IF_TYPE_PROP_DOCS_WIRELESS_UPSTREAM"},
+		{int64(IF_TYPE_HIPERLAN2), "IF_TYPE_HIPERLAN2"},
+		{int64(IF_TYPE_PROP_BWA_P2MP), "IF_TYPE_PROP_BWA_P2MP"},
+		{int64(IF_TYPE_SONET_OVERHEAD_CHANNEL), "IF_TYPE_SONET_OVERHEAD_CHANNEL"},
+		{int64(IF_TYPE_DIGITAL_WRAPPER_OVERHEAD_CHANNEL), "IF_TYPE_DIGITAL_WRAPPER_OVERHEAD_CHANNEL"},
+		{int64(IF_TYPE_AAL2), "IF_TYPE_AAL2"},
+		{int64(IF_TYPE_RADIO_MAC), "IF_TYPE_RADIO_MAC"},
+		{int64(IF_TYPE_ATM_RADIO), "IF_TYPE_ATM_RADIO"},
+		{int64(IF_TYPE_IMT), "IF_TYPE_IMT"},
+		{int64(IF_TYPE_MVL), "IF_TYPE_MVL"},
+		{int64(IF_TYPE_REACH_DSL), "IF_TYPE_REACH_DSL"},
+		{int64(IF_TYPE_FR_DLCI_ENDPT), "IF_TYPE_FR_DLCI_ENDPT"},
+		{int64(IF_TYPE_ATM_VCI_ENDPT), "IF_TYPE_ATM_VCI_ENDPT"},
+		{int64(IF_TYPE_OPTICAL_CHANNEL), "IF_TYPE_OPTICAL_CHANNEL"},
+		{int64(IF_TYPE_OPTICAL_TRANSPORT), "IF_TYPE_OPTICAL_TRANSPORT"},
+		{int64(IF_TYPE_IEEE80216_WMAN), "IF_TYPE_IEEE80216_WMAN"},
+		{int64(IF_TYPE_WWANPP), "IF_TYPE_WWANPP"},
+		{int64(IF_TYPE_WWANPP2), "IF_TYPE_WWANPP2"},
+		{int64(IF_TYPE_IEEE802154), "IF_TYPE_IEEE802154"},
+		{int64(IF_TYPE_XBOX_WIRELESS), "IF_TYPE_XBOX_WIRELESS"},
+	},
+	aliases: map[string]int64{
+		"ethernet": int64(IF_TYPE_ETHERNET_CSMACD),
+		"wifi":     int64(IF_TYPE_IEEE80211),
+		"loopback": int64(IF_TYPE_SOFTWARE_LOOPBACK),
+		"virtual":  int64(IF_TYPE_PROP_VIRTUAL),
+		"wwan":     int64(IF_TYPE_WWANPP),
+	},
+}
+
 func (t IfType) String() string {
-	switch t {
-	case IF_TYPE_OTHER:
-		return "IF_TYPE_OTHER"
-	case IF_TYPE_REGULAR_1822:
-		return "IF_TYPE_REGULAR_1822"
-	case IF_TYPE_HDH_1822:
-		return "IF_TYPE_HDH_1822"
-	case IF_TYPE_DDN_X25:
-		return "IF_TYPE_DDN_X25"
-	case IF_TYPE_RFC877_X25:
-		return "IF_TYPE_RFC877_X25"
-	case IF_TYPE_ETHERNET_CSMACD:
-		return "IF_TYPE_ETHERNET_CSMACD"
-	case IF_TYPE_IS088023_CSMACD:
-		return "IF_TYPE_IS088023_CSMACD"
-	case IF_TYPE_ISO88024_TOKENBUS:
-		return "IF_TYPE_ISO88024_TOKENBUS"
-	case IF_TYPE_ISO88025_TOKENRING:
-		return "IF_TYPE_ISO88025_TOKENRING"
-	case IF_TYPE_ISO88026_MAN:
-		return "IF_TYPE_ISO88026_MAN"
-	case IF_TYPE_STARLAN:
-		return "IF_TYPE_STARLAN"
-	case IF_TYPE_PROTEON_10MBIT:
-		return "IF_TYPE_PROTEON_10MBIT"
-	case IF_TYPE_PROTEON_80MBIT:
-		return "IF_TYPE_PROTEON_80MBIT"
-	case IF_TYPE_HYPERCHANNEL:
-		return "IF_TYPE_HYPERCHANNEL"
-	case IF_TYPE_FDDI:
-		return "IF_TYPE_FDDI"
-	case IF_TYPE_LAP_B:
-		return "IF_TYPE_LAP_B"
-	case IF_TYPE_SDLC:
-		return "IF_TYPE_SDLC"
-	case IF_TYPE_DS1:
-		return "IF_TYPE_DS1"
-	case IF_TYPE_E1:
-		return "IF_TYPE_E1"
-	case IF_TYPE_BASIC_ISDN:
-		return "IF_TYPE_BASIC_ISDN"
-	case IF_TYPE_PRIMARY_ISDN:
-		return "IF_TYPE_PRIMARY_ISDN"
-	case IF_TYPE_PROP_POINT2POINT_SERIAL:
-		return "IF_TYPE_PROP_POINT2POINT_SERIAL"
-	case IF_TYPE_PPP:
-		return "IF_TYPE_PPP"
-	case IF_TYPE_SOFTWARE_LOOPBACK:
-		return "IF_TYPE_SOFTWARE_LOOPBACK"
-	case IF_TYPE_EON:
-		return "IF_TYPE_EON"
-	case IF_TYPE_ETHERNET_3MBIT:
-		return "IF_TYPE_ETHERNET_3MBIT"
-	case IF_TYPE_NSIP:
-		return "IF_TYPE_NSIP"
-	case IF_TYPE_SLIP:
-		return "IF_TYPE_SLIP"
-	case IF_TYPE_ULTRA:
-		return "IF_TYPE_ULTRA"
-	case IF_TYPE_DS3:
-		return "IF_TYPE_DS3"
-	case IF_TYPE_SIP:
-		return "IF_TYPE_SIP"
-	case IF_TYPE_FRAMERELAY:
-		return "IF_TYPE_FRAMERELAY"
-	case IF_TYPE_RS232:
-		return "IF_TYPE_RS232"
-	case IF_TYPE_PARA:
-		return "IF_TYPE_PARA"
-	case IF_TYPE_ARCNET:
-		return "IF_TYPE_ARCNET"
-	case IF_TYPE_ARCNET_PLUS:
-		return "IF_TYPE_ARCNET_PLUS"
-	case IF_TYPE_ATM:
-		return "IF_TYPE_ATM"
-	case IF_TYPE_MIO_X25:
-		return "IF_TYPE_MIO_X25"
-	case IF_TYPE_SONET:
-		return "IF_TYPE_SONET"
-	case IF_TYPE_X25_PLE:
-		return "IF_TYPE_X25_PLE"
-	case IF_TYPE_ISO88022_LLC:
-		return "IF_TYPE_ISO88022_LLC"
-	case IF_TYPE_LOCALTALK:
-		return "IF_TYPE_LOCALTALK"
-	case IF_TYPE_SMDS_DXI:
-		return "IF_TYPE_SMDS_DXI"
-	case IF_TYPE_FRAMERELAY_SERVICE:
-		return "IF_TYPE_FRAMERELAY_SERVICE"
-	case IF_TYPE_V35:
-		return "IF_TYPE_V35"
-	case IF_TYPE_HSSI:
-		return "IF_TYPE_HSSI"
-	case IF_TYPE_HIPPI:
-		return "IF_TYPE_HIPPI"
-	case IF_TYPE_MODEM:
-		return "IF_TYPE_MODEM"
-	case IF_TYPE_AAL5:
-		return "IF_TYPE_AAL5"
-	case IF_TYPE_SONET_PATH:
-		return "IF_TYPE_SONET_PATH"
-	case IF_TYPE_SONET_VT:
-		return "IF_TYPE_SONET_VT"
-	case IF_TYPE_SMDS_ICIP:
-		return "IF_TYPE_SMDS_ICIP"
-	case IF_TYPE_PROP_VIRTUAL:
-		return "IF_TYPE_PROP_VIRTUAL"
-	case IF_TYPE_PROP_MULTIPLEXOR:
-		return "IF_TYPE_PROP_MULTIPLEXOR"
-	case IF_TYPE_IEEE80212:
-		return "IF_TYPE_IEEE80212"
-	case IF_TYPE_FIBRECHANNEL:
-		return "IF_TYPE_FIBRECHANNEL"
-	case IF_TYPE_HIPPIINTERFACE:
-		return "IF_TYPE_HIPPIINTERFACE"
-	case IF_TYPE_FRAMERELAY_INTERCONNECT:
-		return "IF_TYPE_FRAMERELAY_INTERCONNECT"
-	case IF_TYPE_AFLANE_8023:
-		return "IF_TYPE_AFLANE_8023"
-	case IF_TYPE_AFLANE_8025:
-		return "IF_TYPE_AFLANE_8025"
-	case IF_TYPE_CCTEMUL:
-		return "IF_TYPE_CCTEMUL"
-	case IF_TYPE_FASTETHER:
-		return "IF_TYPE_FASTETHER"
-	case IF_TYPE_ISDN:
-		return "IF_TYPE_ISDN"
-	case IF_TYPE_V11:
-		return "IF_TYPE_V11"
-	case IF_TYPE_V36:
-		return "IF_TYPE_V36"
-	case IF_TYPE_G703_64K:
-		return "IF_TYPE_G703_64K"
-	case IF_TYPE_G703_2MB:
-		return "IF_TYPE_G703_2MB"
-	case IF_TYPE_QLLC:
-		return "IF_TYPE_QLLC"
-	case IF_TYPE_FASTETHER_FX:
-		return "IF_TYPE_FASTETHER_FX"
-	case IF_TYPE_CHANNEL:
-		return "IF_TYPE_CHANNEL"
-	case IF_TYPE_IEEE80211:
-		return "IF_TYPE_IEEE80211"
-	case IF_TYPE_IBM370PARCHAN:
-		return "IF_TYPE_IBM370PARCHAN"
-	case IF_TYPE_ESCON:
-		return "IF_TYPE_ESCON"
-	case IF_TYPE_DLSW:
-		return "IF_TYPE_DLSW"
-	case IF_TYPE_ISDN_S:
-		return "IF_TYPE_ISDN_S"
-	case IF_TYPE_ISDN_U:
-		return "IF_TYPE_ISDN_U"
-	case IF_TYPE_LAP_D:
-		return "IF_TYPE_LAP_D"
-	case IF_TYPE_IPSWITCH:
-		return "IF_TYPE_IPSWITCH"
-	case IF_TYPE_RSRB:
-		return "IF_TYPE_RSRB"
-	case IF_TYPE_ATM_LOGICAL:
-		return "IF_TYPE_ATM_LOGICAL"
-	case IF_TYPE_DS0:
-		return "IF_TYPE_DS0"
-	case IF_TYPE_DS0_BUNDLE:
-		return "IF_TYPE_DS0_BUNDLE"
-	case IF_TYPE_BSC:
-		return "IF_TYPE_BSC"
-	case IF_TYPE_ASYNC:
-		return "IF_TYPE_ASYNC"
-	case IF_TYPE_CNR:
-		return "IF_TYPE_CNR"
-	case IF_TYPE_ISO88025R_DTR:
-		return "IF_TYPE_ISO88025R_DTR"
-	case IF_TYPE_EPLRS:
-		return "IF_TYPE_EPLRS"
-	case IF_TYPE_ARAP:
-		return "IF_TYPE_ARAP"
-	case IF_TYPE_PROP_CNLS:
-		return "IF_TYPE_PROP_CNLS"
-	case IF_TYPE_HOSTPAD:
-		return "IF_TYPE_HOSTPAD"
-	case IF_TYPE_TERMPAD:
-		return "IF_TYPE_TERMPAD"
-	case IF_TYPE_FRAMERELAY_MPI:
-		return "IF_TYPE_FRAMERELAY_MPI"
-	case IF_TYPE_X213:
-		return "IF_TYPE_X213"
-	case IF_TYPE_ADSL:
-		return "IF_TYPE_ADSL"
-	case IF_TYPE_RADSL:
-		return "IF_TYPE_RADSL"
-	case IF_TYPE_SDSL:
-		return "IF_TYPE_SDSL"
-	case IF_TYPE_VDSL:
-		return "IF_TYPE_VDSL"
-	case IF_TYPE_ISO88025_CRFPRINT:
-		return "IF_TYPE_ISO88025_CRFPRINT"
-	case IF_TYPE_MYRINET:
-		return "IF_TYPE_MYRINET"
-	case IF_TYPE_VOICE_EM:
-		return "IF_TYPE_VOICE_EM"
-	case IF_TYPE_VOICE_FXO:
-		return "IF_TYPE_VOICE_FXO"
-	case IF_TYPE_VOICE_FXS:
-		return "IF_TYPE_VOICE_FXS"
-	case IF_TYPE_VOICE_ENCAP:
-		return "IF_TYPE_VOICE_ENCAP"
-	case IF_TYPE_VOICE_OVERIP:
-		return "IF_TYPE_VOICE_OVERIP"
-	case IF_TYPE_ATM_DXI:
-		return "IF_TYPE_ATM_DXI"
-	case IF_TYPE_ATM_FUNI:
-		return "IF_TYPE_ATM_FUNI"
-	case IF_TYPE_ATM_IMA:
-		return "IF_TYPE_ATM_IMA"
-	case IF_TYPE_PPPMULTILINKBUNDLE:
-		return "IF_TYPE_PPPMULTILINKBUNDLE"
-	case IF_TYPE_IPOVER_CDLC:
-		return "IF_TYPE_IPOVER_CDLC"
-	case IF_TYPE_IPOVER_CLAW:
-		return "IF_TYPE_IPOVER_CLAW"
-	case IF_TYPE_STACKTOSTACK:
-		return "IF_TYPE_STACKTOSTACK"
-	case IF_TYPE_VIRTUALIPADDRESS:
-		return "IF_TYPE_VIRTUALIPADDRESS"
-	case IF_TYPE_MPC:
-		return "IF_TYPE_MPC"
-	case IF_TYPE_IPOVER_ATM:
-		return "IF_TYPE_IPOVER_ATM"
-	case IF_TYPE_ISO88025_FIBER:
-		return "IF_TYPE_ISO88025_FIBER"
-	case IF_TYPE_TDLC:
-		return "IF_TYPE_TDLC"
-	case IF_TYPE_GIGABITETHERNET:
-		return "IF_TYPE_GIGABITETHERNET"
-	case IF_TYPE_HDLC:
-		return "IF_TYPE_HDLC"
-	case IF_TYPE_LAP_F:
-		return "IF_TYPE_LAP_F"
-	case IF_TYPE_V37:
-		return "IF_TYPE_V37"
-	case IF_TYPE_X25_MLP:
-		return "IF_TYPE_X25_MLP"
-	case IF_TYPE_X25_HUNTGROUP:
-		return "IF_TYPE_X25_HUNTGROUP"
-	case IF_TYPE_TRANSPHDLC:
-		return "IF_TYPE_TRANSPHDLC"
-	case IF_TYPE_INTERLEAVE:
-		return "IF_TYPE_INTERLEAVE"
-	case IF_TYPE_FAST:
-		return "IF_TYPE_FAST"
-	case IF_TYPE_IP:
-		return "IF_TYPE_IP"
-	case IF_TYPE_DOCSCABLE_MACLAYER:
-		return "IF_TYPE_DOCSCABLE_MACLAYER"
-	case IF_TYPE_DOCSCABLE_DOWNSTREAM:
-		return "IF_TYPE_DOCSCABLE_DOWNSTREAM"
-	case IF_TYPE_DOCSCABLE_UPSTREAM:
-		return "IF_TYPE_DOCSCABLE_UPSTREAM"
-	case IF_TYPE_A12MPPSWITCH:
-		return "IF_TYPE_A12MPPSWITCH"
-	case IF_TYPE_TUNNEL:
-		return "IF_TYPE_TUNNEL"
-	case IF_TYPE_COFFEE:
-		return "IF_TYPE_COFFEE"
-	case IF_TYPE_CES:
-		return "IF_TYPE_CES"
-	case IF_TYPE_ATM_SUBINTERFACE:
-		return "IF_TYPE_ATM_SUBINTERFACE"
-	case IF_TYPE_L2_VLAN:
-		return "IF_TYPE_L2_VLAN"
-	case IF_TYPE_L3_IPVLAN:
-		return "IF_TYPE_L3_IPVLAN"
-	case IF_TYPE_L3_IPXVLAN:
-		return "IF_TYPE_L3_IPXVLAN"
-	case IF_TYPE_DIGITALPOWERLINE:
-		return "IF_TYPE_DIGITALPOWERLINE"
-	case IF_TYPE_MEDIAMAILOVERIP:
-		return "IF_TYPE_MEDIAMAILOVERIP"
-	case IF_TYPE_DTM:
-		return "IF_TYPE_DTM"
-	case IF_TYPE_DCN:
-		return "IF_TYPE_DCN"
-	case IF_TYPE_IPFORWARD:
-		return "IF_TYPE_IPFORWARD"
-	case IF_TYPE_MSDSL:
-		return "IF_TYPE_MSDSL"
-	case IF_TYPE_IEEE1394:
-		return "IF_TYPE_IEEE1394"
-	case IF_TYPE_IF_GSN:
-		return "IF_TYPE_IF_GSN"
-	case IF_TYPE_DVBRCC_MACLAYER:
-		return "IF_TYPE_DVBRCC_MACLAYER"
-	case IF_TYPE_DVBRCC_DOWNSTREAM:
-		return "IF_TYPE_DVBRCC_DOWNSTREAM"
-	case IF_TYPE_DVBRCC_UPSTREAM:
-		return "IF_TYPE_DVBRCC_UPSTREAM"
-	case IF_TYPE_ATM_VIRTUAL:
-		return "IF_TYPE_ATM_VIRTUAL"
-	case IF_TYPE_MPLS_TUNNEL:
-		return "IF_TYPE_MPLS_TUNNEL"
-	case IF_TYPE_SRP:
-		return "IF_TYPE_SRP"
-	case IF_TYPE_VOICEOVERATM:
-		return "IF_TYPE_VOICEOVERATM"
-	case IF_TYPE_VOICEOVERFRAMERELAY:
-		return "IF_TYPE_VOICEOVERFRAMERELAY"
-	case IF_TYPE_IDSL:
-		return "IF_TYPE_IDSL"
-	case IF_TYPE_COMPOSITELINK:
-		return "IF_TYPE_COMPOSITELINK"
-	case IF_TYPE_SS7_SIGLINK:
-		return "IF_TYPE_SS7_SIGLINK"
-	case IF_TYPE_PROP_WIRELESS_P2P:
-		return "IF_TYPE_PROP_WIRELESS_P2P"
-	case IF_TYPE_FR_FORWARD:
-		return "IF_TYPE_FR_FORWARD"
-	case IF_TYPE_RFC1483:
-		return "IF_TYPE_RFC1483"
-	case IF_TYPE_USB:
-		return "IF_TYPE_USB"
-	case IF_TYPE_IEEE8023AD_LAG:
-		return "IF_TYPE_IEEE8023AD_LAG"
-	case IF_TYPE_BGP_POLICY_ACCOUNTING:
-		return "IF_TYPE_BGP_POLICY_ACCOUNTING"
-	case IF_TYPE_FRF16_MFR_BUNDLE:
-		return "IF_TYPE_FRF16_MFR_BUNDLE"
-	case IF_TYPE_H323_GATEKEEPER:
-		return "IF_TYPE_H323_GATEKEEPER"
-	case IF_TYPE_H323_PROXY:
-		return "IF_TYPE_H323_PROXY"
-	case IF_TYPE_MPLS:
-		return "IF_TYPE_MPLS"
-	case IF_TYPE_MF_SIGLINK:
-		return "IF_TYPE_MF_SIGLINK"
-	case IF_TYPE_HDSL2:
-		return "IF_TYPE_HDSL2"
-	case IF_TYPE_SHDSL:
-		return "IF_TYPE_SHDSL"
-	case IF_TYPE_DS1_FDL:
-		return "IF_TYPE_DS1_FDL"
-	case IF_TYPE_POS:
-		return "IF_TYPE_POS"
-	case IF_TYPE_DVB_ASI_IN:
-		return "IF_TYPE_DVB_ASI_IN"
-	case IF_TYPE_DVB_ASI_OUT:
-		return "IF_TYPE_DVB_ASI_OUT"
-	case IF_TYPE_PLC:
-		return "IF_TYPE_PLC"
-	case IF_TYPE_NFAS:
-		return "IF_TYPE_NFAS"
-	case IF_TYPE_TR008:
-		return "IF_TYPE_TR008"
-	case IF_TYPE_GR303_RDT:
-		return "IF_TYPE_GR303_RDT"
-	case IF_TYPE_GR303_IDT:
-		return "IF_TYPE_GR303_IDT"
-	case IF_TYPE_ISUP:
-		return "IF_TYPE_ISUP"
-	case IF_TYPE_PROP_DOCS_WIRELESS_MACLAYER:
-		return "IF_TYPE_PROP_DOCS_WIRELESS_MACLAYER"
-	case IF_TYPE_PROP_DOCS_WIRELESS_DOWNSTREAM:
-		return "IF_TYPE_PROP_DOCS_WIRELESS_DOWNSTREAM"
-	case IF_TYPE_PROP_DOCS_WIRELESS_UPSTREAM:
-		return "IF_TYPE_PROP_DOCS_WIRELESS_UPSTREAM"
-	case IF_TYPE_HIPERLAN2:
-		return "IF_TYPE_HIPERLAN2"
-	case IF_TYPE_PROP_BWA_P2MP:
-		return "IF_TYPE_PROP_BWA_P2MP"
-	case IF_TYPE_SONET_OVERHEAD_CHANNEL:
-		return "IF_TYPE_SONET_OVERHEAD_CHANNEL"
-	case IF_TYPE_DIGITAL_WRAPPER_OVERHEAD_CHANNEL:
-		return "IF_TYPE_DIGITAL_WRAPPER_OVERHEAD_CHANNEL"
-	case IF_TYPE_AAL2:
-		return "IF_TYPE_AAL2"
-	case IF_TYPE_RADIO_MAC:
-		return "IF_TYPE_RADIO_MAC"
-	case IF_TYPE_ATM_RADIO:
-		return "IF_TYPE_ATM_RADIO"
-	case IF_TYPE_IMT:
-		return "IF_TYPE_IMT"
-	case IF_TYPE_MVL:
-		return "IF_TYPE_MVL"
-	case IF_TYPE_REACH_DSL:
-		return "IF_TYPE_REACH_DSL"
-	case IF_TYPE_FR_DLCI_ENDPT:
-		return "IF_TYPE_FR_DLCI_ENDPT"
-	case IF_TYPE_ATM_VCI_ENDPT:
-		return "IF_TYPE_ATM_VCI_ENDPT"
-	case IF_TYPE_OPTICAL_CHANNEL:
-		return "IF_TYPE_OPTICAL_CHANNEL"
-	case IF_TYPE_OPTICAL_TRANSPORT:
-		return "IF_TYPE_OPTICAL_TRANSPORT"
-	case IF_TYPE_IEEE80216_WMAN:
-		return "IF_TYPE_IEEE80216_WMAN"
-	case IF_TYPE_WWANPP:
-		return "IF_TYPE_WWANPP"
-	case IF_TYPE_WWANPP2:
-		return "IF_TYPE_WWANPP2"
-	case IF_TYPE_IEEE802154:
-		return "IF_TYPE_IEEE802154"
-	case IF_TYPE_XBOX_WIRELESS:
-		return "IF_TYPE_XBOX_WIRELESS"
-	default:
-		return fmt.Sprintf("IfType_UNKNOWN(%d)", t)
+	return ifTypeTable.format(int64(t))
+}
+
+func (t IfType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *IfType) UnmarshalText(text []byte) error {
+
+	value, err := ifTypeTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*t = IfType(value)
+
+	return nil
 }
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -8,14 +11,14 @@ package winipcfg
 import "fmt"
 
 type InterfaceAndOperStatusFlags struct {
-	HardwareInterface bool
-	FilterInterface   bool
-	ConnectorPresent  bool
-	NotAuthenticated  bool
-	NotMediaConnected bool
-	Paused            bool
-	LowPower          bool
-	EndPointInterface bool
+	HardwareInterface bool `json:"hardwareInterface"`
+	FilterInterface   bool `json:"filterInterface"`
+	ConnectorPresent  bool `json:"connectorPresent"`
+	NotAuthenticated  bool `json:"notAuthenticated"`
+	NotMediaConnected bool `json:"notMediaConnected"`
+	Paused            bool `json:"paused"`
+	LowPower          bool `json:"lowPower"`
+	EndPointInterface bool `json:"endPointInterface"`
 }
 
 func (iaosf *InterfaceAndOperStatusFlags) toInterfaceAndOperStatusFlagsByte() interfaceAndOperStatusFlagsByte {
@@ -0,0 +1,338 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Minimum time between refreshes of a fresh InterfaceCache caused by lookup misses. Lookups of interfaces which don't
+// exist would get all the interfaces on every call otherwise.
+const InterfaceCacheMissRefreshInterval = time.Second
+
+// Opt-in cache of GetInterfacesEx() result, indexed by LUID, index, friendly name and GUID. Unlike InterfaceFromLUID()
+// and the similar functions, which get all the interfaces from the system and scan them on every call, lookups through
+// the cache only get the interfaces when the cache is stale. The cache becomes stale when an interface or unicast
+// address change notification arrives (after Start() has been called), when Invalidate() is called, or when it is
+// older than its max staleness. It is safe for concurrent use.
+//
+// Returned *Interface values are shared by all the callers and must not be modified.
+type InterfaceCache struct {
+	maxStaleness        time.Duration
+	missRefreshInterval time.Duration
+	clock               Clock
+	flags               GetAdapterAddressesFlags
+
+	// Replaceable for tests.
+	getInterfaces func() ([]*Interface, error)
+
+	// Serializes refreshes, so that concurrent lookups of a stale cache get the interfaces only once.
+	refreshMutex sync.Mutex
+
+	mutex          sync.RWMutex
+	valid          bool
+	generation     uint64
+	refreshedAt    time.Time
+	interfaces     []*Interface
+	byLuid         map[uint64]*Interface
+	byIndex        map[uint32]*Interface
+	byFriendlyName map[string]*Interface
+	byGuid         map[GUID]*Interface
+
+	registrationMutex           sync.Mutex
+	interfaceChangeRegistration *InterfaceChangeCallback
+	addressChangeRegistration   *UnicastAddressChangeCallback
+}
+
+// Creates new InterfaceCache whose content is gotten by GetInterfacesEx() with 'flags' (or with
+// DefaultGetAdapterAddressesFlags() if 'flags' is nil). GAA_FLAG_SKIP_FRIENDLY_NAME is cleared regardless of 'flags',
+// since the cache is indexed by friendly name. Content older than 'maxStaleness', as measured by 'clock'
+// (typically SystemClock), is refreshed on the next lookup; 0 means that content never expires by age, which only
+// makes sense if the cache is started by Start() method.
+func NewInterfaceCache(flags *GetAdapterAddressesFlags, maxStaleness time.Duration, clock Clock) *InterfaceCache {
+
+	if flags == nil {
+		flags = DefaultGetAdapterAddressesFlags()
+	}
+
+	cache := &InterfaceCache{
+		maxStaleness:        maxStaleness,
+		missRefreshInterval: InterfaceCacheMissRefreshInterval,
+		clock:               clock,
+		flags:               *flags,
+	}
+
+	cache.flags.GAA_FLAG_SKIP_FRIENDLY_NAME = false
+
+	cache.getInterfaces = func() ([]*Interface, error) {
+		flags := cache.flags
+		return GetInterfacesEx(&flags)
+	}
+
+	return cache
+}
+
+// Registers for interface and unicast address change notifications, which invalidate the cache.
+func (c *InterfaceCache) Start() error {
+
+	c.registrationMutex.Lock()
+	defer c.registrationMutex.Unlock()
+
+	if c.interfaceChangeRegistration != nil {
+		return fmt.Errorf("InterfaceCache.Start() - already started")
+	}
+
+	interfaceChangeRegistration, err := RegisterInterfaceChangeCallback(c.interfaceChanged)
+
+	if err != nil {
+		return err
+	}
+
+	addressChangeRegistration, err := RegisterUnicastAddressChangeCallback(c.unicastAddressChanged)
+
+	if err != nil {
+		interfaceChangeRegistration.Unregister()
+		return err
+	}
+
+	c.interfaceChangeRegistration = interfaceChangeRegistration
+	c.addressChangeRegistration = addressChangeRegistration
+
+	// Changes which happened before the registration wouldn't be noticed otherwise.
+	c.Invalidate()
+
+	return nil
+}
+
+// Unregisters from change notifications. The cache keeps working afterwards, bounded by its max staleness only.
+func (c *InterfaceCache) Stop() error {
+
+	c.registrationMutex.Lock()
+	defer c.registrationMutex.Unlock()
+
+	if c.interfaceChangeRegistration == nil {
+		return nil
+	}
+
+	err := c.interfaceChangeRegistration.Unregister()
+
+	if err2 := c.addressChangeRegistration.Unregister(); err == nil {
+		err = err2
+	}
+
+	c.interfaceChangeRegistration = nil
+	c.addressChangeRegistration = nil
+
+	return err
+}
+
+func (c *InterfaceCache) interfaceChanged(notificationType MibNotificationType, interfaceLuid uint64) {
+	c.Invalidate()
+}
+
+func (c *InterfaceCache) unicastAddressChanged(notificationType MibNotificationType, interfaceLuid uint64,
+	ip *net.IP) {
+	c.Invalidate()
+}
+
+// Marks the cache as stale, so that the next lookup refreshes it.
+func (c *InterfaceCache) Invalidate() {
+	c.mutex.Lock()
+	c.valid = false
+	c.generation++
+	c.mutex.Unlock()
+}
+
+// Returns all the interfaces.
+func (c *InterfaceCache) Interfaces() ([]*Interface, error) {
+
+	var ifcs []*Interface
+
+	err := c.lookup(func() bool {
+		ifcs = make([]*Interface, len(c.interfaces))
+		copy(ifcs, c.interfaces)
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ifcs, nil
+}
+
+// Returns interface with specified LUID.
+func (c *InterfaceCache) InterfaceFromLUID(luid uint64) (*Interface, error) {
+
+	var ifc *Interface
+
+	err := c.lookup(func() bool {
+		ifc = c.byLuid[luid]
+		return ifc != nil
+	})
+
+	if err == nil && ifc == nil {
+		err = fmt.Errorf("InterfaceCache.InterfaceFromLUID() - interface with specified LUID not found")
+	}
+
+	return ifc, err
+}
+
+// Returns interface at specified index.
+func (c *InterfaceCache) InterfaceFromIndex(index uint32) (*Interface, error) {
+
+	var ifc *Interface
+
+	err := c.lookup(func() bool {
+		ifc = c.byIndex[index]
+		return ifc != nil
+	})
+
+	if err == nil && ifc == nil {
+		err = fmt.Errorf("InterfaceCache.InterfaceFromIndex() - interface with specified index not found")
+	}
+
+	return ifc, err
+}
+
+// Returns interface with specified friendly name.
+func (c *InterfaceCache) InterfaceFromFriendlyName(friendlyName string) (*Interface, error) {
+
+	var ifc *Interface
+
+	err := c.lookup(func() bool {
+		ifc = c.byFriendlyName[friendlyName]
+		return ifc != nil
+	})
+
+	if err == nil && ifc == nil {
+		err = fmt.Errorf("InterfaceCache.InterfaceFromFriendlyName() - interface with specified friendly name not " +
+			"found")
+	}
+
+	return ifc, err
+}
+
+// Returns interface with specified GUID.
+func (c *InterfaceCache) InterfaceFromGUID(guid *GUID) (*Interface, error) {
+
+	if guid == nil {
+		return nil, fmt.Errorf("InterfaceCache.InterfaceFromGUID() - guid is nil")
+	}
+
+	var ifc *Interface
+
+	err := c.lookup(func() bool {
+		ifc = c.byGuid[*guid]
+		return ifc != nil
+	})
+
+	if err == nil && ifc == nil {
+		err = fmt.Errorf("InterfaceCache.InterfaceFromGUID() - interface with specified GUID not found")
+	}
+
+	return ifc, err
+}
+
+// Calls 'find' with the cache read-locked, refreshing the cache first if it's stale. If 'find' returns false (a miss),
+// and the cache hasn't been refreshed within InterfaceCacheMissRefreshInterval, it is refreshed and 'find' is called
+// once more, so that interfaces which have appeared before their change notification is delivered are found as well.
+func (c *InterfaceCache) lookup(find func() bool) error {
+
+	c.mutex.RLock()
+	fresh := c.isFresh()
+	found := fresh && find()
+	c.mutex.RUnlock()
+
+	if found {
+		return nil
+	}
+
+	if err := c.refresh(fresh); err != nil {
+		return err
+	}
+
+	c.mutex.RLock()
+	find()
+	c.mutex.RUnlock()
+
+	return nil
+}
+
+// Has to be called with c.mutex locked.
+func (c *InterfaceCache) isFresh() bool {
+	return c.valid && (c.maxStaleness <= 0 || c.clock.Now().Sub(c.refreshedAt) <= c.maxStaleness)
+}
+
+// Refreshes the cache. The refresh is skipped if the cache is fresh (i.e. because another goroutine has refreshed it in
+// the meantime), unless 'force' is true and the cache is older than the miss refresh interval.
+func (c *InterfaceCache) refresh(force bool) error {
+
+	c.refreshMutex.Lock()
+	defer c.refreshMutex.Unlock()
+
+	c.mutex.RLock()
+	fresh := c.isFresh()
+	recent := c.clock.Now().Sub(c.refreshedAt) < c.missRefreshInterval
+	generation := c.generation
+	c.mutex.RUnlock()
+
+	if fresh && (!force || recent) {
+		return nil
+	}
+
+	ifcs, err := c.getInterfaces()
+
+	if err != nil {
+		return err
+	}
+
+	byLuid := make(map[uint64]*Interface, len(ifcs))
+	byIndex := make(map[uint32]*Interface, len(ifcs))
+	byFriendlyName := make(map[string]*Interface, len(ifcs))
+	byGuid := make(map[GUID]*Interface, len(ifcs))
+
+	for _, ifc := range ifcs {
+
+		byLuid[ifc.Luid] = ifc
+
+		// The same fallback InterfaceFromIndexEx() does for interfaces without IPv4.
+		if ifc.Index != 0 {
+			byIndex[ifc.Index] = ifc
+		} else if ifc.Ipv6IfIndex != 0 {
+			byIndex[ifc.Ipv6IfIndex] = ifc
+		}
+
+		if _, ok := byFriendlyName[ifc.FriendlyName]; !ok {
+			byFriendlyName[ifc.FriendlyName] = ifc
+		}
+
+		if guid, err := ParseGUID(ifc.AdapterName); err == nil {
+			byGuid[guid] = ifc
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.interfaces = ifcs
+	c.byLuid = byLuid
+	c.byIndex = byIndex
+	c.byFriendlyName = byFriendlyName
+	c.byGuid = byGuid
+	c.refreshedAt = c.clock.Now()
+
+	// If the cache has been invalidated while the interfaces were being gotten, they may already be outdated.
+	c.valid = c.generation == generation
+
+	return nil
+}
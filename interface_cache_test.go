@@ -0,0 +1,278 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func interfaceCacheTestInterfaces(count int) []*Interface {
+
+	ifcs := make([]*Interface, count)
+
+	for i := range ifcs {
+		ifcs[i] = &Interface{
+			Luid:         uint64(i+1) << 24,
+			Index:        uint32(i + 1),
+			AdapterName:  fmt.Sprintf("{%08X-0000-0000-0000-000000000000}", i+1),
+			FriendlyName: fmt.Sprintf("vEthernet (Switch %d)", i+1),
+		}
+	}
+
+	return ifcs
+}
+
+// Returns cache backed by 'ifcs' (which can be replaced while the cache is in use) and the counter of its refreshes.
+func newInterfaceCacheForTest(maxStaleness time.Duration, clock Clock, ifcs *[]*Interface) (*InterfaceCache, *int32) {
+
+	cache := NewInterfaceCache(nil, maxStaleness, clock)
+	refreshes := new(int32)
+	mutex := &sync.Mutex{}
+
+	cache.getInterfaces = func() ([]*Interface, error) {
+		atomic.AddInt32(refreshes, 1)
+		mutex.Lock()
+		defer mutex.Unlock()
+		return *ifcs, nil
+	}
+
+	return cache, refreshes
+}
+
+func TestInterfaceCache(t *testing.T) {
+
+	clock := newFakeClock()
+	ifcs := interfaceCacheTestInterfaces(3)
+	cache, refreshes := newInterfaceCacheForTest(time.Minute, clock, &ifcs)
+
+	ifc, err := cache.InterfaceFromLUID(2 << 24)
+
+	if err != nil || ifc != ifcs[1] {
+		t.Errorf("InterfaceFromLUID() returned (%v, %v).", ifc, err)
+	}
+
+	ifc, err = cache.InterfaceFromIndex(3)
+
+	if err != nil || ifc != ifcs[2] {
+		t.Errorf("InterfaceFromIndex() returned (%v, %v).", ifc, err)
+	}
+
+	ifc, err = cache.InterfaceFromFriendlyName("vEthernet (Switch 1)")
+
+	if err != nil || ifc != ifcs[0] {
+		t.Errorf("InterfaceFromFriendlyName() returned (%v, %v).", ifc, err)
+	}
+
+	ifc, err = cache.InterfaceFromGUID(&GUID{Data1: 2})
+
+	if err != nil || ifc != ifcs[1] {
+		t.Errorf("InterfaceFromGUID() returned (%v, %v).", ifc, err)
+	}
+
+	all, err := cache.Interfaces()
+
+	if err != nil || len(all) != 3 {
+		t.Errorf("Interfaces() returned (%v, %v).", all, err)
+	}
+
+	if *refreshes != 1 {
+		t.Errorf("Lookups of a fresh cache caused %d refreshes instead of 1.", *refreshes)
+	}
+
+	// Misses refresh the cache, since the interface may have appeared before its notification arrived, but not more
+	// often than once per InterfaceCacheMissRefreshInterval.
+	ifcs = interfaceCacheTestInterfaces(4)
+
+	if _, err = cache.InterfaceFromIndex(4); err == nil || *refreshes != 1 {
+		t.Errorf("Miss right after a refresh refreshed the cache (refreshes: %d).", *refreshes)
+	}
+
+	clock.advance(InterfaceCacheMissRefreshInterval)
+
+	if ifc, err = cache.InterfaceFromIndex(4); err != nil || ifc != ifcs[3] {
+		t.Errorf("InterfaceFromIndex() of a new interface returned (%v, %v).", ifc, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err = cache.InterfaceFromIndex(5); err == nil {
+			t.Error("InterfaceFromIndex() of a nonexistent interface should have failed.")
+		}
+	}
+
+	if *refreshes != 2 {
+		t.Errorf("Misses caused %d refreshes instead of 1.", *refreshes-1)
+	}
+
+	clock.advance(InterfaceCacheMissRefreshInterval)
+
+	if _, err = cache.InterfaceFromIndex(5); err == nil || *refreshes != 3 {
+		t.Errorf("Miss after the miss refresh interval didn't refresh the cache (refreshes: %d).", *refreshes)
+	}
+
+	// Notifications invalidate the cache.
+	cache.interfaceChanged(MibDeleteInstance, 1<<24)
+	ifcs = ifcs[1:]
+
+	if _, err = cache.InterfaceFromLUID(2 << 24); err != nil || *refreshes != 4 {
+		t.Errorf("Interface change notification didn't invalidate the cache (refreshes: %d).", *refreshes)
+	}
+
+	if _, err = cache.InterfaceFromLUID(1 << 24); err == nil {
+		t.Error("InterfaceFromLUID() of a removed interface should have failed.")
+	}
+
+	cache.unicastAddressChanged(MibAddInstance, 2<<24, nil)
+
+	if _, err = cache.InterfaceFromLUID(2 << 24); err != nil || *refreshes != 5 {
+		t.Errorf("Unicast address change notification didn't invalidate the cache (refreshes: %d).", *refreshes)
+	}
+
+	// Max staleness bounds the age of the content, even without notifications.
+	clock.advance(time.Minute)
+
+	cache.InterfaceFromLUID(2 << 24)
+
+	if *refreshes != 5 {
+		t.Error("Cache which isn't older than max staleness has been refreshed.")
+	}
+
+	clock.advance(time.Second)
+
+	cache.InterfaceFromLUID(2 << 24)
+
+	if *refreshes != 6 {
+		t.Error("Cache older than max staleness hasn't been refreshed.")
+	}
+}
+
+func TestInterfaceCacheInvalidatedDuringRefresh(t *testing.T) {
+
+	ifcs := interfaceCacheTestInterfaces(1)
+	cache, refreshes := newInterfaceCacheForTest(0, newFakeClock(), &ifcs)
+	getInterfaces := cache.getInterfaces
+
+	cache.getInterfaces = func() ([]*Interface, error) {
+		// A notification arriving while the interfaces are being gotten.
+		cache.Invalidate()
+		return getInterfaces()
+	}
+
+	if _, err := cache.InterfaceFromIndex(1); err != nil {
+		t.Errorf("InterfaceFromIndex() returned an error: %v", err)
+	}
+
+	cache.getInterfaces = getInterfaces
+
+	cache.InterfaceFromIndex(1)
+	cache.InterfaceFromIndex(1)
+
+	if *refreshes != 2 {
+		t.Errorf("Cache invalidated during a refresh has been refreshed %d times instead of 2.", *refreshes)
+	}
+}
+
+func TestInterfaceCacheConcurrency(t *testing.T) {
+
+	ifcs := interfaceCacheTestInterfaces(16)
+	cache, refreshes := newInterfaceCacheForTest(0, SystemClock, &ifcs)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 8; i++ {
+
+		wg.Add(1)
+
+		go func(i int) {
+
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+
+				if j%100 == 0 && i == 0 {
+					cache.Invalidate()
+				}
+
+				index := uint32(j%16 + 1)
+
+				if ifc, err := cache.InterfaceFromIndex(index); err != nil || ifc.Index != index {
+					t.Errorf("InterfaceFromIndex(%d) returned (%v, %v).", index, ifc, err)
+					return
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	if *refreshes > 11 {
+		t.Errorf("Concurrent lookups caused %d refreshes, but the cache has been invalidated only 10 times.",
+			*refreshes)
+	}
+}
+
+const interfaceCacheBenchmarkInterfaces = 64
+
+func BenchmarkInterfaceCacheLookup(b *testing.B) {
+
+	ifcs := interfaceCacheTestInterfaces(interfaceCacheBenchmarkInterfaces)
+	cache, _ := newInterfaceCacheForTest(time.Minute, SystemClock, &ifcs)
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			cache.InterfaceFromIndex(uint32(i%interfaceCacheBenchmarkInterfaces + 1))
+			i++
+		}
+	})
+}
+
+// Baseline for BenchmarkInterfaceCacheLookup: uncached lookups of the actual interfaces.
+func BenchmarkInterfaceFromIndex(b *testing.B) {
+
+	ifcs, err := GetInterfaces()
+
+	if err != nil || len(ifcs) < 1 {
+		b.Skipf("GetInterfaces() returned (%v, %v).", ifcs, err)
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		InterfaceFromIndex(ifcs[i%len(ifcs)].Index)
+	}
+}
+
+func TestInterfaceCacheFlagsAndNilGUID(t *testing.T) {
+
+	flags := DefaultGetAdapterAddressesFlags()
+	flags.GAA_FLAG_SKIP_FRIENDLY_NAME = true
+
+	cache := NewInterfaceCache(flags, time.Minute, newFakeClock())
+
+	if cache.flags.GAA_FLAG_SKIP_FRIENDLY_NAME {
+		t.Error("NewInterfaceCache() kept GAA_FLAG_SKIP_FRIENDLY_NAME, which breaks lookups by friendly name.")
+	}
+
+	if !flags.GAA_FLAG_SKIP_FRIENDLY_NAME {
+		t.Error("NewInterfaceCache() modified the flags passed to it.")
+	}
+
+	ifcs := interfaceCacheTestInterfaces(1)
+	cache, _ = newInterfaceCacheForTest(time.Minute, newFakeClock(), &ifcs)
+
+	if ifc, err := cache.InterfaceFromGUID(nil); err == nil {
+		t.Errorf("InterfaceCache.InterfaceFromGUID(nil) returned %v instead of an error.", ifc)
+	}
+}
@@ -0,0 +1,56 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+// Category of an interface, as determined by Classify().
+type InterfaceCategory uint32
+
+const (
+	InterfaceCategoryUnknown    InterfaceCategory = 0
+	InterfaceCategoryPhysical   InterfaceCategory = 1
+	InterfaceCategoryVirtual    InterfaceCategory = 2
+	InterfaceCategoryTunnel     InterfaceCategory = 3
+	InterfaceCategoryLoopback   InterfaceCategory = 4
+	InterfaceCategoryFilter     InterfaceCategory = 5
+	InterfaceCategoryHypervisor InterfaceCategory = 6
+)
+
+var interfaceCategoryTable = &enumTable{
+	typeName:    "InterfaceCategory",
+	unknown:     "InterfaceCategory_UNKNOWN",
+	shortPrefix: "InterfaceCategory",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(InterfaceCategoryUnknown), "InterfaceCategoryUnknown"},
+		{int64(InterfaceCategoryPhysical), "InterfaceCategoryPhysical"},
+		{int64(InterfaceCategoryVirtual), "InterfaceCategoryVirtual"},
+		{int64(InterfaceCategoryTunnel), "InterfaceCategoryTunnel"},
+		{int64(InterfaceCategoryLoopback), "InterfaceCategoryLoopback"},
+		{int64(InterfaceCategoryFilter), "InterfaceCategoryFilter"},
+		{int64(InterfaceCategoryHypervisor), "InterfaceCategoryHypervisor"},
+	},
+}
+
+func (c InterfaceCategory) String() string {
+	return interfaceCategoryTable.format(int64(c))
+}
+
+func (c InterfaceCategory) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *InterfaceCategory) UnmarshalText(text []byte) error {
+
+	value, err := interfaceCategoryTable.parse(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*c = InterfaceCategory(value)
+
+	return nil
+}
@@ -0,0 +1,219 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Rule used by InterfaceClassifier.
+type ClassificationRule struct {
+	// Why the rule matched, i.e. "IfType is IF_TYPE_SOFTWARE_LOOPBACK"; listed in InterfaceClassification.Reasons.
+	Reason string
+
+	// Category the rule assigns, or InterfaceCategoryUnknown for rules which only add Product hint.
+	Category InterfaceCategory
+
+	// Known product the rule identifies, or empty string.
+	Product string
+
+	Predicate InterfacePredicate
+}
+
+// Result of Classify().
+type InterfaceClassification struct {
+	Category InterfaceCategory
+
+	// Reasons of all the matching rules. The reason of the rule which determined Category goes first (unless Category
+	// is InterfaceCategoryUnknown), followed by the others in the order of the rules.
+	Reasons []string
+
+	// Known products the interface belongs to, i.e. "WireGuard" or "Hyper-V vSwitch".
+	Products []string
+}
+
+func (ic *InterfaceClassification) String() string {
+	return fmt.Sprintf("%s (%s); products: [%s]", ic.Category.String(), strings.Join(ic.Reasons, "; "),
+		strings.Join(ic.Products, ", "))
+}
+
+// Classifies interfaces by an ordered list of rules. The first matching rule with a category determines the
+// interface's category; all the matching rules contribute their reasons and product hints.
+type InterfaceClassifier struct {
+	rules []*ClassificationRule
+}
+
+// Creates new InterfaceClassifier using 'rules'. To extend the default rules, use i.e.
+// append(myRules, DefaultClassificationRules()...) for rules taking precedence over the default ones.
+func NewInterfaceClassifier(rules []*ClassificationRule) *InterfaceClassifier {
+	return &InterfaceClassifier{rules: rules}
+}
+
+// Predicate satisfied if the interface has IfRow and 'predicate' is satisfied by it.
+func ifRowIs(predicate func(ifrow *IfRow) bool) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return ifrow != nil && predicate(ifrow)
+	}
+}
+
+// Returns predicate satisfied by interfaces whose physical address is locally administered (the second least
+// significant bit of the first octet is set), as opposed to one assigned by the manufacturer. Virtual adapters
+// typically have such addresses, but so do Wi-Fi adapters using MAC address randomization.
+func HasLocallyAdministeredAddress() InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return len(ifc.PhysicalAddress) > 0 && ifc.PhysicalAddress[0]&0x02 != 0
+	}
+}
+
+// Returns the default classification rules. Each call returns new instances, which the caller is free to modify.
+func DefaultClassificationRules() []*ClassificationRule {
+	return []*ClassificationRule{
+		{
+			Reason:    "IfType is IF_TYPE_SOFTWARE_LOOPBACK",
+			Category:  InterfaceCategoryLoopback,
+			Predicate: IfTypeIs(IF_TYPE_SOFTWARE_LOOPBACK),
+		},
+		{
+			Reason:    "description matches Npcap Loopback Adapter",
+			Category:  InterfaceCategoryLoopback,
+			Product:   "Npcap loopback",
+			Predicate: DescriptionMatches("Npcap Loopback Adapter*"),
+		},
+		{
+			Reason:    "description matches WireGuard Tunnel",
+			Category:  InterfaceCategoryTunnel,
+			Product:   "WireGuard",
+			Predicate: DescriptionMatches("WireGuard Tunnel*"),
+		},
+		{
+			Reason:    "description matches Wintun or WireGuard Tunnel (WireGuard adapters are Wintun adapters)",
+			Category:  InterfaceCategoryTunnel,
+			Product:   "Wintun",
+			Predicate: AnyOf(DescriptionMatches("Wintun*"), DescriptionMatches("WireGuard Tunnel*")),
+		},
+		{
+			Reason:    "description matches TAP-Windows Adapter",
+			Category:  InterfaceCategoryTunnel,
+			Product:   "TAP-Windows",
+			Predicate: AnyOf(DescriptionMatches("TAP-Windows Adapter*"), DescriptionMatches("TAP-Win32 Adapter*")),
+		},
+		{
+			Reason:    "description matches Hyper-V Virtual Ethernet Adapter",
+			Category:  InterfaceCategoryHypervisor,
+			Product:   "Hyper-V vSwitch",
+			Predicate: DescriptionMatches("Hyper-V Virtual Ethernet Adapter*"),
+		},
+		{
+			Reason:   "InterfaceAndOperStatusFlags.FilterInterface is set",
+			Category: InterfaceCategoryFilter,
+			Predicate: ifRowIs(func(ifrow *IfRow) bool {
+				return ifrow.InterfaceAndOperStatusFlags.FilterInterface
+			}),
+		},
+		{
+			Reason:    "IfType is IF_TYPE_TUNNEL or TunnelType isn't TUNNEL_TYPE_NONE",
+			Category:  InterfaceCategoryTunnel,
+			Predicate: IsTunnel(),
+		},
+		{
+			Reason:   "InterfaceAndOperStatusFlags.EndPointInterface is set",
+			Category: InterfaceCategoryVirtual,
+			Predicate: ifRowIs(func(ifrow *IfRow) bool {
+				return ifrow.InterfaceAndOperStatusFlags.EndPointInterface
+			}),
+		},
+		{
+			Reason:   "InterfaceAndOperStatusFlags.HardwareInterface and ConnectorPresent are set",
+			Category: InterfaceCategoryPhysical,
+			Predicate: ifRowIs(func(ifrow *IfRow) bool {
+				return ifrow.InterfaceAndOperStatusFlags.HardwareInterface &&
+					ifrow.InterfaceAndOperStatusFlags.ConnectorPresent
+			}),
+		},
+		{
+			Reason:    "IfType is IF_TYPE_PROP_VIRTUAL",
+			Category:  InterfaceCategoryVirtual,
+			Predicate: IfTypeIs(IF_TYPE_PROP_VIRTUAL),
+		},
+		{
+			Reason:    "physical address is locally administered",
+			Category:  InterfaceCategoryVirtual,
+			Predicate: AllOf(HasLocallyAdministeredAddress(), Negate(IsHardwareInterface())),
+		},
+		{
+			Reason:   "InterfaceAndOperStatusFlags.HardwareInterface isn't set",
+			Category: InterfaceCategoryVirtual,
+			Predicate: ifRowIs(func(ifrow *IfRow) bool {
+				return !ifrow.InterfaceAndOperStatusFlags.HardwareInterface
+			}),
+		},
+		{
+			Reason:   "physical medium is NdisPhysicalMedium802_3 or NdisPhysicalMediumNative802_11",
+			Category: InterfaceCategoryPhysical,
+			Predicate: ifRowIs(func(ifrow *IfRow) bool {
+				return ifrow.PhysicalMediumType == NdisPhysicalMedium802_3 ||
+					ifrow.PhysicalMediumType == NdisPhysicalMediumNative802_11
+			}),
+		},
+	}
+}
+
+var defaultInterfaceClassifier = NewInterfaceClassifier(DefaultClassificationRules())
+
+// Classifies interface 'ifc' by the default rules (see DefaultClassificationRules()). Argument 'ifrow' is the IfRow
+// struct of the same interface, or nil; without it, only rules based on Interface struct can match.
+func Classify(ifc *Interface, ifrow *IfRow) *InterfaceClassification {
+	return defaultInterfaceClassifier.Classify(ifc, ifrow)
+}
+
+// Classifies interface 'ifc' by the classifier's rules. See Classify().
+func (c *InterfaceClassifier) Classify(ifc *Interface, ifrow *IfRow) *InterfaceClassification {
+
+	classification := &InterfaceClassification{
+		Category: InterfaceCategoryUnknown,
+		Reasons:  make([]string, 0),
+		Products: make([]string, 0),
+	}
+
+	decided := false
+
+	for _, rule := range c.rules {
+
+		if !rule.Predicate(ifc, ifrow) {
+			continue
+		}
+
+		if rule.Category != InterfaceCategoryUnknown && !decided {
+			classification.Category = rule.Category
+			decided = true
+			// The deciding reason goes first.
+			classification.Reasons = append([]string{rule.Reason}, classification.Reasons...)
+		} else {
+			classification.Reasons = append(classification.Reasons, rule.Reason)
+		}
+
+		if rule.Product != "" && !containsString(classification.Products, rule.Product) {
+			classification.Products = append(classification.Products, rule.Product)
+		}
+	}
+
+	return classification
+}
+
+func containsString(values []string, value string) bool {
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
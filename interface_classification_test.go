@@ -0,0 +1,153 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+
+	hardware := InterfaceAndOperStatusFlags{HardwareInterface: true, ConnectorPresent: true}
+
+	tests := []struct {
+		name     string
+		ifc      *Interface
+		ifrow    *IfRow
+		category InterfaceCategory
+		products []string
+	}{
+		{
+			"loopback",
+			&Interface{IfType: IF_TYPE_SOFTWARE_LOOPBACK, Description: "Software Loopback Interface 1"},
+			nil,
+			InterfaceCategoryLoopback, []string{},
+		},
+		{
+			"ethernet",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "Intel(R) Ethernet Connection I219-V",
+				PhysicalAddress: net.HardwareAddr{0x00, 0x1b, 0x21, 0x01, 0x02, 0x03}},
+			&IfRow{InterfaceAndOperStatusFlags: hardware, PhysicalMediumType: NdisPhysicalMedium802_3},
+			InterfaceCategoryPhysical, []string{},
+		},
+		{
+			"Wi-Fi with randomized MAC address",
+			&Interface{IfType: IF_TYPE_IEEE80211, Description: "Intel(R) Wi-Fi 6 AX201 160MHz",
+				PhysicalAddress: net.HardwareAddr{0x06, 0x1b, 0x21, 0x01, 0x02, 0x03}},
+			&IfRow{InterfaceAndOperStatusFlags: hardware, PhysicalMediumType: NdisPhysicalMediumNative802_11},
+			InterfaceCategoryPhysical, []string{},
+		},
+		{
+			"WireGuard",
+			&Interface{IfType: IF_TYPE_PROP_VIRTUAL, Description: "WireGuard Tunnel #2"},
+			&IfRow{},
+			InterfaceCategoryTunnel, []string{"WireGuard", "Wintun"},
+		},
+		{
+			"TAP-Windows",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "TAP-Windows Adapter V9",
+				PhysicalAddress: net.HardwareAddr{0x00, 0xff, 0x01, 0x02, 0x03, 0x04}},
+			&IfRow{InterfaceAndOperStatusFlags: InterfaceAndOperStatusFlags{ConnectorPresent: true}},
+			InterfaceCategoryTunnel, []string{"TAP-Windows"},
+		},
+		{
+			"Hyper-V vSwitch",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "Hyper-V Virtual Ethernet Adapter #2"},
+			&IfRow{},
+			InterfaceCategoryHypervisor, []string{"Hyper-V vSwitch"},
+		},
+		{
+			"Npcap loopback",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "Npcap Loopback Adapter"},
+			&IfRow{},
+			InterfaceCategoryLoopback, []string{"Npcap loopback"},
+		},
+		{
+			"filter",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "Intel(R) Ethernet-WFP Native MAC Layer LightWeight Filter-0000"},
+			&IfRow{InterfaceAndOperStatusFlags: InterfaceAndOperStatusFlags{FilterInterface: true}},
+			InterfaceCategoryFilter, []string{},
+		},
+		{
+			"Teredo",
+			&Interface{IfType: IF_TYPE_TUNNEL, TunnelType: TUNNEL_TYPE_TEREDO, Description: "Teredo Tunneling Pseudo-Interface"},
+			&IfRow{},
+			InterfaceCategoryTunnel, []string{},
+		},
+		{
+			"locally administered address without IfRow",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "Some Virtual Adapter",
+				PhysicalAddress: net.HardwareAddr{0x02, 0x50, 0xf2, 0x00, 0x00, 0x01}},
+			nil,
+			InterfaceCategoryVirtual, []string{},
+		},
+		{
+			"no evidence",
+			&Interface{IfType: IF_TYPE_ETHERNET_CSMACD, Description: "Some Adapter"},
+			nil,
+			InterfaceCategoryUnknown, []string{},
+		},
+	}
+
+	for _, test := range tests {
+
+		classification := Classify(test.ifc, test.ifrow)
+
+		if classification.Category != test.category || !reflect.DeepEqual(classification.Products, test.products) {
+			t.Errorf("Classify() returned %s for %s instead of %s with products %v.", classification.String(),
+				test.name, test.category.String(), test.products)
+		}
+
+		if test.category != InterfaceCategoryUnknown && len(classification.Reasons) == 0 {
+			t.Errorf("Classify() returned no reasons for %s.", test.name)
+		}
+	}
+}
+
+func TestInterfaceClassifierCustomRules(t *testing.T) {
+
+	rules := append([]*ClassificationRule{
+		{
+			Reason:    "description matches Tailscale Tunnel",
+			Category:  InterfaceCategoryTunnel,
+			Product:   "Tailscale",
+			Predicate: DescriptionMatches("Tailscale Tunnel*"),
+		},
+		{
+			Reason:    "friendly name matches vEthernet",
+			Product:   "Hyper-V vSwitch",
+			Predicate: FriendlyNameMatches("vEthernet (*)"),
+		},
+	}, DefaultClassificationRules()...)
+
+	classifier := NewInterfaceClassifier(rules)
+
+	ifc := &Interface{IfType: IF_TYPE_PROP_VIRTUAL, Description: "Tailscale Tunnel"}
+
+	if classification := classifier.Classify(ifc, &IfRow{}); classification.Category != InterfaceCategoryTunnel ||
+		!reflect.DeepEqual(classification.Products, []string{"Tailscale"}) ||
+		classification.Reasons[0] != "description matches Tailscale Tunnel" {
+		t.Errorf("InterfaceClassifier.Classify() returned %s.", classification.String())
+	}
+
+	// Rule without category only adds a product hint; the category is decided by a later rule.
+	ifc = &Interface{IfType: IF_TYPE_ETHERNET_CSMACD, FriendlyName: "vEthernet (Default Switch)",
+		Description: "Hyper-V Virtual Ethernet Adapter"}
+
+	classification := classifier.Classify(ifc, &IfRow{})
+
+	if classification.Category != InterfaceCategoryHypervisor ||
+		!reflect.DeepEqual(classification.Products, []string{"Hyper-V vSwitch"}) ||
+		classification.Reasons[0] != "description matches Hyper-V Virtual Ethernet Adapter" ||
+		classification.Reasons[1] != "friendly name matches vEthernet" {
+		t.Errorf("InterfaceClassifier.Classify() returned %s.", classification.String())
+	}
+}
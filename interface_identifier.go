@@ -0,0 +1,205 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"net"
+)
+
+// 64-bit IPv6 interface identifier (https://tools.ietf.org/html/rfc4291#section-2.5.1).
+type InterfaceIdentifier [8]byte
+
+const (
+	// Universal/local bit of the first interface identifier byte.
+	interfaceIdentifierUniversalLocalBit = 0x02
+
+	// Maximal number of DAD_Counter values tried by StablePrivacyInterfaceIdentifier() before giving up.
+	stablePrivacyMaxDadCounterRetries = 16
+)
+
+// Returns modified EUI-64 interface identifier (https://tools.ietf.org/html/rfc4291#appendix-A) created from
+// 'physicalAddress', which has to be either a 48-bit MAC address or a 64-bit EUI.
+func ModifiedEUI64InterfaceIdentifier(physicalAddress net.HardwareAddr) (InterfaceIdentifier, error) {
+
+	iid := InterfaceIdentifier{}
+
+	switch len(physicalAddress) {
+	case 6:
+		copy(iid[0:3], physicalAddress[0:3])
+		iid[3] = 0xff
+		iid[4] = 0xfe
+		copy(iid[5:8], physicalAddress[3:6])
+	case 8:
+		copy(iid[:], physicalAddress)
+	default:
+		return iid, fmt.Errorf("ModifiedEUI64InterfaceIdentifier() - physical address has to be 6 or 8 bytes long")
+	}
+
+	iid[0] ^= interfaceIdentifierUniversalLocalBit
+
+	return iid, nil
+}
+
+// Returns modified EUI-64 interface identifier created from the interface's PhysicalAddress.
+func (ifc *Interface) ModifiedEUI64InterfaceIdentifier() (InterfaceIdentifier, error) {
+	return ModifiedEUI64InterfaceIdentifier(ifc.PhysicalAddress)
+}
+
+// Returns a stable, semantically opaque interface identifier as described in RFC 7217
+// (https://tools.ietf.org/html/rfc7217#section-5). SHA-256 is used as the pseudorandom function F() over the first 64
+// bits of 'prefix', 'netIface' (a stable identifier of the interface), optional 'networkId', 'dadCounter' and
+// 'secretKey', and the interface identifier is taken from the least significant bits of the result. If the result is
+// a reserved interface identifier (RFC 5453), DAD_Counter is incremented and the identifier is computed again.
+func StablePrivacyInterfaceIdentifier(prefix *net.IPNet, netIface, networkId []byte, dadCounter uint8,
+	secretKey []byte) (InterfaceIdentifier, error) {
+
+	iid := InterfaceIdentifier{}
+
+	if prefix == nil || len(prefix.IP.To16()) != net.IPv6len || prefix.IP.To4() != nil {
+		return iid, fmt.Errorf("StablePrivacyInterfaceIdentifier() - prefix has to be an IPv6 prefix")
+	}
+
+	if len(secretKey) < 16 {
+		return iid, fmt.Errorf("StablePrivacyInterfaceIdentifier() - secret key has to be at least 128 bits long")
+	}
+
+	prefixBits := prefix.IP.To16().Mask(prefix.Mask)[:8]
+
+	for i := 0; i < stablePrivacyMaxDadCounterRetries; i++ {
+
+		h := sha256.New()
+		h.Write(prefixBits)
+		h.Write(netIface)
+		h.Write(networkId)
+		h.Write([]byte{dadCounter})
+		h.Write(secretKey)
+
+		copy(iid[:], h.Sum(nil)[sha256.Size-8:])
+
+		if !iid.IsReserved() {
+			return iid, nil
+		}
+
+		dadCounter++
+	}
+
+	return InterfaceIdentifier{}, fmt.Errorf("StablePrivacyInterfaceIdentifier() - failed to generate a non-reserved identifier")
+}
+
+// Returns RFC 7217 stable interface identifier for 'prefix' on this interface. The interface's AdapterName (its
+// GUID) is used as Net_Iface and NetworkGuid as Network_ID.
+func (ifc *Interface) StablePrivacyInterfaceIdentifier(prefix *net.IPNet, dadCounter uint8,
+	secretKey []byte) (InterfaceIdentifier, error) {
+
+	networkId := make([]byte, 16)
+
+	binary.LittleEndian.PutUint32(networkId[0:4], ifc.NetworkGuid.Data1)
+	binary.LittleEndian.PutUint16(networkId[4:6], ifc.NetworkGuid.Data2)
+	binary.LittleEndian.PutUint16(networkId[6:8], ifc.NetworkGuid.Data3)
+	copy(networkId[8:16], ifc.NetworkGuid.Data4[:])
+
+	return StablePrivacyInterfaceIdentifier(prefix, []byte(ifc.AdapterName), networkId, dadCounter, secretKey)
+}
+
+// Returns a temporary interface identifier generated from 'iid' and 'history' as described in RFC 4941
+// (https://tools.ietf.org/html/rfc4941#section-3.2.1), together with the history value which should be used for the
+// next invocation. Initial history value should be gotten from RandomInterfaceIdentifierHistory() function.
+func TemporaryInterfaceIdentifier(iid InterfaceIdentifier, history [8]byte) (InterfaceIdentifier, [8]byte) {
+
+	for {
+
+		input := make([]byte, 0, 16)
+		input = append(input, history[:]...)
+		input = append(input, iid[:]...)
+
+		digest := md5.Sum(input)
+
+		temporary := InterfaceIdentifier{}
+		copy(temporary[:], digest[0:8])
+		temporary[0] &^= interfaceIdentifierUniversalLocalBit
+
+		copy(history[:], digest[8:16])
+
+		if !temporary.IsReserved() && temporary != iid {
+			return temporary, history
+		}
+	}
+}
+
+// Returns a random initial history value for TemporaryInterfaceIdentifier() function.
+func RandomInterfaceIdentifierHistory() ([8]byte, error) {
+
+	history := [8]byte{}
+
+	_, err := rand.Read(history[:])
+
+	return history, err
+}
+
+// Returns true if the interface identifier is reserved according to RFC 5453
+// (https://tools.ietf.org/html/rfc5453#section-3), meaning that it mustn't be used for unicast addresses.
+func (iid InterfaceIdentifier) IsReserved() bool {
+
+	value := binary.BigEndian.Uint64(iid[:])
+
+	switch {
+	case value == 0:
+		// Subnet-Router Anycast (RFC 4291).
+		return true
+	case value >= 0xfdffffffffffff80 && value <= 0xfdffffffffffffff:
+		// Reserved Subnet Anycast Addresses (RFC 2526).
+		return true
+	case value >= 0x02005efffe000000 && value <= 0x02005efffeffffff:
+		// Reserved IPv6 Interface Identifiers, including Proxy Mobile IPv6 (RFC 6543).
+		return true
+	default:
+		return false
+	}
+}
+
+// Returns IPv6 address made of the first 64 bits of 'prefix' and 'iid', with the mask of 'prefix', ready to be used
+// with Interface.AddAddresses() method.
+func IPNetWithInterfaceIdentifier(prefix *net.IPNet, iid InterfaceIdentifier) (*net.IPNet, error) {
+
+	if prefix == nil || prefix.IP.To4() != nil || len(prefix.IP.To16()) != net.IPv6len {
+		return nil, fmt.Errorf("IPNetWithInterfaceIdentifier() - prefix has to be an IPv6 prefix")
+	}
+
+	ones, bits := prefix.Mask.Size()
+
+	if bits != 128 || ones > 64 {
+		return nil, fmt.Errorf("IPNetWithInterfaceIdentifier() - prefix length has to be 64 or shorter")
+	}
+
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, prefix.IP.To16().Mask(prefix.Mask))
+	copy(ip[8:], iid[:])
+
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(ones, 128)}, nil
+}
+
+// Returns IPv6 address made of the adapter's prefix and 'iid'. See IPNetWithInterfaceIdentifier() for details.
+func (ap *IpAdapterPrefix) IPNetWithInterfaceIdentifier(iid InterfaceIdentifier) (*net.IPNet, error) {
+
+	if ap == nil {
+		return nil, fmt.Errorf("IpAdapterPrefix.IPNetWithInterfaceIdentifier() - receiver argument is nil")
+	}
+
+	return IPNetWithInterfaceIdentifier(&net.IPNet{
+		IP:   ap.Address.Address,
+		Mask: net.CIDRMask(int(ap.PrefixLength), 128),
+	}, iid)
+}
+
+func (iid InterfaceIdentifier) String() string {
+	return fmt.Sprintf("%x:%x:%x:%x", binary.BigEndian.Uint16(iid[0:2]), binary.BigEndian.Uint16(iid[2:4]),
+		binary.BigEndian.Uint16(iid[4:6]), binary.BigEndian.Uint16(iid[6:8]))
+}
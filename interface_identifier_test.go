@@ -0,0 +1,165 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bytes"
+	"net"
+	"testing"
+)
+
+var interfaceIdentifier_secretKey = []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b,
+	0x0c, 0x0d, 0x0e, 0x0f}
+
+func TestModifiedEUI64InterfaceIdentifier(t *testing.T) {
+
+	vectors := []struct {
+		physicalAddress net.HardwareAddr
+		expected        string
+	}{
+		// RFC 2464, section 4.
+		{net.HardwareAddr{0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde}, "3656:78ff:fe9a:bcde"},
+		// Locally administered MAC address gets the universal/local bit cleared.
+		{net.HardwareAddr{0x02, 0x00, 0x5e, 0x10, 0x00, 0x01}, "0:5eff:fe10:1"},
+		// 64-bit EUI only gets the universal/local bit inverted (RFC 4291, appendix A).
+		{net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55, 0x66, 0x77}, "211:2233:4455:6677"},
+	}
+
+	for _, vector := range vectors {
+
+		iid, err := ModifiedEUI64InterfaceIdentifier(vector.physicalAddress)
+
+		if err != nil {
+			t.Errorf("ModifiedEUI64InterfaceIdentifier(%s) returned an error: %v", vector.physicalAddress, err)
+		} else if iid.String() != vector.expected {
+			t.Errorf("ModifiedEUI64InterfaceIdentifier(%s) returned %s although %s is expected.",
+				vector.physicalAddress, iid.String(), vector.expected)
+		}
+	}
+
+	if _, err := ModifiedEUI64InterfaceIdentifier(net.HardwareAddr{1, 2, 3}); err == nil {
+		t.Error("ModifiedEUI64InterfaceIdentifier() didn't return an error for a 3 bytes long address.")
+	}
+}
+
+// RFC 7217 doesn't publish test vectors, so the expected values below have been computed independently as the least
+// significant 64 bits of SHA-256(Prefix | Net_Iface | Network_ID | DAD_Counter | secret_key).
+func TestStablePrivacyInterfaceIdentifier(t *testing.T) {
+
+	_, prefix, _ := net.ParseCIDR("2001:db8:1::/64")
+
+	iid, err := StablePrivacyInterfaceIdentifier(prefix, []byte("eth0"), nil, 0, interfaceIdentifier_secretKey)
+
+	if err != nil {
+		t.Fatalf("StablePrivacyInterfaceIdentifier() returned an error: %v", err)
+	}
+
+	if iid.String() != "fdc8:c6b8:b415:2fce" {
+		t.Errorf("StablePrivacyInterfaceIdentifier() returned %s although fdc8:c6b8:b415:2fce is expected.",
+			iid.String())
+	}
+
+	iid, err = StablePrivacyInterfaceIdentifier(prefix, []byte("eth0"), []byte("home"), 0,
+		interfaceIdentifier_secretKey)
+
+	if err != nil {
+		t.Fatalf("StablePrivacyInterfaceIdentifier() returned an error: %v", err)
+	}
+
+	if iid.String() != "b98a:c1c4:478e:9730" {
+		t.Errorf("StablePrivacyInterfaceIdentifier() returned %s although b98a:c1c4:478e:9730 is expected.",
+			iid.String())
+	}
+
+	other, _ := StablePrivacyInterfaceIdentifier(prefix, []byte("eth0"), []byte("home"), 1,
+		interfaceIdentifier_secretKey)
+
+	if other == iid {
+		t.Error("StablePrivacyInterfaceIdentifier() returned the same identifier for different DAD counters.")
+	}
+
+	if _, err = StablePrivacyInterfaceIdentifier(prefix, []byte("eth0"), nil, 0, []byte{1, 2, 3}); err == nil {
+		t.Error("StablePrivacyInterfaceIdentifier() didn't return an error for a too short secret key.")
+	}
+}
+
+// RFC 4941 doesn't publish test vectors, so the expected values below have been computed independently by following
+// the algorithm from section 3.2.1.
+func TestTemporaryInterfaceIdentifier(t *testing.T) {
+
+	iid := InterfaceIdentifier{0x36, 0x56, 0x78, 0xff, 0xfe, 0x9a, 0xbc, 0xde}
+	history := [8]byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07}
+
+	temporary, history := TemporaryInterfaceIdentifier(iid, history)
+
+	if temporary.String() != "8046:c9d5:c61:24fb" {
+		t.Errorf("TemporaryInterfaceIdentifier() returned %s although 8046:c9d5:c61:24fb is expected.",
+			temporary.String())
+	}
+
+	if !bytes.Equal(history[:], []byte{0xe6, 0xca, 0xc8, 0x9d, 0x10, 0x73, 0x3b, 0x4d}) {
+		t.Errorf("TemporaryInterfaceIdentifier() returned history %x although e6cac89d10733b4d is expected.",
+			history)
+	}
+
+	temporary, _ = TemporaryInterfaceIdentifier(iid, history)
+
+	if temporary.String() != "850d:ac94:cf8f:6f2d" {
+		t.Errorf("TemporaryInterfaceIdentifier() returned %s although 850d:ac94:cf8f:6f2d is expected.",
+			temporary.String())
+	}
+}
+
+func TestInterfaceIdentifierIsReserved(t *testing.T) {
+
+	reserved := []InterfaceIdentifier{
+		{},
+		{0xfd, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x80},
+		{0x02, 0x00, 0x5e, 0xff, 0xfe, 0x00, 0x52, 0x13},
+	}
+
+	for _, iid := range reserved {
+		if !iid.IsReserved() {
+			t.Errorf("InterfaceIdentifier.IsReserved() returned false for %s.", iid.String())
+		}
+	}
+
+	notReserved := []InterfaceIdentifier{
+		{0xfd, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+		{0xfe, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01},
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+	}
+
+	for _, iid := range notReserved {
+		if iid.IsReserved() {
+			t.Errorf("InterfaceIdentifier.IsReserved() returned true for %s.", iid.String())
+		}
+	}
+}
+
+func TestIpAdapterPrefixIPNetWithInterfaceIdentifier(t *testing.T) {
+
+	prefix := IpAdapterPrefix{PrefixLength: 64}
+	prefix.Address = SockaddrInet{Family: AF_INET6, Address: net.ParseIP("2001:db8:1::")}
+
+	ipnet, err := prefix.IPNetWithInterfaceIdentifier(InterfaceIdentifier{0x36, 0x56, 0x78, 0xff, 0xfe, 0x9a, 0xbc,
+		0xde})
+
+	if err != nil {
+		t.Fatalf("IpAdapterPrefix.IPNetWithInterfaceIdentifier() returned an error: %v", err)
+	}
+
+	if ipnet.String() != "2001:db8:1:0:3656:78ff:fe9a:bcde/64" {
+		t.Errorf("IpAdapterPrefix.IPNetWithInterfaceIdentifier() returned %s although "+
+			"2001:db8:1:0:3656:78ff:fe9a:bcde/64 is expected.", ipnet.String())
+	}
+
+	prefix.PrefixLength = 96
+
+	if _, err = prefix.IPNetWithInterfaceIdentifier(InterfaceIdentifier{}); err == nil {
+		t.Error("IpAdapterPrefix.IPNetWithInterfaceIdentifier() didn't return an error for a /96 prefix.")
+	}
+}
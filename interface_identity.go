@@ -0,0 +1,238 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+)
+
+// Identifies an interface by properties which (unlike LUID, index and FriendlyName) survive reboots, and most of which
+// survive driver reinstalls as well. It can be persisted as JSON, and resolved back to the current Interface by
+// Resolve() method.
+type InterfaceIdentity struct {
+	InterfaceGuid            GUID
+	NetworkGuid              GUID
+	PermanentPhysicalAddress net.HardwareAddr
+	Description              string
+	IfType                   IfType
+}
+
+// Weights of the properties which InterfaceIdentity.Match() sums up. Their total is 100.
+const (
+	interfaceIdentityInterfaceGuidWeight   = 50
+	interfaceIdentityPhysicalAddressWeight = 30
+	interfaceIdentityNetworkGuidWeight     = 10
+	interfaceIdentityDescriptionWeight     = 7
+	interfaceIdentityIfTypeWeight          = 3
+)
+
+// Returns InterfaceIdentity of the interface described by 'ifrow'.
+func NewInterfaceIdentity(ifrow *IfRow) *InterfaceIdentity {
+	return &InterfaceIdentity{
+		InterfaceGuid:            ifrow.InterfaceGuid,
+		NetworkGuid:              ifrow.NetworkGuid,
+		PermanentPhysicalAddress: net.HardwareAddr(ifrow.PermanentPhysicalAddress),
+		Description:              ifrow.Description,
+		IfType:                   ifrow.Type,
+	}
+}
+
+// Returns InterfaceIdentity of the interface.
+func (ifc *Interface) Identity() (*InterfaceIdentity, error) {
+
+	ifrow, err := ifc.GetIfRow(MibIfEntryNormalWithoutStatistics)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInterfaceIdentity(ifrow), nil
+}
+
+// A candidate interface for an InterfaceIdentity, returned by InterfaceIdentity.Match() method.
+type InterfaceIdentityMatch struct {
+	IfRow *IfRow
+
+	// Value between 0 and 1; 1 means that all the properties are equal.
+	Confidence float64
+}
+
+// Returned by InterfaceIdentity.Resolve() method if more than one interface matches the identity equally well.
+type InterfaceIdentityAmbiguousError struct {
+	Candidates []*InterfaceIdentityMatch
+}
+
+func (e *InterfaceIdentityAmbiguousError) Error() string {
+
+	luids := make([]string, len(e.Candidates))
+
+	for i, candidate := range e.Candidates {
+		luids[i] = fmt.Sprintf("%d", candidate.IfRow.InterfaceLuid)
+	}
+
+	return fmt.Sprintf("InterfaceIdentity.Resolve() - identity is ambiguous; interfaces with LUIDs %s match it with "+
+		"confidence %.2f", strings.Join(luids, ", "), e.Candidates[0].Confidence)
+}
+
+// Returns interfaces from 'ifrows' which may be the one the identity describes, best match first. Interfaces of
+// different IfType never match, nor do interfaces that match by IfType alone. Properties which are zero in the
+// identity (i.e. NetworkGuid of an interface which isn't connected) are considered neither matching nor mismatching.
+func (identity *InterfaceIdentity) Match(ifrows []*IfRow) []*InterfaceIdentityMatch {
+
+	matches := make([]*InterfaceIdentityMatch, 0)
+
+	for _, ifrow := range ifrows {
+
+		if ifrow == nil || ifrow.Type != identity.IfType {
+			continue
+		}
+
+		score := interfaceIdentityIfTypeWeight
+
+		if !identity.InterfaceGuid.IsZero() && identity.InterfaceGuid == ifrow.InterfaceGuid {
+			score += interfaceIdentityInterfaceGuidWeight
+		}
+
+		if !allZeroBytes(identity.PermanentPhysicalAddress) &&
+			bytes.Equal(identity.PermanentPhysicalAddress, []byte(ifrow.PermanentPhysicalAddress)) {
+			score += interfaceIdentityPhysicalAddressWeight
+		}
+
+		if !identity.NetworkGuid.IsZero() && identity.NetworkGuid == ifrow.NetworkGuid {
+			score += interfaceIdentityNetworkGuidWeight
+		}
+
+		if identity.Description != "" && identity.Description == ifrow.Description {
+			score += interfaceIdentityDescriptionWeight
+		}
+
+		if score == interfaceIdentityIfTypeWeight {
+			continue
+		}
+
+		matches = append(matches, &InterfaceIdentityMatch{IfRow: ifrow, Confidence: float64(score) / 100})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Confidence > matches[j].Confidence })
+
+	return matches
+}
+
+// Returns the current interface the identity describes, and the confidence of the match. Only interfaces matching with
+// confidence of at least 'minConfidence' (see Match() method) are considered. If more than one interface has the best
+// confidence, returned error is *InterfaceIdentityAmbiguousError.
+func (identity *InterfaceIdentity) Resolve(minConfidence float64) (*Interface, float64, error) {
+
+	ifrows, err := GetIfRows(MibIfEntryNormalWithoutStatistics)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	best, err := identity.bestMatch(ifrows, minConfidence)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ifc, err := InterfaceFromLUID(best.IfRow.InterfaceLuid)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return ifc, best.Confidence, nil
+}
+
+func (identity *InterfaceIdentity) bestMatch(ifrows []*IfRow, minConfidence float64) (*InterfaceIdentityMatch, error) {
+
+	matches := identity.Match(ifrows)
+
+	if len(matches) < 1 || matches[0].Confidence < minConfidence {
+		return nil, fmt.Errorf("InterfaceIdentity.Resolve() - no interface matches the identity")
+	}
+
+	tied := 1
+
+	for tied < len(matches) && matches[tied].Confidence == matches[0].Confidence {
+		tied++
+	}
+
+	if tied > 1 {
+		return nil, &InterfaceIdentityAmbiguousError{Candidates: matches[:tied]}
+	}
+
+	return matches[0], nil
+}
+
+type interfaceIdentityJSON struct {
+	InterfaceGuid            GUID   `json:"interfaceGuid"`
+	NetworkGuid              GUID   `json:"networkGuid"`
+	PermanentPhysicalAddress string `json:"permanentPhysicalAddress"`
+	Description              string `json:"description"`
+	IfType                   IfType `json:"ifType"`
+}
+
+func (identity *InterfaceIdentity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&interfaceIdentityJSON{
+		InterfaceGuid:            identity.InterfaceGuid,
+		NetworkGuid:              identity.NetworkGuid,
+		PermanentPhysicalAddress: identity.PermanentPhysicalAddress.String(),
+		Description:              identity.Description,
+		IfType:                   identity.IfType,
+	})
+}
+
+func (identity *InterfaceIdentity) UnmarshalJSON(data []byte) error {
+
+	var ij interfaceIdentityJSON
+
+	if err := json.Unmarshal(data, &ij); err != nil {
+		return err
+	}
+
+	var physicalAddress net.HardwareAddr
+
+	if ij.PermanentPhysicalAddress != "" {
+
+		var err error
+
+		physicalAddress, err = parsePhysicalAddress(ij.PermanentPhysicalAddress)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	*identity = InterfaceIdentity{
+		InterfaceGuid:            ij.InterfaceGuid,
+		NetworkGuid:              ij.NetworkGuid,
+		PermanentPhysicalAddress: physicalAddress,
+		Description:              ij.Description,
+		IfType:                   ij.IfType,
+	}
+
+	return nil
+}
+
+func (identity *InterfaceIdentity) String() string {
+
+	if identity == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("InterfaceGuid: %s; NetworkGuid: %s; PermanentPhysicalAddress: %s; Description: %s; IfType: %s",
+		identity.InterfaceGuid.String(), identity.NetworkGuid.String(),
+		identity.PermanentPhysicalAddress.String(), identity.Description, identity.IfType.String())
+}
@@ -0,0 +1,131 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func interfaceIdentityTestRows() []*IfRow {
+	return []*IfRow{
+		{
+			InterfaceLuid:            1,
+			InterfaceGuid:            GUID{Data1: 0x11111111, Data4: [8]byte{1}},
+			NetworkGuid:              GUID{Data1: 0xaaaaaaaa},
+			PermanentPhysicalAddress: string([]byte{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03}),
+			Description:              "Intel(R) Ethernet Connection",
+			Type:                     IF_TYPE_ETHERNET_CSMACD,
+		},
+		{
+			InterfaceLuid:            2,
+			InterfaceGuid:            GUID{Data1: 0x22222222},
+			PermanentPhysicalAddress: string([]byte{0x00, 0x15, 0x5d, 0x04, 0x05, 0x06}),
+			Description:              "Intel(R) Ethernet Connection #2",
+			Type:                     IF_TYPE_ETHERNET_CSMACD,
+		},
+		{
+			InterfaceLuid: 3,
+			InterfaceGuid: GUID{Data1: 0x33333333},
+			Description:   "WireGuard Tunnel",
+			Type:          IF_TYPE_PROP_VIRTUAL,
+		},
+		{
+			InterfaceLuid: 4,
+			InterfaceGuid: GUID{Data1: 0x44444444},
+			Description:   "WireGuard Tunnel",
+			Type:          IF_TYPE_PROP_VIRTUAL,
+		},
+	}
+}
+
+func TestInterfaceIdentityMatch(t *testing.T) {
+
+	rows := interfaceIdentityTestRows()
+
+	identity := NewInterfaceIdentity(rows[0])
+
+	best, err := identity.bestMatch(rows, 0.5)
+
+	if err != nil {
+		t.Fatalf("InterfaceIdentity.bestMatch() returned an error: %v", err)
+	}
+
+	if best.IfRow.InterfaceLuid != 1 || best.Confidence != 1 {
+		t.Errorf("InterfaceIdentity.bestMatch() returned LUID %d with confidence %.2f although LUID 1 with "+
+			"confidence 1 is expected.", best.IfRow.InterfaceLuid, best.Confidence)
+	}
+
+	// Driver reinstall: new InterfaceGuid, the same physical address.
+	rows[0].InterfaceGuid = GUID{Data1: 0x55555555}
+
+	best, err = identity.bestMatch(rows, 0.4)
+
+	if err != nil {
+		t.Fatalf("InterfaceIdentity.bestMatch() returned an error: %v", err)
+	}
+
+	if best.IfRow.InterfaceLuid != 1 || best.Confidence != 0.5 {
+		t.Errorf("InterfaceIdentity.bestMatch() returned LUID %d with confidence %.2f although LUID 1 with "+
+			"confidence 0.50 is expected.", best.IfRow.InterfaceLuid, best.Confidence)
+	}
+
+	if _, err = identity.bestMatch(rows, 0.6); err == nil {
+		t.Error("InterfaceIdentity.bestMatch() didn't return an error although no match is good enough.")
+	}
+
+	// Two virtual adapters which differ by GUID only.
+	identity = &InterfaceIdentity{Description: "WireGuard Tunnel", IfType: IF_TYPE_PROP_VIRTUAL}
+
+	_, err = identity.bestMatch(rows, 0)
+
+	if ambiguous, ok := err.(*InterfaceIdentityAmbiguousError); !ok {
+		t.Errorf("InterfaceIdentity.bestMatch() returned %v although *InterfaceIdentityAmbiguousError is expected.",
+			err)
+	} else if len(ambiguous.Candidates) != 2 {
+		t.Errorf("InterfaceIdentityAmbiguousError has %d candidates although 2 are expected.",
+			len(ambiguous.Candidates))
+	}
+
+	// IfType alone doesn't match.
+	identity = &InterfaceIdentity{IfType: IF_TYPE_ETHERNET_CSMACD}
+
+	if matches := identity.Match(rows); len(matches) != 0 {
+		t.Errorf("InterfaceIdentity.Match() returned %d matches although none is expected.", len(matches))
+	}
+}
+
+func TestInterfaceIdentityJSON(t *testing.T) {
+
+	identity := NewInterfaceIdentity(interfaceIdentityTestRows()[0])
+
+	data, err := json.Marshal(identity)
+
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	expected := `{"interfaceGuid":"{11111111-0000-0000-0100-000000000000}",` +
+		`"networkGuid":"{AAAAAAAA-0000-0000-0000-000000000000}","permanentPhysicalAddress":"00:15:5d:01:02:03",` +
+		`"description":"Intel(R) Ethernet Connection","ifType":"IF_TYPE_ETHERNET_CSMACD"}`
+
+	if string(data) != expected {
+		t.Errorf("json.Marshal() returned %s although %s is expected.", data, expected)
+	}
+
+	var decoded InterfaceIdentity
+
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if decoded.String() != identity.String() {
+		t.Errorf("json.Unmarshal() returned %s although %s is expected.", decoded.String(), identity.String())
+	}
+}
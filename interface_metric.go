@@ -0,0 +1,218 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Metric settings of an interface's IpInterface as they were before Interface.SetMetric() changed them. It can be
+// serialized by json.Marshal() (see ParseMetricRestoreToken()) and stored, so that the settings can be restored even
+// after the process which changed them has crashed.
+type MetricRestoreToken struct {
+	InterfaceLuid      uint64        `json:"interfaceLuid"`
+	Family             AddressFamily `json:"family"`
+	UseAutomaticMetric bool          `json:"useAutomaticMetric"`
+	Metric             uint32        `json:"metric"`
+}
+
+// Parses token serialized by json.Marshal().
+func ParseMetricRestoreToken(data []byte) (*MetricRestoreToken, error) {
+
+	token := &MetricRestoreToken{}
+
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, fmt.Errorf("ParseMetricRestoreToken() - %v", err)
+	}
+
+	if token.Family != AF_INET && token.Family != AF_INET6 {
+		return nil, fmt.Errorf("ParseMetricRestoreToken() - family has to be either AF_INET or AF_INET6")
+	}
+
+	return token, nil
+}
+
+// Returns update restoring the recorded settings. Metric is only restored if automatic metric wasn't used, since
+// Windows ignores it otherwise.
+func (token *MetricRestoreToken) update() *IpInterfaceUpdate {
+
+	useAutomaticMetric := token.UseAutomaticMetric
+
+	update := &IpInterfaceUpdate{UseAutomaticMetric: &useAutomaticMetric}
+
+	if !useAutomaticMetric {
+		metric := token.Metric
+		update.Metric = &metric
+	}
+
+	return update
+}
+
+// Puts the recorded metric settings back.
+func (token *MetricRestoreToken) Restore() error {
+	_, err := token.update().Apply(token.InterfaceLuid, token.Family)
+	return err
+}
+
+func (token *MetricRestoreToken) String() string {
+	return fmt.Sprintf("InterfaceLuid: %d; Family: %s; UseAutomaticMetric: %v; Metric: %d", token.InterfaceLuid,
+		token.Family.String(), token.UseAutomaticMetric, token.Metric)
+}
+
+// Disables UseAutomaticMetric of the interface's IpInterface of family 'family' (AF_INET or AF_INET6) and sets its
+// Metric to 'metric'. Returned token records the previous settings; see MetricRestoreToken.Restore().
+func (ifc *Interface) SetMetric(family AddressFamily, metric uint32) (*MetricRestoreToken, error) {
+
+	ipifc, err := ifc.GetIpInterface(family)
+
+	if err != nil {
+		return nil, err
+	}
+
+	token := &MetricRestoreToken{
+		InterfaceLuid:      ifc.Luid,
+		Family:             family,
+		UseAutomaticMetric: ipifc.UseAutomaticMetric,
+		Metric:             ipifc.Metric,
+	}
+
+	useAutomaticMetric := false
+
+	update := &IpInterfaceUpdate{UseAutomaticMetric: &useAutomaticMetric, Metric: &metric}
+
+	if _, err := update.Apply(ifc.Luid, family); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Sets the interface's metric of family 'family' to 'margin' below the lowest metric of the other interfaces which
+// are up, making it preferred to all of them. See SetMetric() and MetricBelowOthers().
+func (ifc *Interface) SetMetricBelowOthers(family AddressFamily, margin uint32) (*MetricRestoreToken, error) {
+
+	others, err := GetInterfaces()
+
+	if err != nil {
+		return nil, err
+	}
+
+	metric, err := MetricBelowOthers(others, ifc.Luid, family, margin)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ifc.SetMetric(family, metric)
+}
+
+// Sets the interface's metric of family 'family' to 'margin' above the highest metric of the other interfaces which
+// are up, making all of them preferred to it. See SetMetric() and MetricAboveOthers().
+func (ifc *Interface) SetMetricAboveOthers(family AddressFamily, margin uint32) (*MetricRestoreToken, error) {
+
+	others, err := GetInterfaces()
+
+	if err != nil {
+		return nil, err
+	}
+
+	metric, err := MetricAboveOthers(others, ifc.Luid, family, margin)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ifc.SetMetric(family, metric)
+}
+
+// Returns metric 'margin' below the lowest Ipv4Metric or Ipv6Metric (depending on 'family') of 'ifcs' which are up
+// and have 'family' enabled, excluding the interface with LUID 'excludeLuid'. The result is never lower than 1, so the margin shrinks if the
+// lowest metric isn't above 'margin'; if the lowest metric is 1, no metric is below it, and an error is returned.
+func MetricBelowOthers(ifcs []*Interface, excludeLuid uint64, family AddressFamily, margin uint32) (uint32, error) {
+
+	lowest, _, err := otherInterfacesMetricRange(ifcs, excludeLuid, family)
+
+	if err != nil {
+		return 0, fmt.Errorf("MetricBelowOthers() - %v", err)
+	}
+
+	if lowest <= 1 {
+		return 0, fmt.Errorf("MetricBelowOthers() - the lowest metric is %d, so there's no metric below it", lowest)
+	}
+
+	if lowest <= margin {
+		return 1, nil
+	}
+
+	return lowest - margin, nil
+}
+
+// Returns metric 'margin' above the highest Ipv4Metric or Ipv6Metric (depending on 'family') of 'ifcs' which are up
+// and have 'family' enabled, excluding the interface with LUID 'excludeLuid'.
+func MetricAboveOthers(ifcs []*Interface, excludeLuid uint64, family AddressFamily, margin uint32) (uint32, error) {
+
+	_, highest, err := otherInterfacesMetricRange(ifcs, excludeLuid, family)
+
+	if err != nil {
+		return 0, fmt.Errorf("MetricAboveOthers() - %v", err)
+	}
+
+	if highest > ^uint32(0)-margin {
+		return 0, fmt.Errorf("MetricAboveOthers() - metric %d plus margin %d overflows", highest, margin)
+	}
+
+	return highest + margin, nil
+}
+
+func otherInterfacesMetricRange(ifcs []*Interface, excludeLuid uint64,
+	family AddressFamily) (lowest uint32, highest uint32, err error) {
+
+	if family != AF_INET && family != AF_INET6 {
+		return 0, 0, fmt.Errorf("family has to be either AF_INET or AF_INET6")
+	}
+
+	found := false
+
+	for _, ifc := range ifcs {
+
+		if ifc.Luid == excludeLuid || ifc.OperStatus != IfOperStatusUp || ifc.IfType == IF_TYPE_SOFTWARE_LOOPBACK {
+			continue
+		}
+
+		metric := ifc.Ipv4Metric
+		enabled := IP_ADAPTER_IPV4_ENABLED
+
+		if family == AF_INET6 {
+			metric = ifc.Ipv6Metric
+			enabled = IP_ADAPTER_IPV6_ENABLED
+		}
+
+		// Interfaces with the family disabled have zero metric.
+		if ifc.Flags&enabled == 0 {
+			continue
+		}
+
+		if !found || metric < lowest {
+			lowest = metric
+		}
+
+		if !found || metric > highest {
+			highest = metric
+		}
+
+		found = true
+	}
+
+	if !found {
+		return 0, 0, fmt.Errorf("there are no other interfaces which are up with %s enabled", family.String())
+	}
+
+	return lowest, highest, nil
+}
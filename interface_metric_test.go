@@ -0,0 +1,124 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMetricRestoreToken(t *testing.T) {
+
+	token := &MetricRestoreToken{InterfaceLuid: 1 << 24, Family: AF_INET6, UseAutomaticMetric: true, Metric: 25}
+
+	data, err := json.Marshal(token)
+
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	expected := `{"interfaceLuid":16777216,"family":"AF_INET6","useAutomaticMetric":true,"metric":25}`
+
+	if string(data) != expected {
+		t.Errorf("Serialized token is %s instead of %s.", data, expected)
+	}
+
+	parsed, err := ParseMetricRestoreToken(data)
+
+	if err != nil {
+		t.Fatalf("ParseMetricRestoreToken() returned an error: %v", err)
+	}
+
+	if *parsed != *token {
+		t.Errorf("ParseMetricRestoreToken() returned %s instead of %s.", parsed.String(), token.String())
+	}
+
+	// Automatic metric is restored without the recorded metric, which would be ignored.
+	if update := parsed.update(); !*update.UseAutomaticMetric || update.Metric != nil {
+		t.Errorf("Unexpected restore update: %+v", update)
+	}
+
+	parsed.UseAutomaticMetric = false
+
+	if update := parsed.update(); *update.UseAutomaticMetric || update.Metric == nil || *update.Metric != 25 {
+		t.Errorf("Unexpected restore update: %+v", update)
+	}
+
+	if _, err = ParseMetricRestoreToken([]byte(`{"interfaceLuid":1,"family":"AF_UNSPEC"}`)); err == nil {
+		t.Error("ParseMetricRestoreToken() should have failed for AF_UNSPEC.")
+	}
+
+	if _, err = ParseMetricRestoreToken([]byte(`{"interfaceLuid":"1"}`)); err == nil {
+		t.Error("ParseMetricRestoreToken() should have failed for malformed token.")
+	}
+}
+
+func TestMetricRelativeToOthers(t *testing.T) {
+
+	both := IP_ADAPTER_IPV4_ENABLED | IP_ADAPTER_IPV6_ENABLED
+
+	ifcs := []*Interface{
+		{Luid: 1, OperStatus: IfOperStatusUp, IfType: IF_TYPE_ETHERNET_CSMACD, Flags: both, Ipv4Metric: 25,
+			Ipv6Metric: 35},
+		{Luid: 2, OperStatus: IfOperStatusUp, IfType: IF_TYPE_IEEE80211, Flags: both, Ipv4Metric: 50, Ipv6Metric: 5},
+		{Luid: 3, OperStatus: IfOperStatusDown, IfType: IF_TYPE_ETHERNET_CSMACD, Flags: both, Ipv4Metric: 1,
+			Ipv6Metric: 1},
+		{Luid: 4, OperStatus: IfOperStatusUp, IfType: IF_TYPE_SOFTWARE_LOOPBACK, Flags: both, Ipv4Metric: 75,
+			Ipv6Metric: 75},
+		{Luid: 5, OperStatus: IfOperStatusUp, IfType: IF_TYPE_PROP_VIRTUAL, Flags: both, Ipv4Metric: 0, Ipv6Metric: 0},
+		// IPv6 is disabled, so its zero Ipv6Metric doesn't count; likewise IPv4 and its high Ipv4Metric.
+		{Luid: 6, OperStatus: IfOperStatusUp, IfType: IF_TYPE_ETHERNET_CSMACD, Flags: IP_ADAPTER_IPV4_ENABLED,
+			Ipv4Metric: 30, Ipv6Metric: 0},
+		{Luid: 7, OperStatus: IfOperStatusUp, IfType: IF_TYPE_ETHERNET_CSMACD, Flags: IP_ADAPTER_IPV6_ENABLED,
+			Ipv4Metric: 9000, Ipv6Metric: 40},
+	}
+
+	tests := []struct {
+		name     string
+		f        func([]*Interface, uint64, AddressFamily, uint32) (uint32, error)
+		family   AddressFamily
+		margin   uint32
+		expected uint32
+	}{
+		{"MetricBelowOthers", MetricBelowOthers, AF_INET, 5, 20},
+		{"MetricBelowOthers", MetricBelowOthers, AF_INET6, 5, 1},
+		{"MetricAboveOthers", MetricAboveOthers, AF_INET, 10, 60},
+		{"MetricAboveOthers", MetricAboveOthers, AF_INET6, 10, 50},
+	}
+
+	for _, test := range tests {
+
+		metric, err := test.f(ifcs, 5, test.family, test.margin)
+
+		if err != nil {
+			t.Errorf("%s(%s) returned an error: %v", test.name, test.family.String(), err)
+		} else if metric != test.expected {
+			t.Errorf("%s(%s) returned %d instead of %d.", test.name, test.family.String(), metric, test.expected)
+		}
+	}
+
+	if _, err := MetricBelowOthers(ifcs[2:5], 5, AF_INET, 5); err == nil {
+		t.Error("MetricBelowOthers() should have failed without other interfaces which are up.")
+	}
+
+	if _, err := MetricBelowOthers(ifcs[5:6], 5, AF_INET6, 5); err == nil {
+		t.Error("MetricBelowOthers() should have failed without other interfaces with IPv6 enabled.")
+	}
+
+	// Metric 1 would tie with the other interface's.
+	ifcs[1].Ipv6Metric = 1
+
+	if metric, err := MetricBelowOthers(ifcs, 5, AF_INET6, 5); err == nil {
+		t.Errorf("MetricBelowOthers() returned %d although the lowest metric is 1.", metric)
+	}
+
+	if _, err := MetricAboveOthers(ifcs, 5, AF_INET, ^uint32(0)); err == nil {
+		t.Error("MetricAboveOthers() should have failed on overflow.")
+	}
+}
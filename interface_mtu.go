@@ -0,0 +1,200 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sizes of headers added by the usual tunnel encapsulations, in bytes.
+const (
+	IPv4HeaderSize = 20
+	IPv6HeaderSize = 40
+	UDPHeaderSize  = 8
+
+	// WireGuard transport data message header (type, receiver index and counter; 16 bytes) plus Poly1305
+	// authentication tag (16 bytes).
+	WireGuardHeaderSize = 32
+)
+
+// Header added by a tunnel encapsulation.
+type EncapsulationHeader struct {
+	Name string
+	Size uint32
+}
+
+// Headers used by the predefined encapsulations. Custom ones can be defined by the caller.
+var (
+	UDPEncapsulationHeader       = EncapsulationHeader{Name: "UDP", Size: UDPHeaderSize}
+	WireGuardEncapsulationHeader = EncapsulationHeader{Name: "WireGuard", Size: WireGuardHeaderSize}
+)
+
+// Describes how inner packets are carried over the underlying interface: the outer IP header, of family
+// OuterFamily, followed by Headers.
+type TunnelEncapsulation struct {
+	// Either AF_INET or AF_INET6.
+	OuterFamily AddressFamily
+
+	Headers []EncapsulationHeader
+}
+
+// Returns encapsulation of UDP datagrams over IP of family 'outerFamily'.
+func UDPEncapsulation(outerFamily AddressFamily) *TunnelEncapsulation {
+	return &TunnelEncapsulation{OuterFamily: outerFamily, Headers: []EncapsulationHeader{UDPEncapsulationHeader}}
+}
+
+// Returns encapsulation of WireGuard over UDP over IP of family 'outerFamily'.
+func WireGuardEncapsulation(outerFamily AddressFamily) *TunnelEncapsulation {
+	return &TunnelEncapsulation{
+		OuterFamily: outerFamily,
+		Headers:     []EncapsulationHeader{UDPEncapsulationHeader, WireGuardEncapsulationHeader},
+	}
+}
+
+// Returns total size of the headers added by the encapsulation, including the outer IP header.
+func (te *TunnelEncapsulation) Overhead() (uint32, error) {
+
+	var overhead uint32
+
+	switch te.OuterFamily {
+	case AF_INET:
+		overhead = IPv4HeaderSize
+	case AF_INET6:
+		overhead = IPv6HeaderSize
+	default:
+		return 0, fmt.Errorf("TunnelEncapsulation.Overhead() - OuterFamily has to be either AF_INET or AF_INET6")
+	}
+
+	for _, header := range te.Headers {
+		overhead += header.Size
+	}
+
+	return overhead, nil
+}
+
+// Returns MTU of the inner packets which fit into packets of 'outerMtu' bytes once encapsulated.
+func (te *TunnelEncapsulation) InnerMTU(outerMtu uint32) (uint32, error) {
+
+	overhead, err := te.Overhead()
+
+	if err != nil {
+		return 0, err
+	}
+
+	if overhead >= outerMtu {
+		return 0, fmt.Errorf("TunnelEncapsulation.InnerMTU() - overhead of %d bytes doesn't fit into MTU of %d bytes",
+			overhead, outerMtu)
+	}
+
+	return outerMtu - overhead, nil
+}
+
+func (te *TunnelEncapsulation) String() string {
+
+	names := make([]string, len(te.Headers)+1)
+
+	names[0] = te.OuterFamily.String()
+
+	for i, header := range te.Headers {
+		names[i+1] = fmt.Sprintf("%s(%d)", header.Name, header.Size)
+	}
+
+	return strings.Join(names, " + ")
+}
+
+// Returns MTU of the inner packets of a tunnel using 'encapsulation' over the interface, based on the interface's
+// current NlMtu for encapsulation.OuterFamily.
+func (ifc *Interface) TunnelInnerMTU(encapsulation *TunnelEncapsulation) (uint32, error) {
+
+	ipifc, err := ifc.GetIpInterface(encapsulation.OuterFamily)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return encapsulation.InnerMTU(ipifc.NlMtu)
+}
+
+// Sets NlMtu of the interface's IPv4 and IPv6 IpInterfaces. Zero value leaves the family's MTU unchanged. See
+// SetMTUEx() for details.
+func (ifc *Interface) SetMTU(ipv4Mtu uint32, ipv6Mtu uint32) error {
+	return ifc.SetMTUEx(ipv4Mtu, ipv6Mtu, nil)
+}
+
+// Sets NlMtu of the interface's IPv4 and IPv6 IpInterfaces, and, if 'pathMtuDiscoveryTimeout' isn't nil,
+// PathMtuDiscoveryTimeout (in milliseconds) of those whose MTU is set. Zero MTU leaves the family untouched, including
+// its PathMtuDiscoveryTimeout. MTUs are checked
+// against the protocol minimums (MinIPv4Mtu and MinIPv6Mtu) and against IfRow.Mtu of the adapter, and nothing is
+// changed if any of them is out of range.
+func (ifc *Interface) SetMTUEx(ipv4Mtu uint32, ipv6Mtu uint32, pathMtuDiscoveryTimeout *uint32) error {
+
+	ifrow, err := ifc.GetIfRow(MibIfEntryNormalWithoutStatistics)
+
+	if err != nil {
+		return err
+	}
+
+	updates, err := mtuUpdates(ipv4Mtu, ipv6Mtu, ifrow.Mtu, pathMtuDiscoveryTimeout)
+
+	if err != nil {
+		return err
+	}
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+
+		update, ok := updates[family]
+
+		if !ok {
+			continue
+		}
+
+		if _, err := update.Apply(ifc.Luid, family); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns IpInterface updates for the families whose MTU is to be changed, after checking all of them. Zero
+// 'ceiling' means the adapter doesn't report its MTU.
+func mtuUpdates(ipv4Mtu uint32, ipv6Mtu uint32, ceiling uint32,
+	pathMtuDiscoveryTimeout *uint32) (map[AddressFamily]*IpInterfaceUpdate, error) {
+
+	updates := make(map[AddressFamily]*IpInterfaceUpdate)
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+
+		mtu := ipv4Mtu
+
+		if family == AF_INET6 {
+			mtu = ipv6Mtu
+		}
+
+		if mtu == 0 {
+			continue
+		}
+
+		if ceiling != 0 && mtu > ceiling {
+			return nil, fmt.Errorf("SetMTUEx() - %s MTU %d exceeds the adapter's MTU of %d", family.String(), mtu,
+				ceiling)
+		}
+
+		update := &IpInterfaceUpdate{NlMtu: &mtu, PathMtuDiscoveryTimeout: pathMtuDiscoveryTimeout}
+
+		if err := update.Validate(family); err != nil {
+			return nil, err
+		}
+
+		updates[family] = update
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,100 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTunnelEncapsulationInnerMTU(t *testing.T) {
+
+	tests := []struct {
+		encapsulation *TunnelEncapsulation
+		outerMtu      uint32
+		innerMtu      uint32
+	}{
+		{WireGuardEncapsulation(AF_INET6), 1500, 1420},
+		{WireGuardEncapsulation(AF_INET), 1500, 1440},
+		{UDPEncapsulation(AF_INET), 1500, 1472},
+		{&TunnelEncapsulation{OuterFamily: AF_INET6}, 1280, 1240},
+		{&TunnelEncapsulation{
+			OuterFamily: AF_INET,
+			Headers: []EncapsulationHeader{
+				UDPEncapsulationHeader,
+				{Name: "VXLAN", Size: 8},
+				{Name: "Ethernet", Size: 14},
+			},
+		}, 1500, 1450},
+	}
+
+	for _, test := range tests {
+
+		innerMtu, err := test.encapsulation.InnerMTU(test.outerMtu)
+
+		if err != nil {
+			t.Errorf("InnerMTU(%d) of %s returned an error: %v", test.outerMtu, test.encapsulation.String(), err)
+		} else if innerMtu != test.innerMtu {
+			t.Errorf("InnerMTU(%d) of %s is %d instead of %d.", test.outerMtu, test.encapsulation.String(), innerMtu,
+				test.innerMtu)
+		}
+	}
+
+	if _, err := WireGuardEncapsulation(AF_INET6).InnerMTU(80); err == nil {
+		t.Error("InnerMTU() should have failed for MTU not larger than the overhead.")
+	}
+
+	if _, err := WireGuardEncapsulation(AF_UNSPEC).InnerMTU(1500); err == nil {
+		t.Error("InnerMTU() should have failed for AF_UNSPEC outer family.")
+	}
+
+	if s := WireGuardEncapsulation(AF_INET6).String(); s != "AF_INET6 + UDP(8) + WireGuard(32)" {
+		t.Errorf("TunnelEncapsulation.String() returned \"%s\".", s)
+	}
+}
+
+func TestMtuUpdates(t *testing.T) {
+
+	timeout := uint32(600000)
+
+	updates, err := mtuUpdates(1400, 1420, 1500, &timeout)
+
+	if err != nil {
+		t.Fatalf("mtuUpdates() returned an error: %v", err)
+	}
+
+	if len(updates) != 2 || *updates[AF_INET].NlMtu != 1400 || *updates[AF_INET6].NlMtu != 1420 ||
+		*updates[AF_INET6].PathMtuDiscoveryTimeout != timeout {
+		t.Errorf("mtuUpdates() returned unexpected updates: %v", updates)
+	}
+
+	// Zero MTU leaves the family untouched; zero ceiling isn't enforced.
+	if updates, err = mtuUpdates(0, 9000, 0, nil); err != nil || len(updates) != 1 || updates[AF_INET6] == nil {
+		t.Errorf("mtuUpdates() returned (%v, %v).", updates, err)
+	}
+
+	if _, err = mtuUpdates(1400, 1600, 1500, nil); err == nil || !strings.Contains(err.Error(), "adapter's MTU") {
+		t.Errorf("mtuUpdates() should have failed for MTU above the ceiling: %v", err)
+	}
+
+	if _, err = mtuUpdates(1400, 1200, 1500, nil); err == nil || !strings.Contains(err.Error(), "NlMtu 1200") {
+		t.Errorf("mtuUpdates() should have failed for IPv6 MTU below the minimum: %v", err)
+	}
+
+	if _, err = mtuUpdates(500, 0, 1500, nil); err == nil {
+		t.Error("mtuUpdates() should have failed for IPv4 MTU below the minimum.")
+	}
+
+	// With both families out of range, IPv4 is always reported.
+	for i := 0; i < 10; i++ {
+		if _, err = mtuUpdates(1600, 1700, 1500, nil); err == nil || !strings.Contains(err.Error(), "MTU 1600") {
+			t.Fatalf("mtuUpdates() didn't report the IPv4 MTU first: %v", err)
+		}
+	}
+}
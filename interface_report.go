@@ -0,0 +1,430 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// Output format of WriteInterfaceReport().
+type ReportFormat int
+
+const (
+	// Plain text, laid out like 'ipconfig /all' output.
+	ReportFormatPlain ReportFormat = iota
+	// Markdown, with a heading and a property table per interface.
+	ReportFormatMarkdown
+)
+
+// Data about a single interface rendered by WriteInterfaceReport(). Only Interface is mandatory; properties coming
+// from IfRow, IPv4 and IPv6 are left out of the report if they are nil.
+type InterfaceReportEntry struct {
+	Interface *Interface
+	IfRow     *IfRow
+	IPv4      *IpInterface
+	IPv6      *IpInterface
+}
+
+// Pairs 'ifcs' with rows from 'ifrows' and 'ipifcs' having the same LUID. Interfaces keep their order.
+func NewInterfaceReportEntries(ifcs []*Interface, ifrows []*IfRow, ipifcs []*IpInterface) []*InterfaceReportEntry {
+
+	entries := make([]*InterfaceReportEntry, len(ifcs))
+	entriesByLuid := make(map[uint64]*InterfaceReportEntry, len(ifcs))
+
+	for i, ifc := range ifcs {
+		entries[i] = &InterfaceReportEntry{Interface: ifc}
+		entriesByLuid[ifc.Luid] = entries[i]
+	}
+
+	for _, ifrow := range ifrows {
+		if entry, ok := entriesByLuid[ifrow.InterfaceLuid]; ok {
+			entry.IfRow = ifrow
+		}
+	}
+
+	for _, ipifc := range ipifcs {
+
+		entry, ok := entriesByLuid[ipifc.InterfaceLuid]
+
+		if !ok {
+			continue
+		}
+
+		switch ipifc.Family {
+		case AF_INET:
+			entry.IPv4 = ipifc
+		case AF_INET6:
+			entry.IPv6 = ipifc
+		}
+	}
+
+	return entries
+}
+
+// Returns report entries of all the interfaces, including gateways and WINS servers.
+func GetInterfaceReportEntries() ([]*InterfaceReportEntry, error) {
+
+	flags := DefaultGetAdapterAddressesFlags()
+	flags.GAA_FLAG_INCLUDE_GATEWAYS = true
+	flags.GAA_FLAG_INCLUDE_WINS_INFO = true
+
+	ifcs, err := GetInterfacesEx(flags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ifrows, err := GetIfRows(MibIfEntryNormalWithoutStatistics)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ipifcs, err := GetIpInterfaces(AF_INET)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ipifcs6, err := GetIpInterfaces(AF_INET6)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewInterfaceReportEntries(ifcs, ifrows, append(ipifcs, ipifcs6...)), nil
+}
+
+// Writes an 'ipconfig /all' like report about 'entries' to 'w'. The report depends on the entries only, so it can be
+// compared against expected output.
+func WriteInterfaceReport(w io.Writer, entries []*InterfaceReportEntry, format ReportFormat) error {
+
+	bw := bufio.NewWriter(w)
+
+	switch format {
+	case ReportFormatPlain:
+		fmt.Fprint(bw, "Windows IP Configuration\n")
+	case ReportFormatMarkdown:
+		fmt.Fprint(bw, "# Windows IP Configuration\n")
+	default:
+		return fmt.Errorf("WriteInterfaceReport() - unknown report format %d", format)
+	}
+
+	for _, entry := range entries {
+
+		heading := fmt.Sprintf("%s adapter %s", reportAdapterKind(entry.Interface.IfType), entry.Interface.FriendlyName)
+		items := entry.reportItems()
+
+		if format == ReportFormatPlain {
+			writePlainReportSection(bw, heading, items)
+		} else {
+			writeMarkdownReportSection(bw, heading, items)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// A line of the report. Items with more than one value continue on the following lines in plain text format.
+type reportItem struct {
+	label  string
+	values []string
+}
+
+const reportLabelWidth = 36
+
+func writePlainReportSection(w io.Writer, heading string, items []reportItem) {
+
+	fmt.Fprintf(w, "\n%s:\n\n", heading)
+
+	continuation := strings.Repeat(" ", 3+reportLabelWidth+2)
+
+	for _, item := range items {
+
+		label := reportDottedLabel(item.label)
+
+		for i, value := range item.values {
+			if i == 0 {
+				fmt.Fprintf(w, "   %s: %s\n", label, value)
+			} else {
+				fmt.Fprintf(w, "%s%s\n", continuation, value)
+			}
+		}
+	}
+}
+
+// Pads 'label' to reportLabelWidth with dots placed on every other column, the way 'ipconfig' does, so that dots of
+// all the labels line up. Labels which don't fit are followed by a single space.
+func reportDottedLabel(label string) string {
+
+	if len(label) >= reportLabelWidth-1 {
+		return label + " "
+	}
+
+	padded := []byte(label)
+
+	for column := len(padded); column < reportLabelWidth; column++ {
+		if (reportLabelWidth-column)%2 == 0 {
+			padded = append(padded, '.')
+		} else {
+			padded = append(padded, ' ')
+		}
+	}
+
+	return string(padded)
+}
+
+func writeMarkdownReportSection(w io.Writer, heading string, items []reportItem) {
+
+	fmt.Fprintf(w, "\n## %s\n\n| Property | Value |\n| --- | --- |\n", escapeMarkdownCell(heading))
+
+	for _, item := range items {
+
+		values := make([]string, len(item.values))
+
+		for i, value := range item.values {
+			values[i] = escapeMarkdownCell(value)
+		}
+
+		fmt.Fprintf(w, "| %s | %s |\n", escapeMarkdownCell(item.label), strings.Join(values, "<br>"))
+	}
+}
+
+func escapeMarkdownCell(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func reportAdapterKind(ifType IfType) string {
+	switch ifType {
+	case IF_TYPE_ETHERNET_CSMACD:
+		return "Ethernet"
+	case IF_TYPE_IEEE80211:
+		return "Wireless LAN"
+	case IF_TYPE_TUNNEL:
+		return "Tunnel"
+	case IF_TYPE_PPP:
+		return "PPP"
+	case IF_TYPE_WWANPP, IF_TYPE_WWANPP2:
+		return "Mobile Broadband"
+	case IF_TYPE_SOFTWARE_LOOPBACK:
+		return "Loopback"
+	default:
+		return "Unknown"
+	}
+}
+
+func (entry *InterfaceReportEntry) reportItems() []reportItem {
+
+	ifc := entry.Interface
+	items := make([]reportItem, 0)
+
+	add := func(label string, values ...string) {
+		if len(values) > 0 {
+			items = append(items, reportItem{label: label, values: values})
+		}
+	}
+
+	if ifc.OperStatus != IfOperStatusUp ||
+		(entry.IfRow != nil && entry.IfRow.MediaConnectState == MediaConnectStateDisconnected) {
+		add("Media State", "Media disconnected")
+	}
+
+	add("Connection-specific DNS Suffix", ifc.DnsSuffix)
+	add("Description", ifc.Description)
+
+	if len(ifc.PhysicalAddress) > 0 {
+		add("Physical Address", reportHexBytes(ifc.PhysicalAddress))
+	}
+
+	add("DHCP Enabled", reportYesNo(ifc.Flags&IP_ADAPTER_DHCP_ENABLED != 0))
+	add("Interface Index", fmt.Sprintf("%d", ifc.Index))
+
+	if entry.IfRow != nil {
+		add("Link Speed", reportLinkSpeed(entry.IfRow.TransmitLinkSpeed))
+	}
+
+	for _, address := range ifc.UnicastAddresses {
+
+		ip := address.Address.Address
+		value := ipWithZoneToString(ip, address.Address.IPv6ScopeId)
+
+		if state := reportDadState(address.DadState); state != "" {
+			value += fmt.Sprintf("(%s)", state)
+		}
+
+		add(reportAddressLabel(address), value)
+
+		if ip.To4() != nil {
+			add("Subnet Mask", net.IP(net.CIDRMask(int(address.OnLinkPrefixLength), 32)).String())
+		}
+
+		add("Valid Lifetime", reportLifetime(address.ValidLifetime))
+		add("Preferred Lifetime", reportLifetime(address.PreferredLifetime))
+	}
+
+	add("Default Gateway", reportAddresses(ifc.GatewayAddresses)...)
+
+	if ifc.Dhcpv4Server != nil {
+		add("DHCP Server", ipWithZoneToString(ifc.Dhcpv4Server.Address, ifc.Dhcpv4Server.IPv6ScopeId))
+	}
+
+	if ifc.Dhcpv6Server != nil {
+		add("DHCPv6 Server", ipWithZoneToString(ifc.Dhcpv6Server.Address, ifc.Dhcpv6Server.IPv6ScopeId))
+	}
+
+	if ifc.Dhcpv6Iaid != 0 {
+		add("DHCPv6 IAID", fmt.Sprintf("%d", ifc.Dhcpv6Iaid))
+	}
+
+	if len(ifc.Dhcpv6ClientDuid) > 0 {
+		add("DHCPv6 Client DUID", reportHexBytes(ifc.Dhcpv6ClientDuid))
+	}
+
+	add("DNS Servers", reportAddresses(ifc.DnsServerAddresses)...)
+	add("WINS Servers", reportAddresses(ifc.WinsServerAddresses)...)
+	add("NetBIOS over Tcpip", reportEnabledDisabled(ifc.Flags&IP_ADAPTER_NETBIOS_OVER_TCPIP_ENABLED != 0))
+	add("Connection-specific DNS Suffix Search List", ifc.DnsSuffixes...)
+
+	if entry.IPv4 != nil {
+		add("IPv4 Interface Metric", reportMetric(entry.IPv4))
+		add("IPv4 MTU", fmt.Sprintf("%d", entry.IPv4.NlMtu))
+	} else if ifc.Flags&IP_ADAPTER_IPV4_ENABLED != 0 {
+		add("IPv4 Interface Metric", fmt.Sprintf("%d", ifc.Ipv4Metric))
+	}
+
+	if entry.IPv6 != nil {
+		add("IPv6 Interface Metric", reportMetric(entry.IPv6))
+		add("IPv6 MTU", fmt.Sprintf("%d", entry.IPv6.NlMtu))
+	} else if ifc.Flags&IP_ADAPTER_IPV6_ENABLED != 0 {
+		add("IPv6 Interface Metric", fmt.Sprintf("%d", ifc.Ipv6Metric))
+	}
+
+	return items
+}
+
+func reportAddressLabel(address *UnicastAddress) string {
+
+	ip := address.Address.Address
+
+	switch {
+	case ip.To4() != nil && ip.IsLinkLocalUnicast():
+		return "Autoconfiguration IPv4 Address"
+	case ip.To4() != nil:
+		return "IPv4 Address"
+	case ip.IsLinkLocalUnicast():
+		return "Link-local IPv6 Address"
+	case NlSuffixOrigin(address.SuffixOrigin) == IpSuffixOriginRandom:
+		return "Temporary IPv6 Address"
+	default:
+		return "IPv6 Address"
+	}
+}
+
+func reportDadState(state IpDadState) string {
+	switch NlDadState(state) {
+	case IpDadStateInvalid:
+		return "Invalid"
+	case IpDadStateTentative:
+		return "Tentative"
+	case IpDadStateDuplicate:
+		return "Duplicate"
+	case IpDadStateDeprecated:
+		return "Deprecated"
+	case IpDadStatePreferred:
+		return "Preferred"
+	default:
+		return ""
+	}
+}
+
+func reportAddresses(addresses []*IpAdapterAddressCommonType) []string {
+
+	values := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		values = append(values, ipWithZoneToString(address.Address.Address, address.Address.IPv6ScopeId))
+	}
+
+	return values
+}
+
+func reportMetric(ipifc *IpInterface) string {
+
+	if ipifc.UseAutomaticMetric {
+		return fmt.Sprintf("%d (automatic)", ipifc.Metric)
+	}
+
+	return fmt.Sprintf("%d", ipifc.Metric)
+}
+
+// Formats lifetimes in seconds, where 0xffffffff means infinite lifetime.
+func reportLifetime(seconds uint32) string {
+
+	if seconds == 0xffffffff {
+		return "Infinite"
+	}
+
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+// Formats link speed in bits per second, using the largest unit that keeps the value whole.
+func reportLinkSpeed(speed uint64) string {
+
+	units := []struct {
+		multiplier uint64
+		name       string
+	}{
+		{1000 * 1000 * 1000, "Gbps"},
+		{1000 * 1000, "Mbps"},
+		{1000, "kbps"},
+	}
+
+	for _, unit := range units {
+		if speed >= unit.multiplier && speed%unit.multiplier == 0 {
+			return fmt.Sprintf("%d %s", speed/unit.multiplier, unit.name)
+		}
+	}
+
+	return fmt.Sprintf("%d bps", speed)
+}
+
+// Formats bytes like 'ipconfig' does, i.e. "00-15-5D-01-02-03".
+func reportHexBytes(bytes []byte) string {
+
+	parts := make([]string, len(bytes))
+
+	for i, b := range bytes {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+
+	return strings.Join(parts, "-")
+}
+
+func reportYesNo(value bool) string {
+
+	if value {
+		return "Yes"
+	}
+
+	return "No"
+}
+
+func reportEnabledDisabled(value bool) string {
+
+	if value {
+		return "Enabled"
+	}
+
+	return "Disabled"
+}
@@ -0,0 +1,181 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bytes"
+	"flag"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"testing"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files in testdata")
+
+// Compares 'actual' with the content of testdata/'name', or overwrites the file if -update flag is set.
+func checkGolden(t *testing.T, name string, actual []byte) {
+
+	path := filepath.Join("testdata", name)
+
+	if *updateGolden {
+		if err := ioutil.WriteFile(path, actual, 0644); err != nil {
+			t.Fatalf("ioutil.WriteFile() returned an error: %v", err)
+		}
+		return
+	}
+
+	expected, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		t.Fatalf("ioutil.ReadFile() returned an error: %v", err)
+	}
+
+	if !bytes.Equal(bytes.Replace(expected, []byte("\r\n"), []byte("\n"), -1), actual) {
+		t.Errorf("Output differs from %s (run the test with -update to accept it):\n%s", path, actual)
+	}
+}
+
+func interfaceReportTestEntries() []*InterfaceReportEntry {
+
+	unicastAddress := func(address SockaddrInet, suffixOrigin NlSuffixOrigin, dadState NlDadState, prefixLength uint8,
+		validLifetime, preferredLifetime uint32) *UnicastAddress {
+		return &UnicastAddress{
+			IpAdapterAddressCommonTypeEx: IpAdapterAddressCommonTypeEx{
+				IpAdapterAddressCommonType: IpAdapterAddressCommonType{Address: address},
+			},
+			SuffixOrigin:       IpSuffixOrigin(suffixOrigin),
+			DadState:           IpDadState(dadState),
+			ValidLifetime:      validLifetime,
+			PreferredLifetime:  preferredLifetime,
+			OnLinkPrefixLength: prefixLength,
+		}
+	}
+
+	commonType := func(address SockaddrInet) *IpAdapterAddressCommonType {
+		return &IpAdapterAddressCommonType{Address: address}
+	}
+
+	ipv4 := func(a, b, c, d byte) SockaddrInet {
+		return SockaddrInet{Family: AF_INET, Address: net.IPv4(a, b, c, d)}
+	}
+
+	ipv6 := func(address string, zone uint32) SockaddrInet {
+		return SockaddrInet{Family: AF_INET6, Address: net.ParseIP(address), IPv6ScopeId: zone}
+	}
+
+	ethernet := &Interface{
+		Luid:         1689399616077824,
+		Index:        12,
+		FriendlyName: "Ethernet",
+		UnicastAddresses: []*UnicastAddress{
+			unicastAddress(ipv6("2001:db8::215:5dff:fe01:203", 0), IpSuffixOriginLinkLayerAddress,
+				IpDadStatePreferred, 64, 86400, 14400),
+			unicastAddress(ipv6("2001:db8::8d3c:12f:a5e3:7b21", 0), IpSuffixOriginRandom, IpDadStateDeprecated, 64,
+				86400, 0),
+			unicastAddress(ipv6("fe80::215:5dff:fe01:203", 12), IpSuffixOriginLinkLayerAddress, IpDadStatePreferred,
+				64, 0xffffffff, 0xffffffff),
+			unicastAddress(ipv4(192, 0, 2, 10), IpSuffixOriginDhcp, IpDadStatePreferred, 24, 691200, 691200),
+		},
+		DnsServerAddresses: []*IpAdapterAddressCommonType{
+			commonType(ipv4(192, 0, 2, 1)),
+			commonType(ipv4(192, 0, 2, 2)),
+		},
+		DnsSuffix:       "corp.example.com",
+		Description:     "Intel(R) Ethernet Connection (7) I219-V",
+		PhysicalAddress: net.HardwareAddr{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03},
+		Flags: IP_ADAPTER_DDNS_ENABLED | IP_ADAPTER_DHCP_ENABLED | IP_ADAPTER_NETBIOS_OVER_TCPIP_ENABLED |
+			IP_ADAPTER_IPV4_ENABLED | IP_ADAPTER_IPV6_ENABLED,
+		Mtu:                 1500,
+		IfType:              IF_TYPE_ETHERNET_CSMACD,
+		OperStatus:          IfOperStatusUp,
+		WinsServerAddresses: []*IpAdapterAddressCommonType{commonType(ipv4(192, 0, 2, 5))},
+		GatewayAddresses: []*IpAdapterAddressCommonType{
+			commonType(ipv6("fe80::1", 12)),
+			commonType(ipv4(192, 0, 2, 1)),
+		},
+		Ipv4Metric:   25,
+		Ipv6Metric:   25,
+		Dhcpv4Server: &SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 1), Port: 67},
+		Dhcpv6ClientDuid: []uint8{0x00, 0x01, 0x00, 0x01, 0x24, 0x5c, 0x3e, 0x1a, 0x00, 0x15, 0x5d, 0x01, 0x02,
+			0x03},
+		Dhcpv6Iaid:  100666717,
+		DnsSuffixes: []string{"corp.example.com", "example.com"},
+	}
+
+	wifi := &Interface{
+		Luid:            1988650734370816,
+		Index:           7,
+		FriendlyName:    "Wi-Fi",
+		Description:     "Intel(R) Wireless-AC 9560 160MHz",
+		PhysicalAddress: net.HardwareAddr{0x7c, 0x2a, 0x31, 0xaa, 0xbb, 0xcc},
+		Flags:           IP_ADAPTER_DHCP_ENABLED | IP_ADAPTER_IPV4_ENABLED | IP_ADAPTER_IPV6_ENABLED,
+		IfType:          IF_TYPE_IEEE80211,
+		OperStatus:      IfOperStatusDown,
+		Ipv4Metric:      35,
+		Ipv6Metric:      35,
+	}
+
+	tunnel := &Interface{
+		Luid:         13510798882111488,
+		Index:        31,
+		FriendlyName: "wg0 | office",
+		UnicastAddresses: []*UnicastAddress{
+			unicastAddress(ipv4(10, 8, 0, 2), IpSuffixOriginManual, IpDadStatePreferred, 24, 0xffffffff, 0xffffffff),
+		},
+		Description:        "WireGuard Tunnel",
+		Flags:              IP_ADAPTER_IPV4_ENABLED,
+		Mtu:                1420,
+		IfType:             IF_TYPE_PROP_VIRTUAL,
+		OperStatus:         IfOperStatusUp,
+		Ipv4Metric:         5,
+		DnsServerAddresses: []*IpAdapterAddressCommonType{commonType(ipv4(10, 8, 0, 1))},
+	}
+
+	ifrows := []*IfRow{
+		{InterfaceLuid: ethernet.Luid, TransmitLinkSpeed: 1000000000, MediaConnectState: MediaConnectStateConnected},
+		{InterfaceLuid: wifi.Luid, TransmitLinkSpeed: 0, MediaConnectState: MediaConnectStateDisconnected},
+		{InterfaceLuid: tunnel.Luid, TransmitLinkSpeed: 3200000000, MediaConnectState: MediaConnectStateConnected},
+	}
+
+	ipifcs := []*IpInterface{
+		{Family: AF_INET, InterfaceLuid: ethernet.Luid, UseAutomaticMetric: true, Metric: 25, NlMtu: 1500},
+		{Family: AF_INET6, InterfaceLuid: ethernet.Luid, UseAutomaticMetric: true, Metric: 25, NlMtu: 1500},
+		{Family: AF_INET, InterfaceLuid: tunnel.Luid, Metric: 5, NlMtu: 1420},
+	}
+
+	return NewInterfaceReportEntries([]*Interface{ethernet, wifi, tunnel}, ifrows, ipifcs)
+}
+
+func TestWriteInterfaceReport(t *testing.T) {
+
+	entries := interfaceReportTestEntries()
+
+	if entries[1].IfRow == nil || entries[1].IPv4 != nil || entries[2].IPv4 == nil || entries[2].IPv6 != nil {
+		t.Fatal("NewInterfaceReportEntries() paired rows with wrong interfaces.")
+	}
+
+	for name, format := range map[string]ReportFormat{
+		"interface_report.txt": ReportFormatPlain,
+		"interface_report.md":  ReportFormatMarkdown,
+	} {
+
+		var buf bytes.Buffer
+
+		if err := WriteInterfaceReport(&buf, entries, format); err != nil {
+			t.Fatalf("WriteInterfaceReport() returned an error: %v", err)
+		}
+
+		checkGolden(t, name, buf.Bytes())
+	}
+
+	if err := WriteInterfaceReport(&bytes.Buffer{}, entries, ReportFormat(42)); err == nil {
+		t.Error("WriteInterfaceReport() should have failed for unknown format.")
+	}
+}
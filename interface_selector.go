@@ -0,0 +1,402 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Predicate used by SelectInterfaces() function. Argument 'ifrow' is the IfRow struct of the same interface, or nil if
+// the interface has no IfRow.
+type InterfacePredicate func(ifc *Interface, ifrow *IfRow) bool
+
+// Returns interfaces satisfying all 'predicates', in the order GetInterfaces() returns them. Interfaces are gotten with
+// gateway addresses included (see HasGateway() predicate).
+func SelectInterfaces(predicates ...InterfacePredicate) ([]*Interface, error) {
+
+	flags := DefaultGetAdapterAddressesFlags()
+	flags.GAA_FLAG_INCLUDE_GATEWAYS = true
+
+	ifcs, err := GetInterfacesEx(flags)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ifrows, err := GetIfRows(MibIfEntryNormalWithoutStatistics)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return filterInterfaces(ifcs, ifrows, AllOf(predicates...)), nil
+}
+
+func filterInterfaces(ifcs []*Interface, ifrows []*IfRow, predicate InterfacePredicate) []*Interface {
+
+	ifrowsByLuid := make(map[uint64]*IfRow, len(ifrows))
+
+	for _, ifrow := range ifrows {
+		ifrowsByLuid[ifrow.InterfaceLuid] = ifrow
+	}
+
+	selected := make([]*Interface, 0)
+
+	for _, ifc := range ifcs {
+		if predicate(ifc, ifrowsByLuid[ifc.Luid]) {
+			selected = append(selected, ifc)
+		}
+	}
+
+	return selected
+}
+
+// Sorts interfaces in place by Ipv4Metric (if 'family' is AF_INET) or Ipv6Metric (if 'family' is AF_INET6), lowest
+// metric first. Interfaces with equal metrics keep their relative order.
+func SortInterfacesByMetric(ifcs []*Interface, family AddressFamily) error {
+
+	var metric func(ifc *Interface) uint32
+
+	switch family {
+	case AF_INET:
+		metric = func(ifc *Interface) uint32 { return ifc.Ipv4Metric }
+	case AF_INET6:
+		metric = func(ifc *Interface) uint32 { return ifc.Ipv6Metric }
+	default:
+		return fmt.Errorf("SortInterfacesByMetric() - family has to be either AF_INET or AF_INET6")
+	}
+
+	sort.SliceStable(ifcs, func(i, j int) bool { return metric(ifcs[i]) < metric(ifcs[j]) })
+
+	return nil
+}
+
+// Returns predicate satisfied if all 'predicates' are satisfied (or if there are no predicates).
+func AllOf(predicates ...InterfacePredicate) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, predicate := range predicates {
+			if !predicate(ifc, ifrow) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Returns predicate satisfied if any of 'predicates' is satisfied.
+func AnyOf(predicates ...InterfacePredicate) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, predicate := range predicates {
+			if predicate(ifc, ifrow) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns predicate satisfied if 'predicate' isn't.
+func Negate(predicate InterfacePredicate) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return !predicate(ifc, ifrow)
+	}
+}
+
+// Returns predicate satisfied by interfaces of any of 'ifTypes'.
+func IfTypeIs(ifTypes ...IfType) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, ifType := range ifTypes {
+			if ifc.IfType == ifType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns predicate satisfied by interfaces in any of 'statuses'.
+func OperStatusIs(statuses ...IfOperStatus) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, status := range statuses {
+			if ifc.OperStatus == status {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns predicate satisfied by interfaces of any of 'tunnelTypes'. Non-tunnel interfaces have TUNNEL_TYPE_NONE.
+func TunnelTypeIs(tunnelTypes ...TunnelType) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, tunnelType := range tunnelTypes {
+			if ifc.TunnelType == tunnelType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns predicate satisfied by tunnel interfaces, meaning interfaces of IF_TYPE_TUNNEL type or with TunnelType other
+// than TUNNEL_TYPE_NONE.
+func IsTunnel() InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return ifc.IfType == IF_TYPE_TUNNEL || ifc.TunnelType != TUNNEL_TYPE_NONE
+	}
+}
+
+// Returns predicate satisfied by interfaces of any of 'connectionTypes'.
+func ConnectionTypeIs(connectionTypes ...NetIfConnectionType) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, connectionType := range connectionTypes {
+			if ifc.ConnectionType == connectionType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns predicate satisfied by interfaces whose FriendlyName matches glob 'pattern' (case insensitive, '*' matches
+// any sequence of characters and '?' any single character).
+func FriendlyNameMatches(pattern string) InterfacePredicate {
+	re := globToRegexp(pattern)
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return re.MatchString(ifc.FriendlyName)
+	}
+}
+
+// Returns predicate satisfied by interfaces whose Description matches glob 'pattern'. See FriendlyNameMatches().
+func DescriptionMatches(pattern string) InterfacePredicate {
+	re := globToRegexp(pattern)
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return re.MatchString(ifc.Description)
+	}
+}
+
+func globToRegexp(pattern string) *regexp.Regexp {
+
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.Replace(quoted, `\*`, `.*`, -1)
+	quoted = strings.Replace(quoted, `\?`, `.`, -1)
+
+	return regexp.MustCompile(`(?is)^` + quoted + `$`)
+}
+
+// Returns predicate satisfied by interfaces which have at least one gateway address. Requires Interface structs to be
+// gotten with GAA_FLAG_INCLUDE_GATEWAYS flag, which SelectInterfaces() does.
+func HasGateway() InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return len(ifc.GatewayAddresses) > 0
+	}
+}
+
+// Returns predicate satisfied by interfaces which have at least one usable (preferred or deprecated) IPv6 unicast
+// address of global scope.
+func HasGlobalIPv6() InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		for _, address := range ifc.UnicastAddresses {
+
+			dadState := NlDadState(address.DadState)
+
+			if address.Address.Family != AF_INET6 ||
+				(dadState != IpDadStatePreferred && dadState != IpDadStateDeprecated) {
+				continue
+			}
+
+			ip := address.Address.Address.To16()
+
+			if ip != nil && ip.To4() == nil && addressScope(ip) == ScopeLevelGlobal {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Returns predicate satisfied by physical interfaces, according to IfRow's InterfaceAndOperStatusFlags.HardwareInterface.
+// Interfaces without IfRow aren't considered physical.
+func IsHardwareInterface() InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return ifrow != nil && ifrow.InterfaceAndOperStatusFlags.HardwareInterface
+	}
+}
+
+// Returns predicate satisfied by interfaces whose both transmit and receive link speeds are at least
+// 'bitsPerSecond'.
+func MinLinkSpeed(bitsPerSecond uint64) InterfacePredicate {
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		return ifc.TransmitLinkSpeed >= bitsPerSecond && ifc.ReceiveLinkSpeed >= bitsPerSecond
+	}
+}
+
+// Parses a compact filter expression into a predicate. The expression is a comma separated list of terms, all of which
+// have to be satisfied; a term prefixed with '!' is negated. Terms are either 'key=value' pairs:
+//
+//	type=<IfType>
+//	oper=<IfOperStatus>
+//	tunneltype=<TunnelType>
+//	conn=<NetIfConnectionType>
+//	name=<glob>
+//	desc=<glob>
+//	speed=<minimal link speed in bits per second, optionally with k, M or G suffix>
+//
+// or flags: 'tunnel', 'gateway', 'ipv6' (has global IPv6 address), 'hardware' and 'virtual' (the opposite of
+// 'hardware'). Enum values are parsed by their UnmarshalText() methods, so both constant names and short names (i.e.
+// "ethernet", "wifi", "up" or "teredo") can be used, and several of them may be separated by '|' to match any of
+// them. For example, "type=ethernet|wifi,oper=up,!tunnel" selects operational ethernet and Wi-Fi interfaces which aren't tunnels.
+func ParseInterfaceFilter(expression string) (InterfacePredicate, error) {
+
+	var predicates []InterfacePredicate
+
+	for _, term := range strings.Split(expression, ",") {
+
+		term = strings.TrimSpace(term)
+
+		if term == "" {
+			continue
+		}
+
+		negate := strings.HasPrefix(term, "!")
+
+		if negate {
+			term = strings.TrimSpace(term[1:])
+		}
+
+		predicate, err := parseInterfaceFilterTerm(term)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if negate {
+			predicate = Negate(predicate)
+		}
+
+		predicates = append(predicates, predicate)
+	}
+
+	return AllOf(predicates...), nil
+}
+
+func parseInterfaceFilterTerm(term string) (InterfacePredicate, error) {
+
+	eq := strings.Index(term, "=")
+
+	if eq < 0 {
+		switch strings.ToLower(term) {
+		case "tunnel":
+			return IsTunnel(), nil
+		case "gateway":
+			return HasGateway(), nil
+		case "ipv6":
+			return HasGlobalIPv6(), nil
+		case "hardware":
+			return IsHardwareInterface(), nil
+		case "virtual":
+			return Negate(IsHardwareInterface()), nil
+		default:
+			return nil, fmt.Errorf("ParseInterfaceFilter() - unknown flag '%s'", term)
+		}
+	}
+
+	key := strings.ToLower(strings.TrimSpace(term[:eq]))
+	value := strings.TrimSpace(term[eq+1:])
+
+	switch key {
+	case "type":
+		return enumFieldIs(ifTypeTable, value, func(ifc *Interface) int64 { return int64(ifc.IfType) })
+	case "oper":
+		return enumFieldIs(ifOperStatusTable, value, func(ifc *Interface) int64 { return int64(ifc.OperStatus) })
+	case "tunneltype":
+		return enumFieldIs(tunnelTypeTable, value, func(ifc *Interface) int64 { return int64(ifc.TunnelType) })
+	case "conn":
+		return enumFieldIs(netIfConnectionTypeTable, value, func(ifc *Interface) int64 {
+			return int64(ifc.ConnectionType)
+		})
+	case "name":
+		return FriendlyNameMatches(value), nil
+	case "desc":
+		return DescriptionMatches(value), nil
+	case "speed":
+		speed, err := parseLinkSpeed(value)
+		if err != nil {
+			return nil, err
+		}
+		return MinLinkSpeed(speed), nil
+	default:
+		return nil, fmt.Errorf("ParseInterfaceFilter() - unknown key '%s'", key)
+	}
+}
+
+// Returns predicate satisfied by interfaces whose 'field' has any of the '|' separated values in 'value', which are
+// parsed by 'table'.
+func enumFieldIs(table *enumTable, value string, field func(ifc *Interface) int64) (InterfacePredicate, error) {
+
+	var values []int64
+
+	for _, v := range strings.Split(value, "|") {
+
+		n, err := table.parse(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, n)
+	}
+
+	return func(ifc *Interface, ifrow *IfRow) bool {
+		actual := field(ifc)
+		for _, v := range values {
+			if actual == v {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func parseLinkSpeed(value string) (uint64, error) {
+
+	multiplier := uint64(1)
+
+	if len(value) > 0 {
+		switch value[len(value)-1] {
+		case 'k', 'K':
+			multiplier = 1000
+		case 'm', 'M':
+			multiplier = 1000 * 1000
+		case 'g', 'G':
+			multiplier = 1000 * 1000 * 1000
+		}
+	}
+
+	if multiplier != 1 {
+		value = value[:len(value)-1]
+	}
+
+	speed, err := strconv.ParseUint(value, 10, 64)
+
+	if err != nil {
+		return 0, fmt.Errorf("ParseInterfaceFilter() - invalid link speed '%s'", value)
+	}
+
+	if speed > math.MaxUint64/multiplier {
+		return 0, fmt.Errorf("ParseInterfaceFilter() - link speed '%s' overflows", value)
+	}
+
+	return speed * multiplier, nil
+}
@@ -0,0 +1,213 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"testing"
+)
+
+func interfaceSelectorTestData() ([]*Interface, []*IfRow) {
+
+	globalIPv6 := &UnicastAddress{DadState: IpDadState(IpDadStatePreferred)}
+	globalIPv6.Address = SockaddrInet{Family: AF_INET6, Address: net.ParseIP("2001:db8::1")}
+
+	linkLocal := &UnicastAddress{DadState: IpDadState(IpDadStatePreferred)}
+	linkLocal.Address = SockaddrInet{Family: AF_INET6, Address: net.ParseIP("fe80::1")}
+
+	ifcs := []*Interface{
+		{
+			Luid:              1,
+			FriendlyName:      "Ethernet 2",
+			Description:       "Intel(R) Ethernet Connection",
+			IfType:            IF_TYPE_ETHERNET_CSMACD,
+			OperStatus:        IfOperStatusUp,
+			TransmitLinkSpeed: 1000000000,
+			ReceiveLinkSpeed:  1000000000,
+			Ipv4Metric:        25,
+			Ipv6Metric:        25,
+			UnicastAddresses:  []*UnicastAddress{globalIPv6, linkLocal},
+			GatewayAddresses:  []*IpAdapterAddressCommonType{{}},
+		},
+		{
+			Luid:              2,
+			FriendlyName:      "Wi-Fi",
+			Description:       "Wireless Adapter",
+			IfType:            IF_TYPE_IEEE80211,
+			OperStatus:        IfOperStatusDown,
+			TransmitLinkSpeed: 54000000,
+			ReceiveLinkSpeed:  54000000,
+			Ipv4Metric:        50,
+			Ipv6Metric:        10,
+			UnicastAddresses:  []*UnicastAddress{linkLocal},
+		},
+		{
+			Luid:              3,
+			FriendlyName:      "wg0",
+			Description:       "WireGuard Tunnel",
+			IfType:            IF_TYPE_PROP_VIRTUAL,
+			OperStatus:        IfOperStatusUp,
+			TransmitLinkSpeed: 10000000000,
+			ReceiveLinkSpeed:  10000000000,
+			Ipv4Metric:        5,
+			Ipv6Metric:        5,
+		},
+		{
+			Luid:       4,
+			IfType:     IF_TYPE_TUNNEL,
+			TunnelType: TUNNEL_TYPE_TEREDO,
+			OperStatus: IfOperStatusUp,
+		},
+	}
+
+	ifrows := []*IfRow{
+		{InterfaceLuid: 1, InterfaceAndOperStatusFlags: InterfaceAndOperStatusFlags{HardwareInterface: true}},
+		{InterfaceLuid: 2, InterfaceAndOperStatusFlags: InterfaceAndOperStatusFlags{HardwareInterface: true}},
+		{InterfaceLuid: 3},
+	}
+
+	return ifcs, ifrows
+}
+
+func interfaceSelectorLuids(ifcs []*Interface) []uint64 {
+
+	luids := make([]uint64, len(ifcs))
+
+	for i, ifc := range ifcs {
+		luids[i] = ifc.Luid
+	}
+
+	return luids
+}
+
+func TestParseInterfaceFilter(t *testing.T) {
+
+	ifcs, ifrows := interfaceSelectorTestData()
+
+	vectors := []struct {
+		expression string
+		expected   []uint64
+	}{
+		{"", []uint64{1, 2, 3, 4}},
+		{"type=ethernet,oper=up,!tunnel", []uint64{1}},
+		{"type=ethernet|wifi", []uint64{1, 2}},
+		{"oper=up,!tunnel", []uint64{1, 3}},
+		{"tunnel", []uint64{4}},
+		{"tunneltype=teredo", []uint64{4}},
+		{"type=131", []uint64{4}},
+		{"name=wi-*", []uint64{2}},
+		{"desc=*tunnel", []uint64{3}},
+		{"gateway", []uint64{1}},
+		{"ipv6", []uint64{1}},
+		{"hardware", []uint64{1, 2}},
+		{"virtual", []uint64{3, 4}},
+		{"speed=1G", []uint64{1, 3}},
+		{" !hardware , oper=up , speed=10000M ", []uint64{3}},
+	}
+
+	for _, vector := range vectors {
+
+		predicate, err := ParseInterfaceFilter(vector.expression)
+
+		if err != nil {
+			t.Errorf("ParseInterfaceFilter(%q) returned an error: %v", vector.expression, err)
+			continue
+		}
+
+		selected := interfaceSelectorLuids(filterInterfaces(ifcs, ifrows, predicate))
+
+		if len(selected) != len(vector.expected) {
+			t.Errorf("ParseInterfaceFilter(%q) selected %v although %v is expected.", vector.expression, selected,
+				vector.expected)
+			continue
+		}
+
+		for i := range selected {
+			if selected[i] != vector.expected[i] {
+				t.Errorf("ParseInterfaceFilter(%q) selected %v although %v is expected.", vector.expression,
+					selected, vector.expected)
+				break
+			}
+		}
+	}
+
+	for _, expression := range []string{"type=foo", "bar", "speed=fast", "color=red", "speed=18446744073709552k",
+		"speed=18446744073709551615G"} {
+		if _, err := ParseInterfaceFilter(expression); err == nil {
+			t.Errorf("ParseInterfaceFilter(%q) didn't return an error.", expression)
+		}
+	}
+}
+
+func TestParseInterfaceFilterIfTypeNumber(t *testing.T) {
+
+	ifcs := []*Interface{
+		{Luid: 1, IfType: IF_TYPE_IEEE1394},
+		{Luid: 2, IfType: IfType(1394)},
+	}
+
+	// Plain decimal numbers are IfType values, never aliases.
+	predicate, err := ParseInterfaceFilter("type=1394")
+
+	if err != nil {
+		t.Fatalf("ParseInterfaceFilter() returned an error: %v", err)
+	}
+
+	if selected := interfaceSelectorLuids(filterInterfaces(ifcs, nil, predicate)); len(selected) != 1 ||
+		selected[0] != 2 {
+		t.Errorf("ParseInterfaceFilter(\"type=1394\") selected %v although [2] is expected.", selected)
+	}
+}
+
+func TestSortInterfacesByMetric(t *testing.T) {
+
+	ifcs, _ := interfaceSelectorTestData()
+
+	if err := SortInterfacesByMetric(ifcs, AF_INET6); err != nil {
+		t.Fatalf("SortInterfacesByMetric() returned an error: %v", err)
+	}
+
+	luids := interfaceSelectorLuids(ifcs)
+
+	if luids[0] != 4 || luids[1] != 3 || luids[2] != 2 || luids[3] != 1 {
+		t.Errorf("SortInterfacesByMetric() sorted interfaces as %v although [4 3 2 1] is expected.", luids)
+	}
+
+	if err := SortInterfacesByMetric(ifcs, AF_UNSPEC); err == nil {
+		t.Error("SortInterfacesByMetric() didn't return an error for AF_UNSPEC.")
+	}
+}
+
+func TestPredicateCombinators(t *testing.T) {
+
+	ifc := &Interface{Luid: 1, IfType: IF_TYPE_ETHERNET_CSMACD, OperStatus: IfOperStatusUp}
+
+	ethernet := IfTypeIs(IF_TYPE_ETHERNET_CSMACD)
+	wifi := IfTypeIs(IF_TYPE_IEEE80211)
+
+	tests := []struct {
+		name      string
+		predicate InterfacePredicate
+		expected  bool
+	}{
+		{"AllOf()", AllOf(), true},
+		{"AllOf(ethernet, up)", AllOf(ethernet, OperStatusIs(IfOperStatusUp)), true},
+		{"AllOf(ethernet, wifi)", AllOf(ethernet, wifi), false},
+		{"AnyOf()", AnyOf(), false},
+		{"AnyOf(wifi, ethernet)", AnyOf(wifi, ethernet), true},
+		{"Negate(ethernet)", Negate(ethernet), false},
+		{"Negate(wifi)", Negate(wifi), true},
+	}
+
+	for _, test := range tests {
+		if test.predicate(ifc, nil) != test.expected {
+			t.Errorf("%s returned %v although %v is expected.", test.name, !test.expected, test.expected)
+		}
+	}
+}
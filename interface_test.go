@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -199,6 +202,41 @@ func TestInterfaceFromGUID(t *testing.T) {
 	}
 }
 
+func TestLuidConversions(t *testing.T) {
+
+	luid := Luid(existingLuid)
+
+	index, err := luid.Index()
+
+	if err != nil {
+		t.Fatalf("Luid.Index() returned an error: %v. Have you forgot to set existingLuid appropriately?", err)
+	}
+
+	if converted, err := LuidFromIndex(index); err != nil || converted != luid {
+		t.Errorf("LuidFromIndex() returned (%s, %v) instead of %s.", converted.String(), err, luid.String())
+	}
+
+	alias, err := luid.Alias()
+
+	if err != nil {
+		t.Fatalf("Luid.Alias() returned an error: %v", err)
+	}
+
+	if converted, err := LuidFromAlias(alias); err != nil || converted != luid {
+		t.Errorf("LuidFromAlias() returned (%s, %v) instead of %s.", converted.String(), err, luid.String())
+	}
+
+	guid, err := luid.GUID()
+
+	if err != nil {
+		t.Fatalf("Luid.GUID() returned an error: %v", err)
+	}
+
+	if converted, err := LuidFromGUID(guid); err != nil || converted != luid {
+		t.Errorf("LuidFromGUID() returned (%s, %v) instead of %s.", converted.String(), err, luid.String())
+	}
+}
+
 func TestInterface_GetData(t *testing.T) {
 
 	ifc, err := InterfaceFromLUID(existingLuid)
@@ -0,0 +1,281 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Readiness condition of an interface, used by WaitForInterface().
+type InterfaceCondition struct {
+	// Describes the condition in error messages, i.e. "operational status is IfOperStatusUp".
+	Description string
+
+	Predicate InterfacePredicate
+}
+
+// Returns condition satisfied if the interface's OperStatus is 'status'.
+func OperStatusCondition(status IfOperStatus) InterfaceCondition {
+	return InterfaceCondition{
+		Description: fmt.Sprintf("operational status is %s", status.String()),
+		Predicate:   OperStatusIs(status),
+	}
+}
+
+// Returns condition satisfied if the interface has unicast address 'ip' in IpDadStatePreferred DAD state.
+func AddressPreferredCondition(ip net.IP) InterfaceCondition {
+	return InterfaceCondition{
+		Description: fmt.Sprintf("address %s is in DAD state %s", ip.String(), IpDadStatePreferred.String()),
+		Predicate: func(ifc *Interface, ifrow *IfRow) bool {
+			for _, address := range ifc.UnicastAddresses {
+				if address.Address.Address.Equal(ip) && NlDadState(address.DadState) == IpDadStatePreferred {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// Returns condition satisfied if the interface has IpInterface rows for all of 'families' (AF_INET and/or AF_INET6).
+func IpInterfaceCondition(families ...AddressFamily) InterfaceCondition {
+
+	names := make([]string, len(families))
+
+	for i, family := range families {
+		names[i] = family.String()
+	}
+
+	return InterfaceCondition{
+		Description: fmt.Sprintf("IpInterface exists for %s", strings.Join(names, " and ")),
+		Predicate: func(ifc *Interface, ifrow *IfRow) bool {
+			for _, family := range families {
+				if _, err := GetIpInterface(ifc.Luid, family); err != nil {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// Returned by WaitForInterface() if the context is done before the interface is ready.
+type WaitForInterfaceError struct {
+	// The interface matching the selector which satisfied the most conditions, as of the last successful check; nil if
+	// no interface matched it.
+	Interface *Interface
+
+	// Descriptions of the conditions 'Interface' didn't satisfy at the last successful check.
+	Unmet []string
+
+	// The context's error.
+	Err error
+
+	// Error of the last check if it has failed (i.e. because listing the interfaces failed), nil otherwise.
+	LastErr error
+}
+
+func (e *WaitForInterfaceError) Error() string {
+
+	message := fmt.Sprintf("WaitForInterface() - %v; unmet conditions: %s", e.Err, strings.Join(e.Unmet, "; "))
+
+	if e.LastErr != nil {
+		message += fmt.Sprintf("; last check failed: %v", e.LastErr)
+	}
+
+	return message
+}
+
+// How often WaitForInterface() checks the conditions regardless of change notifications, which don't cover all the
+// conditions (i.e. IpInterfaceCondition()) and which may not be available at all.
+const waitForInterfacePollInterval = time.Second
+
+// Waits until an interface satisfying 'selector' exists and satisfies all 'conditions', and returns it. If several
+// interfaces satisfy 'selector', the first one satisfying all the conditions is returned. Conditions are rechecked
+// whenever an interface or unicast address change notification arrives, and at least once a second otherwise. Checks
+// which fail (i.e. because listing the interfaces fails) are retried the same way. If 'ctx' is done first, returned
+// error is *WaitForInterfaceError, listing the conditions which were still unmet, and the error of the last check.
+func WaitForInterface(ctx context.Context, selector InterfacePredicate,
+	conditions ...InterfaceCondition) (*Interface, error) {
+
+	waiter := &interfaceWaiter{
+		selector:     selector,
+		conditions:   conditions,
+		pollInterval: waitForInterfacePollInterval,
+		clock:        SystemClock,
+		getInterfaces: func() ([]*Interface, []*IfRow, error) {
+
+			flags := DefaultGetAdapterAddressesFlags()
+			flags.GAA_FLAG_INCLUDE_GATEWAYS = true
+
+			ifcs, err := GetInterfacesEx(flags)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			ifrows, err := GetIfRows(MibIfEntryNormalWithoutStatistics)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return ifcs, ifrows, nil
+		},
+		register: registerInterfaceWaiterCallbacks,
+	}
+
+	return waiter.wait(ctx)
+}
+
+type interfaceWaiter struct {
+	selector     InterfacePredicate
+	conditions   []InterfaceCondition
+	pollInterval time.Duration
+	clock        Clock
+
+	// Replaceable for tests.
+	getInterfaces func() ([]*Interface, []*IfRow, error)
+	register      func(notify func()) (unregister func(), err error)
+}
+
+// Registers 'notify' for both interface and unicast address change notifications.
+func registerInterfaceWaiterCallbacks(notify func()) (func(), error) {
+
+	interfaceChangeRegistration, err := RegisterInterfaceChangeCallback(
+		func(notificationType MibNotificationType, interfaceLuid uint64) { notify() })
+
+	if err != nil {
+		return nil, err
+	}
+
+	addressChangeRegistration, err := RegisterUnicastAddressChangeCallback(
+		func(notificationType MibNotificationType, interfaceLuid uint64, ip *net.IP) { notify() })
+
+	if err != nil {
+		interfaceChangeRegistration.Unregister()
+		return nil, err
+	}
+
+	return func() {
+		interfaceChangeRegistration.Unregister()
+		addressChangeRegistration.Unregister()
+	}, nil
+}
+
+func (w *interfaceWaiter) wait(ctx context.Context) (*Interface, error) {
+
+	changed := make(chan struct{}, 1)
+
+	poke := func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+
+	// Without notifications, polling alone still does the job.
+	unregister, err := w.register(poke)
+
+	if err == nil {
+		defer unregister()
+	}
+
+	var (
+		ifc     *Interface
+		unmet   []string
+		lastErr error
+	)
+
+	for {
+
+		checked, checkedUnmet, err := w.check()
+
+		if err == nil {
+
+			if len(checkedUnmet) < 1 {
+				return checked, nil
+			}
+
+			ifc = checked
+			unmet = checkedUnmet
+		}
+
+		lastErr = err
+
+		timer := w.clock.AfterFunc(w.pollInterval, poke)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, &WaitForInterfaceError{Interface: ifc, Unmet: unmet, Err: ctx.Err(), LastErr: lastErr}
+		case <-changed:
+		}
+
+		timer.Stop()
+	}
+}
+
+// Checks all the interfaces satisfying the selector. Returns the first one satisfying all the conditions if there is
+// one; otherwise the one satisfying the most conditions and descriptions of the conditions it doesn't satisfy.
+func (w *interfaceWaiter) check() (*Interface, []string, error) {
+
+	ifcs, ifrows, err := w.getInterfaces()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ifrowsByLuid := make(map[uint64]*IfRow, len(ifrows))
+
+	for _, ifrow := range ifrows {
+		ifrowsByLuid[ifrow.InterfaceLuid] = ifrow
+	}
+
+	var (
+		best      *Interface
+		bestUnmet []string
+	)
+
+	for _, ifc := range ifcs {
+
+		ifrow := ifrowsByLuid[ifc.Luid]
+
+		if !w.selector(ifc, ifrow) {
+			continue
+		}
+
+		unmet := make([]string, 0)
+
+		for _, condition := range w.conditions {
+			if !condition.Predicate(ifc, ifrow) {
+				unmet = append(unmet, condition.Description)
+			}
+		}
+
+		if len(unmet) < 1 {
+			return ifc, unmet, nil
+		}
+
+		if best == nil || len(unmet) < len(bestUnmet) {
+			best = ifc
+			bestUnmet = unmet
+		}
+	}
+
+	if best == nil {
+		return nil, []string{"an interface matching the selector exists"}, nil
+	}
+
+	return best, bestUnmet, nil
+}
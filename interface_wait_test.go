@@ -0,0 +1,318 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Interfaces seen by interfaceWaiter in tests, changed by the test while the waiter waits.
+type interfaceWaiterTestState struct {
+	mutex  sync.Mutex
+	ifcs   []*Interface
+	notify func()
+	checks int
+	// Returned by checks while it's not nil.
+	err error
+}
+
+func (s *interfaceWaiterTestState) set(ifcs ...*Interface) {
+
+	s.mutex.Lock()
+	s.ifcs = ifcs
+	notify := s.notify
+	s.mutex.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+func (s *interfaceWaiterTestState) waiter(pollInterval time.Duration, registerErr error,
+	conditions ...InterfaceCondition) *interfaceWaiter {
+
+	return &interfaceWaiter{
+		selector:     FriendlyNameMatches("wg*"),
+		conditions:   conditions,
+		pollInterval: pollInterval,
+		clock:        SystemClock,
+		getInterfaces: func() ([]*Interface, []*IfRow, error) {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			s.checks++
+			if s.err != nil {
+				return nil, nil, s.err
+			}
+			return s.ifcs, nil, nil
+		},
+		register: func(notify func()) (func(), error) {
+			if registerErr != nil {
+				return nil, registerErr
+			}
+			s.mutex.Lock()
+			s.notify = notify
+			s.mutex.Unlock()
+			return func() {
+				s.mutex.Lock()
+				s.notify = nil
+				s.mutex.Unlock()
+			}, nil
+		},
+	}
+}
+
+func interfaceWaiterTestInterface(operStatus IfOperStatus, dadState NlDadState) *Interface {
+	return &Interface{
+		Luid:         1 << 24,
+		FriendlyName: "wg0",
+		OperStatus:   operStatus,
+		UnicastAddresses: []*UnicastAddress{
+			{
+				IpAdapterAddressCommonTypeEx: IpAdapterAddressCommonTypeEx{
+					IpAdapterAddressCommonType: IpAdapterAddressCommonType{
+						Address: SockaddrInet{Family: AF_INET, Address: net.IPv4(10, 8, 0, 2)},
+					},
+				},
+				DadState: IpDadState(dadState),
+			},
+		},
+	}
+}
+
+func TestWaitForInterface(t *testing.T) {
+
+	state := &interfaceWaiterTestState{}
+	waiter := state.waiter(time.Hour, nil, OperStatusCondition(IfOperStatusUp),
+		AddressPreferredCondition(net.IPv4(10, 8, 0, 2)))
+
+	type result struct {
+		ifc *Interface
+		err error
+	}
+
+	results := make(chan result, 1)
+
+	go func() {
+		ifc, err := waiter.wait(context.Background())
+		results <- result{ifc, err}
+	}()
+
+	// Notifications trigger checks; polling doesn't, given the interval.
+	waitForChecks := func(checks int) {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			state.mutex.Lock()
+			done := state.checks >= checks && state.notify != nil
+			state.mutex.Unlock()
+			if done {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("interfaceWaiter hasn't done %d checks.", checks)
+	}
+
+	waitForChecks(1)
+	state.set(&Interface{Luid: 2 << 24, FriendlyName: "Ethernet", OperStatus: IfOperStatusUp})
+	waitForChecks(2)
+	state.set(interfaceWaiterTestInterface(IfOperStatusDown, IpDadStateTentative))
+	waitForChecks(3)
+	state.set(interfaceWaiterTestInterface(IfOperStatusUp, IpDadStateTentative))
+	waitForChecks(4)
+
+	select {
+	case r := <-results:
+		t.Fatalf("interfaceWaiter.wait() returned (%v, %v) before all the conditions were met.", r.ifc, r.err)
+	default:
+	}
+
+	ready := interfaceWaiterTestInterface(IfOperStatusUp, IpDadStatePreferred)
+	state.set(ready)
+
+	select {
+	case r := <-results:
+		if r.err != nil || r.ifc != ready {
+			t.Errorf("interfaceWaiter.wait() returned (%v, %v).", r.ifc, r.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interfaceWaiter.wait() hasn't returned after all the conditions were met.")
+	}
+
+	if state.notify != nil {
+		t.Error("interfaceWaiter.wait() hasn't unregistered its callbacks.")
+	}
+}
+
+func TestWaitForInterfaceTimeout(t *testing.T) {
+
+	state := &interfaceWaiterTestState{}
+	state.set(interfaceWaiterTestInterface(IfOperStatusUp, IpDadStateTentative))
+
+	waiter := state.waiter(time.Hour, nil, OperStatusCondition(IfOperStatusUp),
+		AddressPreferredCondition(net.IPv4(10, 8, 0, 2)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	ifc, err := waiter.wait(ctx)
+
+	waitErr, ok := err.(*WaitForInterfaceError)
+
+	if ifc != nil || !ok {
+		t.Fatalf("interfaceWaiter.wait() returned (%v, %v) instead of *WaitForInterfaceError.", ifc, err)
+	}
+
+	if len(waitErr.Unmet) != 1 || waitErr.Err != context.DeadlineExceeded || waitErr.Interface == nil {
+		t.Errorf("Unexpected WaitForInterfaceError: %+v", waitErr)
+	}
+
+	if !strings.Contains(err.Error(), "address 10.8.0.2 is in DAD state IpDadStatePreferred") {
+		t.Errorf("Error message doesn't name the unmet condition: %v", err)
+	}
+
+	// No interface matching the selector.
+	state.set()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err = waiter.wait(ctx); err == nil || !strings.Contains(err.Error(), "matching the selector") {
+		t.Errorf("interfaceWaiter.wait() returned unexpected error: %v", err)
+	}
+}
+
+func TestWaitForInterfacePolling(t *testing.T) {
+
+	clock := newFakeClock()
+	state := &interfaceWaiterTestState{err: fmt.Errorf("listing interfaces failed")}
+	waiter := state.waiter(time.Second, fmt.Errorf("notifications unavailable"),
+		OperStatusCondition(IfOperStatusUp))
+	waiter.clock = clock
+
+	results := make(chan error, 1)
+
+	go func() {
+		ifc, err := waiter.wait(context.Background())
+		if err == nil && ifc == nil {
+			err = fmt.Errorf("no interface")
+		}
+		results <- err
+	}()
+
+	// Waits until the waiter has done 'checks' checks and armed its poll timer.
+	waitForPoll := func(checks int) {
+		for deadline := time.Now().Add(5 * time.Second); clock.pendingTimers() < 1 || state.checksDone() < checks; {
+			if time.Now().After(deadline) {
+				t.Fatalf("interfaceWaiter hasn't done %d checks.", checks)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	// The failed check is retried, finding no interface; the next one finds it.
+	waitForPoll(1)
+	state.mutex.Lock()
+	state.err = nil
+	state.mutex.Unlock()
+	clock.advance(time.Second)
+	waitForPoll(2)
+	state.set(interfaceWaiterTestInterface(IfOperStatusUp, IpDadStatePreferred))
+	clock.advance(time.Second)
+
+	select {
+	case err := <-results:
+		if err != nil {
+			t.Errorf("interfaceWaiter.wait() returned %v without notifications.", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("interfaceWaiter.wait() hasn't returned after polling.")
+	}
+}
+
+func (s *interfaceWaiterTestState) checksDone() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.checks
+}
+
+func TestWaitForInterfaceCheckError(t *testing.T) {
+
+	state := &interfaceWaiterTestState{}
+	state.set(interfaceWaiterTestInterface(IfOperStatusDown, IpDadStatePreferred))
+
+	waiter := state.waiter(time.Millisecond, nil, OperStatusCondition(IfOperStatusUp))
+
+	// A successful check is followed by failing ones; the error reports both.
+	waiter.getInterfaces = func() ([]*Interface, []*IfRow, error) {
+		state.mutex.Lock()
+		defer state.mutex.Unlock()
+		state.checks++
+		if state.checks > 1 {
+			return nil, nil, fmt.Errorf("listing interfaces failed")
+		}
+		return state.ifcs, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := waiter.wait(ctx)
+
+	waitErr, ok := err.(*WaitForInterfaceError)
+
+	if !ok || waitErr.LastErr == nil || waitErr.Interface == nil || len(waitErr.Unmet) != 1 {
+		t.Fatalf("interfaceWaiter.wait() returned %#v instead of *WaitForInterfaceError with the last error.", err)
+	}
+
+	if !strings.Contains(err.Error(), "listing interfaces failed") {
+		t.Errorf("Error message doesn't contain the last error: %v", err)
+	}
+
+	if state.checksDone() < 2 {
+		t.Errorf("interfaceWaiter.wait() didn't retry the failed check.")
+	}
+}
+
+func TestWaitForInterfaceSeveralMatches(t *testing.T) {
+
+	down := interfaceWaiterTestInterface(IfOperStatusDown, IpDadStateTentative)
+	up := interfaceWaiterTestInterface(IfOperStatusUp, IpDadStateTentative)
+	up.Luid = 2 << 24
+	up.FriendlyName = "wg1"
+	ready := interfaceWaiterTestInterface(IfOperStatusUp, IpDadStatePreferred)
+	ready.Luid = 3 << 24
+	ready.FriendlyName = "wg2"
+
+	state := &interfaceWaiterTestState{}
+	waiter := state.waiter(time.Hour, nil, OperStatusCondition(IfOperStatusUp),
+		AddressPreferredCondition(net.IPv4(10, 8, 0, 2)))
+
+	state.set(down, up, ready)
+
+	if ifc, err := waiter.wait(context.Background()); err != nil || ifc != ready {
+		t.Errorf("interfaceWaiter.wait() returned (%v, %v) instead of the ready interface.", ifc, err)
+	}
+
+	// Without a ready interface, the error names the one closest to being ready.
+	state.set(down, up)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := waiter.wait(ctx)
+
+	if waitErr, ok := err.(*WaitForInterfaceError); !ok || waitErr.Interface != up || len(waitErr.Unmet) != 1 {
+		t.Errorf("interfaceWaiter.wait() returned %v.", err)
+	}
+}
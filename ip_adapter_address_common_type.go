@@ -9,13 +9,13 @@ import "fmt"
 
 type IpAdapterAddressCommonType struct {
 	// The interface the address belongs to.
-	InterfaceLuid  uint64
-	InterfaceIndex uint32
+	InterfaceLuid  uint64 `json:"interfaceLuid"`
+	InterfaceIndex uint32 `json:"interfaceIndex"`
 
-	Length uint32
+	Length uint32 `json:"length"`
 
 	// The address.
-	Address SockaddrInet
+	Address SockaddrInet `json:"address"`
 }
 
 func ipAdapterAddressFromLengthAddress(ifc Interface, length uint32, wtsa *wtSocketAddress) (*IpAdapterAddressCommonType,
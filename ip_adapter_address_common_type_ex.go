@@ -11,7 +11,7 @@ type IpAdapterAddressCommonTypeEx struct {
 	// It extends IpAdapterAddressCommonType
 	IpAdapterAddressCommonType
 
-	Flags uint32
+	Flags uint32 `json:"flags"`
 }
 
 func ipAdapterAddressFromLengthFlagsAddress(ifc Interface, length uint32, flags uint32, wtsa *wtSocketAddress) (*IpAdapterAddressCommonTypeEx,
@@ -5,7 +5,10 @@
 
 package winipcfg
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 type IpAdapterPrefix struct {
 	IpAdapterAddressCommonTypeEx
@@ -14,6 +17,55 @@ type IpAdapterPrefix struct {
 	PrefixLength uint32
 }
 
+type ipAdapterPrefixJSON struct {
+	InterfaceLuid  uint64 `json:"interfaceLuid"`
+	InterfaceIndex uint32 `json:"interfaceIndex"`
+	Length         uint32 `json:"length"`
+	Flags          uint32 `json:"flags"`
+	Prefix         string `json:"prefix"`
+}
+
+// Encodes the prefix as an object whose "prefix" member is in CIDR notation, i.e. "fe80::%12/64".
+func (ap IpAdapterPrefix) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&ipAdapterPrefixJSON{
+		InterfaceLuid:  ap.InterfaceLuid,
+		InterfaceIndex: ap.InterfaceIndex,
+		Length:         ap.Length,
+		Flags:          ap.Flags,
+		Prefix:         fmt.Sprintf("%s/%d", ap.Address.jsonAddress(), ap.PrefixLength),
+	})
+}
+
+func (ap *IpAdapterPrefix) UnmarshalJSON(data []byte) error {
+
+	var apj ipAdapterPrefixJSON
+
+	if err := json.Unmarshal(data, &apj); err != nil {
+		return err
+	}
+
+	prefix, length, err := parseCIDRWithZone(apj.Prefix)
+
+	if err != nil {
+		return err
+	}
+
+	*ap = IpAdapterPrefix{
+		IpAdapterAddressCommonTypeEx: IpAdapterAddressCommonTypeEx{
+			IpAdapterAddressCommonType: IpAdapterAddressCommonType{
+				InterfaceLuid:  apj.InterfaceLuid,
+				InterfaceIndex: apj.InterfaceIndex,
+				Length:         apj.Length,
+				Address:        *prefix,
+			},
+			Flags: apj.Flags,
+		},
+		PrefixLength: uint32(length),
+	}
+
+	return nil
+}
+
 func (ap *IpAdapterPrefix) String() string {
 	if ap == nil {
 		return "<nil>"
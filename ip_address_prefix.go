@@ -6,8 +6,11 @@
 package winipcfg
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"strconv"
+	"strings"
 )
 
 type IpAddressPrefix struct {
@@ -60,6 +63,84 @@ func (ap *IpAddressPrefix) toWtIpAddressPrefix() (*wtIpAddressPrefix, error) {
 	}, nil
 }
 
+// Encodes the prefix as a CIDR string, with the zone (IPv6ScopeId) following the address, i.e. "fe80::%12/64". Zero
+// IpAddressPrefix is encoded as null.
+func (ap IpAddressPrefix) MarshalJSON() ([]byte, error) {
+
+	if ap.Prefix.Family == 0 && ap.Prefix.Address == nil && ap.PrefixLength == 0 {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(fmt.Sprintf("%s/%d", ap.Prefix.jsonAddress(), ap.PrefixLength))
+}
+
+func (ap *IpAddressPrefix) UnmarshalJSON(data []byte) error {
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var str string
+
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+
+	prefix, length, err := parseCIDRWithZone(str)
+
+	if err != nil {
+		return err
+	}
+
+	*ap = IpAddressPrefix{Prefix: *prefix, PrefixLength: length}
+
+	return nil
+}
+
+// Parses "<ip>[%<zone>]/<length>" strings.
+func parseCIDRWithZone(str string) (*SockaddrInet, uint8, error) {
+
+	slash := strings.LastIndexByte(str, '/')
+
+	if slash == -1 {
+		return nil, 0, fmt.Errorf("parseCIDRWithZone() - '%s' isn't in CIDR notation", str)
+	}
+
+	ip, zone, err := parseIPWithZone(str[:slash])
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sainet, err := createSockaddrInet(ip)
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// createSockaddrInet() returns 4-byte IPv4 addresses, while conversions from Windows structs return 16-byte ones.
+	sainet.Address = ip
+	sainet.IPv6ScopeId = zone
+
+	if isIPv6Text(str[:slash]) {
+		sainet.Family = AF_INET6
+	}
+
+	maxLength := 128
+
+	if sainet.Family == AF_INET {
+		maxLength = 32
+	}
+
+	length, err := strconv.ParseUint(str[slash+1:], 10, 8)
+
+	if err != nil || int(length) > maxLength {
+		return nil, 0, fmt.Errorf("parseCIDRWithZone() - invalid prefix length in '%s'", str)
+	}
+
+	return sainet, uint8(length), nil
+}
+
 func (ap *IpAddressPrefix) String() string {
 	if ap == nil {
 		return "<nil>"
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -13,70 +16,64 @@ type IpInterface struct {
 	//
 	// Key Structure;
 	//
-	Family         AddressFamily
-	InterfaceLuid  uint64
-	InterfaceIndex uint32
+	Family         AddressFamily `json:"family"`
+	InterfaceLuid  uint64        `json:"interfaceLuid"`
+	InterfaceIndex uint32        `json:"interfaceIndex"`
 
 	//
 	// Read-Write fields.
 	//
-
-	//
-	// Fields currently not exposed.
-	//
-	MaxReassemblySize              uint32
-	InterfaceIdentifier            uint64
-	MinRouterAdvertisementInterval uint32
-	MaxRouterAdvertisementInterval uint32
-
-	//
-	// Fileds currently exposed.
-	//
-	AdvertisingEnabled                   bool
-	ForwardingEnabled                    bool
-	WeakHostSend                         bool
-	WeakHostReceive                      bool
-	UseAutomaticMetric                   bool
-	UseNeighborUnreachabilityDetection   bool
-	ManagedAddressConfigurationSupported bool
-	OtherStatefulConfigurationSupported  bool
-	AdvertiseDefaultRoute                bool
-
-	RouterDiscoveryBehavior NlRouterDiscoveryBehavior
+	MaxReassemblySize   uint32 `json:"maxReassemblySize"`
+	InterfaceIdentifier uint64 `json:"interfaceIdentifier"`
+	// MinRtrAdvInterval and MaxRtrAdvInterval in RFC 4861 (in ms); see ValidateRouterAdvertisementIntervals().
+	MinRouterAdvertisementInterval uint32 `json:"minRouterAdvertisementInterval"`
+	MaxRouterAdvertisementInterval uint32 `json:"maxRouterAdvertisementInterval"`
+
+	AdvertisingEnabled                   bool `json:"advertisingEnabled"`
+	ForwardingEnabled                    bool `json:"forwardingEnabled"`
+	WeakHostSend                         bool `json:"weakHostSend"`
+	WeakHostReceive                      bool `json:"weakHostReceive"`
+	UseAutomaticMetric                   bool `json:"useAutomaticMetric"`
+	UseNeighborUnreachabilityDetection   bool `json:"useNeighborUnreachabilityDetection"`
+	ManagedAddressConfigurationSupported bool `json:"managedAddressConfigurationSupported"`
+	OtherStatefulConfigurationSupported  bool `json:"otherStatefulConfigurationSupported"`
+	AdvertiseDefaultRoute                bool `json:"advertiseDefaultRoute"`
+
+	RouterDiscoveryBehavior NlRouterDiscoveryBehavior `json:"routerDiscoveryBehavior"`
 	// DupAddrDetectTransmits in RFC 2462.
-	DadTransmits      uint32
-	BaseReachableTime uint32
-	RetransmitTime    uint32
+	DadTransmits      uint32 `json:"dadTransmits"`
+	BaseReachableTime uint32 `json:"baseReachableTime"`
+	RetransmitTime    uint32 `json:"retransmitTime"`
 	// Path MTU discovery timeout (in ms).
-	PathMtuDiscoveryTimeout uint32
+	PathMtuDiscoveryTimeout uint32 `json:"pathMtuDiscoveryTimeout"`
 
-	LinkLocalAddressBehavior NlLinkLocalAddressBehavior
+	LinkLocalAddressBehavior NlLinkLocalAddressBehavior `json:"linkLocalAddressBehavior"`
 	// In ms.
-	LinkLocalAddressTimeout uint32
+	LinkLocalAddressTimeout uint32 `json:"linkLocalAddressTimeout"`
 	// Zone part of a SCOPE_ID.
-	ZoneIndices      [ScopeLevelCount]uint32
-	SitePrefixLength uint32
-	Metric           uint32
-	NlMtu            uint32
+	ZoneIndices      [ScopeLevelCount]uint32 `json:"zoneIndices"`
+	SitePrefixLength uint32                  `json:"sitePrefixLength"`
+	Metric           uint32                  `json:"metric"`
+	NlMtu            uint32                  `json:"nlMtu"`
 
 	//
 	// Read Only fields.
 	//
-	Connected                 bool
-	SupportsWakeUpPatterns    bool
-	SupportsNeighborDiscovery bool
-	SupportsRouterDiscovery   bool
+	Connected                 bool `json:"connected"`
+	SupportsWakeUpPatterns    bool `json:"supportsWakeUpPatterns"`
+	SupportsNeighborDiscovery bool `json:"supportsNeighborDiscovery"`
+	SupportsRouterDiscovery   bool `json:"supportsRouterDiscovery"`
 
-	ReachableTime uint32
+	ReachableTime uint32 `json:"reachableTime"`
 
-	TransmitOffload NlInterfaceOffloadRodFlags
-	ReceiveOffload  NlInterfaceOffloadRodFlags
+	TransmitOffload NlInterfaceOffloadRodFlags `json:"transmitOffload"`
+	ReceiveOffload  NlInterfaceOffloadRodFlags `json:"receiveOffload"`
 
 	//
 	// Disables using default route on the interface. This flag
 	// can be used by VPN clients to restrict Split tunnelling.
 	//
-	DisableDefaultRoutes bool
+	DisableDefaultRoutes bool `json:"disableDefaultRoutes"`
 }
 
 // Corresponds to GetIpInterfaceTable function
@@ -119,7 +116,8 @@ func GetIpInterface(interfaceLuid uint64, family AddressFamily) (*IpInterface, e
 //
 // Note that fields Family, InterfaceLuid and InterfaceIndex are used for identifying address to change, meaning that
 // they cannot be changed by using this method. Changing some of these fields would cause updating some other IP
-// interface. Fields which are "changeable" by this method are between AdvertisingEnabled and NlMtu, inclusive.
+// interface. Fields which are "changeable" by this method are between MaxReassemblySize and NlMtu, inclusive, and
+// DisableDefaultRoutes.
 // The workflow of using this method is:
 // 1) Get IpInterface instance by using any of getter methods (i.e. GetIpInterface or any other);
 // 2) Change one or more of "changeable" fields enumerated above;
@@ -132,34 +130,45 @@ func (ipifc *IpInterface) Set() error {
 		return err
 	}
 
-	old.AdvertisingEnabled = boolToUint8(ipifc.AdvertisingEnabled)
-	old.ForwardingEnabled = boolToUint8(ipifc.ForwardingEnabled)
-	old.WeakHostSend = boolToUint8(ipifc.WeakHostSend)
-	old.WeakHostReceive = boolToUint8(ipifc.WeakHostReceive)
-	old.UseAutomaticMetric = boolToUint8(ipifc.UseAutomaticMetric)
-	old.UseNeighborUnreachabilityDetection = boolToUint8(ipifc.UseNeighborUnreachabilityDetection)
-	old.ManagedAddressConfigurationSupported = boolToUint8(ipifc.ManagedAddressConfigurationSupported)
-	old.OtherStatefulConfigurationSupported = boolToUint8(ipifc.OtherStatefulConfigurationSupported)
-	old.AdvertiseDefaultRoute = boolToUint8(ipifc.AdvertiseDefaultRoute)
-	old.RouterDiscoveryBehavior = ipifc.RouterDiscoveryBehavior
-	old.DadTransmits = ipifc.DadTransmits
-	old.BaseReachableTime = ipifc.BaseReachableTime
-	old.RetransmitTime = ipifc.RetransmitTime
-	old.PathMtuDiscoveryTimeout = ipifc.PathMtuDiscoveryTimeout
-	old.LinkLocalAddressBehavior = ipifc.LinkLocalAddressBehavior
-	old.LinkLocalAddressTimeout = ipifc.LinkLocalAddressTimeout
-	old.ZoneIndices = ipifc.ZoneIndices
-	old.SitePrefixLength = ipifc.SitePrefixLength
-	old.Metric = ipifc.Metric
-	old.NlMtu = ipifc.NlMtu
+	ipifc.copyChangeableFieldsTo(old)
+
+	return old.set()
+}
+
+// Copies fields which are "changeable" by Set() method to 'row'.
+func (ipifc *IpInterface) copyChangeableFieldsTo(row *wtMibIpinterfaceRow) {
+
+	row.MaxReassemblySize = ipifc.MaxReassemblySize
+	row.InterfaceIdentifier = ipifc.InterfaceIdentifier
+	row.MinRouterAdvertisementInterval = ipifc.MinRouterAdvertisementInterval
+	row.MaxRouterAdvertisementInterval = ipifc.MaxRouterAdvertisementInterval
+	row.AdvertisingEnabled = boolToUint8(ipifc.AdvertisingEnabled)
+	row.ForwardingEnabled = boolToUint8(ipifc.ForwardingEnabled)
+	row.WeakHostSend = boolToUint8(ipifc.WeakHostSend)
+	row.WeakHostReceive = boolToUint8(ipifc.WeakHostReceive)
+	row.UseAutomaticMetric = boolToUint8(ipifc.UseAutomaticMetric)
+	row.UseNeighborUnreachabilityDetection = boolToUint8(ipifc.UseNeighborUnreachabilityDetection)
+	row.ManagedAddressConfigurationSupported = boolToUint8(ipifc.ManagedAddressConfigurationSupported)
+	row.OtherStatefulConfigurationSupported = boolToUint8(ipifc.OtherStatefulConfigurationSupported)
+	row.AdvertiseDefaultRoute = boolToUint8(ipifc.AdvertiseDefaultRoute)
+	row.RouterDiscoveryBehavior = ipifc.RouterDiscoveryBehavior
+	row.DadTransmits = ipifc.DadTransmits
+	row.BaseReachableTime = ipifc.BaseReachableTime
+	row.RetransmitTime = ipifc.RetransmitTime
+	row.PathMtuDiscoveryTimeout = ipifc.PathMtuDiscoveryTimeout
+	row.LinkLocalAddressBehavior = ipifc.LinkLocalAddressBehavior
+	row.LinkLocalAddressTimeout = ipifc.LinkLocalAddressTimeout
+	row.ZoneIndices = ipifc.ZoneIndices
+	row.SitePrefixLength = ipifc.SitePrefixLength
+	row.Metric = ipifc.Metric
+	row.NlMtu = ipifc.NlMtu
+	row.DisableDefaultRoutes = boolToUint8(ipifc.DisableDefaultRoutes)
 
 	// Patch that fixes SitePrefixLength issue
 	// (https://stackoverflow.com/questions/54857292/setipinterfaceentry-returns-error-invalid-parameter?noredirect=1)
-	if old.SitePrefixLength > 128 || (old.SitePrefixLength > 32 && old.Family == AF_INET) {
-		old.SitePrefixLength = 0
+	if row.SitePrefixLength > 128 || (row.SitePrefixLength > 32 && row.Family == AF_INET) {
+		row.SitePrefixLength = 0
 	}
-
-	return old.set()
 }
 
 func (mir *IpInterface) String() string {
@@ -0,0 +1,286 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Named bundle of IpInterface settings which are applied together, i.e. to put a tunnel interface into a consistent
+// state. Updates are per address family (AF_INET and/or AF_INET6).
+type IpInterfaceProfile struct {
+	Name    string
+	Updates map[AddressFamily]*IpInterfaceUpdate
+}
+
+// Names of the predefined profiles.
+const (
+	// Tunnel carrying all the traffic: default routes are allowed, and neither forwarding nor the weak host model is
+	// used, so that packets can't bypass the tunnel through another interface.
+	IpInterfaceProfileFullTunnel = "full-tunnel"
+
+	// Tunnel carrying traffic of specific routes only: like IpInterfaceProfileFullTunnel, except that default routes
+	// on the interface are disabled.
+	IpInterfaceProfileSplitTunnel = "split-tunnel"
+
+	// Interface forwarding traffic between the tunnel and other interfaces: forwarding and the weak host model are
+	// enabled.
+	IpInterfaceProfileRouter = "router"
+)
+
+func newBool(b bool) *bool {
+	return &b
+}
+
+func newUint32(u uint32) *uint32 {
+	return &u
+}
+
+func newRouterDiscoveryBehavior(rdb NlRouterDiscoveryBehavior) *NlRouterDiscoveryBehavior {
+	return &rdb
+}
+
+// Returns updates of the tunnel profiles, which only differ in DisableDefaultRoutes. Router discovery and DAD are
+// turned off, since the tunnel's addresses and routes are configured explicitly.
+func tunnelProfileUpdates(disableDefaultRoutes bool) map[AddressFamily]*IpInterfaceUpdate {
+
+	updates := make(map[AddressFamily]*IpInterfaceUpdate)
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+		updates[family] = &IpInterfaceUpdate{
+			ForwardingEnabled:       newBool(false),
+			WeakHostSend:            newBool(false),
+			WeakHostReceive:         newBool(false),
+			AdvertisingEnabled:      newBool(false),
+			RouterDiscoveryBehavior: newRouterDiscoveryBehavior(RouterDiscoveryDisabled),
+			DadTransmits:            newUint32(0),
+			DisableDefaultRoutes:    newBool(disableDefaultRoutes),
+		}
+	}
+
+	updates[AF_INET6].ManagedAddressConfigurationSupported = newBool(false)
+	updates[AF_INET6].OtherStatefulConfigurationSupported = newBool(false)
+
+	return updates
+}
+
+var ipInterfaceProfiles = map[string]func() *IpInterfaceProfile{
+	IpInterfaceProfileFullTunnel: func() *IpInterfaceProfile {
+		return &IpInterfaceProfile{Name: IpInterfaceProfileFullTunnel, Updates: tunnelProfileUpdates(false)}
+	},
+	IpInterfaceProfileSplitTunnel: func() *IpInterfaceProfile {
+		return &IpInterfaceProfile{Name: IpInterfaceProfileSplitTunnel, Updates: tunnelProfileUpdates(true)}
+	},
+	IpInterfaceProfileRouter: func() *IpInterfaceProfile {
+
+		updates := make(map[AddressFamily]*IpInterfaceUpdate)
+
+		for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+			updates[family] = &IpInterfaceUpdate{
+				ForwardingEnabled: newBool(true),
+				WeakHostSend:      newBool(true),
+				WeakHostReceive:   newBool(true),
+			}
+		}
+
+		return &IpInterfaceProfile{Name: IpInterfaceProfileRouter, Updates: updates}
+	},
+}
+
+// Returns names of the predefined profiles, sorted.
+func IpInterfaceProfileNames() []string {
+
+	names := make([]string, 0, len(ipInterfaceProfiles))
+
+	for name := range ipInterfaceProfiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Returns predefined profile named 'name'. Each call returns a new instance, which the caller is free to modify.
+func GetIpInterfaceProfile(name string) (*IpInterfaceProfile, error) {
+
+	newProfile, ok := ipInterfaceProfiles[name]
+
+	if !ok {
+		return nil, fmt.Errorf("GetIpInterfaceProfile() - unknown profile \"%s\"; known profiles are: %s", name,
+			strings.Join(IpInterfaceProfileNames(), ", "))
+	}
+
+	return newProfile(), nil
+}
+
+// Checks the profile's updates against the rules of their families; see IpInterfaceUpdate.Validate().
+func (profile *IpInterfaceProfile) Validate() error {
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+
+		update, ok := profile.Updates[family]
+
+		if !ok {
+			continue
+		}
+
+		if err := update.Validate(family); err != nil {
+			return fmt.Errorf("IpInterfaceProfile.Validate() - profile \"%s\", %s: %v", profile.Name, family.String(),
+				err)
+		}
+	}
+
+	for family := range profile.Updates {
+		if family != AF_INET && family != AF_INET6 {
+			return fmt.Errorf("IpInterfaceProfile.Validate() - profile \"%s\" has update for %s", profile.Name,
+				family.String())
+		}
+	}
+
+	return nil
+}
+
+// Applies the profile to the interface with LUID 'interfaceLuid'. Families the interface has no IpInterface for are
+// skipped. If applying the profile to one of the families fails, families it was already applied to are restored.
+// Returned snapshot holds the IpInterfaces as they were before the profile was applied, and can be used to revert
+// the profile (see IpInterfaceSnapshot.Restore()).
+func (profile *IpInterfaceProfile) Apply(interfaceLuid uint64) (*IpInterfaceSnapshot, error) {
+	return defaultIpInterfaceProfileApplier.apply(profile, interfaceLuid)
+}
+
+// IpInterfaces of an interface as they were at some point. It can be serialized by json.Marshal() and stored, so that
+// the interface can be restored even after the process which changed it has crashed.
+type IpInterfaceSnapshot struct {
+	InterfaceLuid uint64         `json:"interfaceLuid"`
+	IpInterfaces  []*IpInterface `json:"ipInterfaces"`
+}
+
+// Returns snapshot of the interface's current IpInterfaces of 'families'. Families the interface has no IpInterface
+// for are skipped.
+func TakeIpInterfaceSnapshot(interfaceLuid uint64, families ...AddressFamily) (*IpInterfaceSnapshot, error) {
+	return defaultIpInterfaceProfileApplier.snapshot(interfaceLuid, families)
+}
+
+// Saves the IpInterfaces held by the snapshot (see IpInterface.Set()). All the IpInterfaces are restored even if some
+// of them fail; returned error lists all the failures.
+func (snapshot *IpInterfaceSnapshot) Restore() error {
+	return defaultIpInterfaceProfileApplier.restore(snapshot)
+}
+
+type ipInterfaceProfileApplier struct {
+	// Replaceable for tests.
+	get    func(interfaceLuid uint64, family AddressFamily) (*IpInterface, error)
+	update func(update *IpInterfaceUpdate, interfaceLuid uint64, family AddressFamily) error
+	set    func(ipifc *IpInterface) error
+}
+
+var defaultIpInterfaceProfileApplier = &ipInterfaceProfileApplier{
+	get: GetIpInterface,
+	update: func(update *IpInterfaceUpdate, interfaceLuid uint64, family AddressFamily) error {
+		_, err := update.Apply(interfaceLuid, family)
+		return err
+	},
+	set: func(ipifc *IpInterface) error {
+		return ipifc.Set()
+	},
+}
+
+func (a *ipInterfaceProfileApplier) snapshot(interfaceLuid uint64,
+	families []AddressFamily) (*IpInterfaceSnapshot, error) {
+
+	snapshot := &IpInterfaceSnapshot{InterfaceLuid: interfaceLuid, IpInterfaces: make([]*IpInterface, 0)}
+
+	for _, family := range families {
+
+		ipifc, err := a.get(interfaceLuid, family)
+
+		if isNotFoundError(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot.IpInterfaces = append(snapshot.IpInterfaces, ipifc)
+	}
+
+	return snapshot, nil
+}
+
+func (a *ipInterfaceProfileApplier) apply(profile *IpInterfaceProfile,
+	interfaceLuid uint64) (*IpInterfaceSnapshot, error) {
+
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+
+	families := make([]AddressFamily, 0, len(profile.Updates))
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+		if _, ok := profile.Updates[family]; ok {
+			families = append(families, family)
+		}
+	}
+
+	snapshot, err := a.snapshot(interfaceLuid, families)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for i, ipifc := range snapshot.IpInterfaces {
+
+		err := a.update(profile.Updates[ipifc.Family], interfaceLuid, ipifc.Family)
+
+		if err == nil {
+			continue
+		}
+
+		// Including the failed family, which may have been changed partially.
+		rollback := &IpInterfaceSnapshot{InterfaceLuid: interfaceLuid, IpInterfaces: snapshot.IpInterfaces[:i+1]}
+
+		if rollbackErr := a.restore(rollback); rollbackErr != nil {
+			return nil, fmt.Errorf("IpInterfaceProfile.Apply() - applying profile \"%s\" to %s failed: %v; "+
+				"rollback failed as well: %v", profile.Name, ipifc.Family.String(), err, rollbackErr)
+		}
+
+		return nil, fmt.Errorf("IpInterfaceProfile.Apply() - applying profile \"%s\" to %s failed: %v",
+			profile.Name, ipifc.Family.String(), err)
+	}
+
+	return snapshot, nil
+}
+
+func (a *ipInterfaceProfileApplier) restore(snapshot *IpInterfaceSnapshot) error {
+
+	failures := make([]string, 0)
+
+	for _, ipifc := range snapshot.IpInterfaces {
+
+		if ipifc.InterfaceLuid != snapshot.InterfaceLuid {
+			failures = append(failures, fmt.Sprintf("%s: IpInterface belongs to interface %d", ipifc.Family.String(),
+				ipifc.InterfaceLuid))
+			continue
+		}
+
+		if err := a.set(ipifc); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", ipifc.Family.String(), err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("IpInterfaceSnapshot.Restore() - %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
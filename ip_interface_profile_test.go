@@ -0,0 +1,208 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"golang.org/x/sys/windows"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// IpInterfaces of a single interface kept in memory, behind ipInterfaceProfileApplier.
+type ipInterfaceProfileTestState struct {
+	ipifcs    map[AddressFamily]*IpInterface
+	failApply AddressFamily
+	failSet   AddressFamily
+}
+
+func (s *ipInterfaceProfileTestState) applier() *ipInterfaceProfileApplier {
+	return &ipInterfaceProfileApplier{
+		get: func(interfaceLuid uint64, family AddressFamily) (*IpInterface, error) {
+			ipifc, ok := s.ipifcs[family]
+			if !ok {
+				return nil, os.NewSyscallError("iphlpapi.GetIpInterfaceEntry", windows.ERROR_NOT_FOUND)
+			}
+			copied := *ipifc
+			return &copied, nil
+		},
+		update: func(update *IpInterfaceUpdate, interfaceLuid uint64, family AddressFamily) error {
+			// Changes the row before failing, as a failure halfway through could.
+			update.ApplyTo(s.ipifcs[family])
+			if family == s.failApply {
+				return fmt.Errorf("apply failed")
+			}
+			return nil
+		},
+		set: func(ipifc *IpInterface) error {
+			if ipifc.Family == s.failSet {
+				return fmt.Errorf("set failed")
+			}
+			copied := *ipifc
+			s.ipifcs[ipifc.Family] = &copied
+			return nil
+		},
+	}
+}
+
+func newIpInterfaceProfileTestState(families ...AddressFamily) *ipInterfaceProfileTestState {
+
+	state := &ipInterfaceProfileTestState{ipifcs: make(map[AddressFamily]*IpInterface)}
+
+	for _, family := range families {
+		state.ipifcs[family] = &IpInterface{
+			Family:                  family,
+			InterfaceLuid:           1 << 24,
+			WeakHostSend:            true,
+			RouterDiscoveryBehavior: RouterDiscoveryEnabled,
+			DadTransmits:            1,
+			NlMtu:                   1420,
+		}
+	}
+
+	return state
+}
+
+func TestGetIpInterfaceProfile(t *testing.T) {
+
+	expectedNames := []string{IpInterfaceProfileFullTunnel, IpInterfaceProfileRouter, IpInterfaceProfileSplitTunnel}
+
+	if names := IpInterfaceProfileNames(); !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("IpInterfaceProfileNames() returned %v instead of %v.", names, expectedNames)
+	}
+
+	for _, name := range expectedNames {
+
+		profile, err := GetIpInterfaceProfile(name)
+
+		if err != nil {
+			t.Errorf("GetIpInterfaceProfile(\"%s\") returned an error: %v", name, err)
+			continue
+		}
+
+		if err = profile.Validate(); err != nil {
+			t.Errorf("Profile \"%s\" isn't valid: %v", name, err)
+		}
+	}
+
+	split, _ := GetIpInterfaceProfile(IpInterfaceProfileSplitTunnel)
+	full, _ := GetIpInterfaceProfile(IpInterfaceProfileFullTunnel)
+
+	if !*split.Updates[AF_INET6].DisableDefaultRoutes || *full.Updates[AF_INET6].DisableDefaultRoutes {
+		t.Error("Only the split-tunnel profile should disable default routes.")
+	}
+
+	// Profiles are independent instances.
+	*split.Updates[AF_INET].WeakHostSend = true
+
+	if again, _ := GetIpInterfaceProfile(IpInterfaceProfileSplitTunnel); *again.Updates[AF_INET].WeakHostSend {
+		t.Error("GetIpInterfaceProfile() returned a shared instance.")
+	}
+
+	if _, err := GetIpInterfaceProfile("lockdown"); err == nil || !strings.Contains(err.Error(), "full-tunnel") {
+		t.Errorf("GetIpInterfaceProfile() returned unexpected error for unknown profile: %v", err)
+	}
+}
+
+func TestIpInterfaceProfileApply(t *testing.T) {
+
+	state := newIpInterfaceProfileTestState(AF_INET, AF_INET6)
+	applier := state.applier()
+	original := state.ipifcs[AF_INET6].String()
+
+	profile, _ := GetIpInterfaceProfile(IpInterfaceProfileSplitTunnel)
+
+	snapshot, err := applier.apply(profile, 1<<24)
+
+	if err != nil {
+		t.Fatalf("ipInterfaceProfileApplier.apply() returned an error: %v", err)
+	}
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+		ipifc := state.ipifcs[family]
+		if ipifc.WeakHostSend || !ipifc.DisableDefaultRoutes || ipifc.DadTransmits != 0 ||
+			ipifc.RouterDiscoveryBehavior != RouterDiscoveryDisabled || ipifc.NlMtu != 1420 {
+			t.Errorf("Profile wasn't applied to %s: %s", family.String(), ipifc.String())
+		}
+	}
+
+	// The snapshot survives serialization and reverts the profile.
+	data, err := json.Marshal(snapshot)
+
+	if err != nil {
+		t.Fatalf("json.Marshal() returned an error: %v", err)
+	}
+
+	restored := &IpInterfaceSnapshot{}
+
+	if err = json.Unmarshal(data, restored); err != nil {
+		t.Fatalf("json.Unmarshal() returned an error: %v", err)
+	}
+
+	if err = applier.restore(restored); err != nil {
+		t.Fatalf("ipInterfaceProfileApplier.restore() returned an error: %v", err)
+	}
+
+	if state.ipifcs[AF_INET6].String() != original {
+		t.Errorf("Snapshot restored %s instead of %s.", state.ipifcs[AF_INET6].String(), original)
+	}
+}
+
+func TestIpInterfaceProfileApplyRollback(t *testing.T) {
+
+	state := newIpInterfaceProfileTestState(AF_INET, AF_INET6)
+	state.failApply = AF_INET6
+	applier := state.applier()
+
+	profile, _ := GetIpInterfaceProfile(IpInterfaceProfileRouter)
+
+	if _, err := applier.apply(profile, 1<<24); err == nil || !strings.Contains(err.Error(), "AF_INET6 failed") {
+		t.Fatalf("ipInterfaceProfileApplier.apply() returned unexpected error: %v", err)
+	}
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+		if state.ipifcs[family].ForwardingEnabled {
+			t.Errorf("%s wasn't rolled back.", family.String())
+		}
+	}
+
+	state = newIpInterfaceProfileTestState(AF_INET, AF_INET6)
+	state.failApply = AF_INET6
+	state.failSet = AF_INET
+
+	_, err := state.applier().apply(profile, 1<<24)
+
+	if err == nil || !strings.Contains(err.Error(), "rollback failed as well") {
+		t.Errorf("ipInterfaceProfileApplier.apply() returned unexpected error: %v", err)
+	}
+}
+
+func TestIpInterfaceProfileApplyMissingFamily(t *testing.T) {
+
+	state := newIpInterfaceProfileTestState(AF_INET)
+
+	profile, _ := GetIpInterfaceProfile(IpInterfaceProfileFullTunnel)
+
+	snapshot, err := state.applier().apply(profile, 1<<24)
+
+	if err != nil {
+		t.Fatalf("ipInterfaceProfileApplier.apply() returned an error: %v", err)
+	}
+
+	if len(snapshot.IpInterfaces) != 1 || snapshot.IpInterfaces[0].Family != AF_INET {
+		t.Errorf("Snapshot should hold AF_INET IpInterface only: %v", snapshot.IpInterfaces)
+	}
+
+	if state.ipifcs[AF_INET].WeakHostSend {
+		t.Error("Profile wasn't applied to AF_INET.")
+	}
+}
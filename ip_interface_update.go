@@ -0,0 +1,298 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Minimum MTUs an interface can be set to: 1280 for IPv6 (RFC 8200, section 5), and 576 for IPv4, the datagram size
+// every IPv4 host has to accept (RFC 791), which is also the lowest value Windows accepts.
+const (
+	MinIPv6Mtu = 1280
+	MinIPv4Mtu = 576
+)
+
+// Bounds of router advertisement intervals (in ms) set by RFC 4861, section 6.2.1.
+const (
+	MinRouterAdvertisementIntervalLowerBound = 3000
+	MaxRouterAdvertisementIntervalLowerBound = 4000
+	MaxRouterAdvertisementIntervalUpperBound = 1800000
+)
+
+// Checks IPv6 router advertisement intervals (in ms) against RFC 4861, section 6.2.1: MaxRtrAdvInterval has to be
+// between 4 and 1800 seconds, and MinRtrAdvInterval between 3 seconds and 0.75 * MaxRtrAdvInterval.
+func ValidateRouterAdvertisementIntervals(minInterval uint32, maxInterval uint32) error {
+
+	if err := checkRouterAdvertisementIntervals(minInterval, maxInterval); err != nil {
+		return fmt.Errorf("ValidateRouterAdvertisementIntervals() - %v", err)
+	}
+
+	return nil
+}
+
+func checkRouterAdvertisementIntervals(minInterval uint32, maxInterval uint32) error {
+
+	if maxInterval < MaxRouterAdvertisementIntervalLowerBound ||
+		maxInterval > MaxRouterAdvertisementIntervalUpperBound {
+		return fmt.Errorf("MaxRouterAdvertisementInterval %d ms is out of range [%d, %d]", maxInterval,
+			MaxRouterAdvertisementIntervalLowerBound, MaxRouterAdvertisementIntervalUpperBound)
+	}
+
+	if minInterval < MinRouterAdvertisementIntervalLowerBound || uint64(minInterval)*4 > uint64(maxInterval)*3 {
+		return fmt.Errorf("MinRouterAdvertisementInterval %d ms is out of range [%d, 0.75 * %d]", minInterval,
+			MinRouterAdvertisementIntervalLowerBound, maxInterval)
+	}
+
+	return nil
+}
+
+// Partial update of IpInterface's "changeable" fields (see IpInterface.Set()). Fields are pointers; nil fields are left
+// as they are, so that an update of NlMtu alone doesn't overwrite the other fields with possibly outdated values.
+//
+// Field names are the same as IpInterface's.
+type IpInterfaceUpdate struct {
+	MaxReassemblySize              *uint32
+	InterfaceIdentifier            *uint64
+	MinRouterAdvertisementInterval *uint32
+	MaxRouterAdvertisementInterval *uint32
+
+	AdvertisingEnabled                   *bool
+	ForwardingEnabled                    *bool
+	WeakHostSend                         *bool
+	WeakHostReceive                      *bool
+	UseAutomaticMetric                   *bool
+	UseNeighborUnreachabilityDetection   *bool
+	ManagedAddressConfigurationSupported *bool
+	OtherStatefulConfigurationSupported  *bool
+	AdvertiseDefaultRoute                *bool
+
+	RouterDiscoveryBehavior  *NlRouterDiscoveryBehavior
+	DadTransmits             *uint32
+	BaseReachableTime        *uint32
+	RetransmitTime           *uint32
+	PathMtuDiscoveryTimeout  *uint32
+	LinkLocalAddressBehavior *NlLinkLocalAddressBehavior
+	LinkLocalAddressTimeout  *uint32
+	ZoneIndices              *[ScopeLevelCount]uint32
+	SitePrefixLength         *uint32
+	Metric                   *uint32
+	NlMtu                    *uint32
+
+	DisableDefaultRoutes *bool
+}
+
+// Change of a single field, as returned by IpInterfaceUpdate.Diff().
+type FieldChange struct {
+	Name string
+	Old  interface{}
+	New  interface{}
+}
+
+func (fc *FieldChange) String() string {
+	return fmt.Sprintf("%s: %v -> %v", fc.Name, fc.Old, fc.New)
+}
+
+// Checks values of the fields which are set against the rules of address family 'family' (AF_INET or AF_INET6):
+//   - NlMtu has to be at least MinIPv6Mtu for IPv6 and MinIPv4Mtu for IPv4;
+//   - SitePrefixLength can't exceed 128 for IPv6 and 32 for IPv4;
+//   - RouterDiscoveryBehavior and LinkLocalAddressBehavior have to be known values, and RouterDiscoveryDhcp is IPv4
+//     only;
+//   - ManagedAddressConfigurationSupported and OtherStatefulConfigurationSupported (flags of IPv6 router
+//     advertisements) can't be enabled for IPv4;
+//   - Metric can't be set together with enabling UseAutomaticMetric, since it would be ignored;
+//   - for IPv6, router advertisement intervals have to satisfy RFC 4861 (see ValidateRouterAdvertisementIntervals()).
+//     If only one of them is set, its own bounds are checked here, and the constraint between the two is checked by
+//     Apply() against the interface's current value of the other one.
+//
+// All the violations are listed in the returned error.
+func (u *IpInterfaceUpdate) Validate(family AddressFamily) error {
+
+	if family != AF_INET && family != AF_INET6 {
+		return fmt.Errorf("IpInterfaceUpdate.Validate() - family has to be either AF_INET or AF_INET6")
+	}
+
+	violations := make([]string, 0)
+
+	violate := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	minMtu, maxPrefixLength := uint32(MinIPv6Mtu), uint32(128)
+
+	if family == AF_INET {
+		minMtu, maxPrefixLength = MinIPv4Mtu, 32
+	}
+
+	if u.NlMtu != nil && *u.NlMtu < minMtu {
+		violate("NlMtu %d is below %s minimum of %d", *u.NlMtu, family.String(), minMtu)
+	}
+
+	if u.SitePrefixLength != nil && *u.SitePrefixLength > maxPrefixLength {
+		violate("SitePrefixLength %d exceeds %s maximum of %d", *u.SitePrefixLength, family.String(), maxPrefixLength)
+	}
+
+	if u.RouterDiscoveryBehavior != nil {
+		switch *u.RouterDiscoveryBehavior {
+		case RouterDiscoveryDisabled, RouterDiscoveryEnabled, RouterDiscoveryUnchanged:
+		case RouterDiscoveryDhcp:
+			if family != AF_INET {
+				violate("RouterDiscoveryBehavior %s applies to AF_INET only", u.RouterDiscoveryBehavior.String())
+			}
+		default:
+			violate("RouterDiscoveryBehavior %s is invalid", u.RouterDiscoveryBehavior.String())
+		}
+	}
+
+	if u.LinkLocalAddressBehavior != nil {
+		switch *u.LinkLocalAddressBehavior {
+		case LinkLocalAlwaysOff, LinkLocalDelayed, LinkLocalAlwaysOn, LinkLocalUnchanged:
+		default:
+			violate("LinkLocalAddressBehavior %s is invalid", u.LinkLocalAddressBehavior.String())
+		}
+	}
+
+	if family == AF_INET {
+
+		if u.ManagedAddressConfigurationSupported != nil && *u.ManagedAddressConfigurationSupported {
+			violate("ManagedAddressConfigurationSupported applies to AF_INET6 only")
+		}
+
+		if u.OtherStatefulConfigurationSupported != nil && *u.OtherStatefulConfigurationSupported {
+			violate("OtherStatefulConfigurationSupported applies to AF_INET6 only")
+		}
+	}
+
+	if u.Metric != nil && u.UseAutomaticMetric != nil && *u.UseAutomaticMetric {
+		violate("Metric is ignored if UseAutomaticMetric is enabled")
+	}
+
+	if family == AF_INET6 && (u.MinRouterAdvertisementInterval != nil || u.MaxRouterAdvertisementInterval != nil) {
+
+		minInterval, maxInterval := uint32(MinRouterAdvertisementIntervalLowerBound),
+			uint32(MaxRouterAdvertisementIntervalUpperBound)
+
+		if u.MinRouterAdvertisementInterval != nil {
+			minInterval = *u.MinRouterAdvertisementInterval
+		}
+
+		if u.MaxRouterAdvertisementInterval != nil {
+			maxInterval = *u.MaxRouterAdvertisementInterval
+		}
+
+		if err := checkRouterAdvertisementIntervals(minInterval, maxInterval); err != nil {
+			violations = append(violations, err.Error())
+		}
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("IpInterfaceUpdate.Validate() - %s", strings.Join(violations, "; "))
+	}
+
+	return nil
+}
+
+// Returns changes the update would make to 'ipifc', in the order of IpInterfaceUpdate fields. Fields which are set
+// to their current values aren't included.
+func (u *IpInterfaceUpdate) Diff(ipifc *IpInterface) []*FieldChange {
+
+	changes := make([]*FieldChange, 0)
+
+	u.forEachSetField(ipifc, func(name string, update, current reflect.Value) {
+		if !reflect.DeepEqual(update.Interface(), current.Interface()) {
+			changes = append(changes, &FieldChange{Name: name, Old: current.Interface(), New: update.Interface()})
+		}
+	})
+
+	return changes
+}
+
+// Sets fields of 'ipifc' which are set in the update. It doesn't save 'ipifc'; see Apply() method for that.
+func (u *IpInterfaceUpdate) ApplyTo(ipifc *IpInterface) {
+	u.forEachSetField(ipifc, func(name string, update, current reflect.Value) {
+		current.Set(update)
+	})
+}
+
+// Calls 'f' for each field which is set in the update, with the field's name, its (dereferenced) value in the update
+// and the (settable) field of 'ipifc' with the same name.
+func (u *IpInterfaceUpdate) forEachSetField(ipifc *IpInterface, f func(name string, update, current reflect.Value)) {
+
+	uv := reflect.ValueOf(u).Elem()
+	iv := reflect.ValueOf(ipifc).Elem()
+
+	for i := 0; i < uv.NumField(); i++ {
+
+		field := uv.Field(i)
+
+		if field.IsNil() {
+			continue
+		}
+
+		name := uv.Type().Field(i).Name
+
+		f(name, field.Elem(), iv.FieldByName(name))
+	}
+}
+
+// Returns changes Apply() would make to the interface's current IpInterface of family 'family'.
+func (u *IpInterfaceUpdate) Preview(interfaceLuid uint64, family AddressFamily) ([]*FieldChange, error) {
+
+	ipifc, err := GetIpInterface(interfaceLuid, family)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return u.Diff(ipifc), nil
+}
+
+// Validates the update, applies it onto the interface's current IpInterface of family 'family' and saves the result.
+// Only the fields which are set are written, so concurrent changes of the other fields made before the current row
+// is read are preserved. Returns the changes made (see Diff() method).
+func (u *IpInterfaceUpdate) Apply(interfaceLuid uint64, family AddressFamily) ([]*FieldChange, error) {
+
+	if err := u.Validate(family); err != nil {
+		return nil, err
+	}
+
+	row, err := getWtMibIpinterfaceRow(interfaceLuid, family)
+
+	if err != nil {
+		return nil, err
+	}
+
+	ipifc := row.toIpInterface()
+
+	changes := u.Diff(ipifc)
+
+	if len(changes) < 1 {
+		return changes, nil
+	}
+
+	u.ApplyTo(ipifc)
+
+	if family == AF_INET6 && (u.MinRouterAdvertisementInterval != nil || u.MaxRouterAdvertisementInterval != nil) {
+		err := ValidateRouterAdvertisementIntervals(ipifc.MinRouterAdvertisementInterval,
+			ipifc.MaxRouterAdvertisementInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ipifc.copyChangeableFieldsTo(row)
+
+	if err := row.set(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
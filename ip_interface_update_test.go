@@ -0,0 +1,127 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIpInterfaceUpdateValidate(t *testing.T) {
+
+	mtu := uint32(1000)
+	prefixLength := uint32(64)
+	dhcp := RouterDiscoveryDhcp
+	invalidLinkLocal := NlLinkLocalAddressBehavior(7)
+	enabled := true
+	metric := uint32(5)
+
+	update := &IpInterfaceUpdate{
+		NlMtu:                    &mtu,
+		SitePrefixLength:         &prefixLength,
+		RouterDiscoveryBehavior:  &dhcp,
+		LinkLocalAddressBehavior: &invalidLinkLocal,
+		UseAutomaticMetric:       &enabled,
+		Metric:                   &metric,
+	}
+
+	err := update.Validate(AF_INET6)
+
+	if err == nil {
+		t.Fatal("IpInterfaceUpdate.Validate() should have failed.")
+	}
+
+	for _, expected := range []string{"NlMtu 1000", "RouterDiscoveryBehavior", "LinkLocalAddressBehavior",
+		"UseAutomaticMetric"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("IpInterfaceUpdate.Validate() error doesn't mention %s: %v", expected, err)
+		}
+	}
+
+	if strings.Contains(err.Error(), "SitePrefixLength") {
+		t.Errorf("SitePrefixLength 64 is valid for IPv6: %v", err)
+	}
+
+	managed := true
+
+	ipv4Update := &IpInterfaceUpdate{
+		NlMtu:                                &mtu,
+		SitePrefixLength:                     &prefixLength,
+		RouterDiscoveryBehavior:              &dhcp,
+		ManagedAddressConfigurationSupported: &managed,
+	}
+
+	err = ipv4Update.Validate(AF_INET)
+
+	if err == nil || !strings.Contains(err.Error(), "SitePrefixLength 64") ||
+		!strings.Contains(err.Error(), "ManagedAddressConfigurationSupported") ||
+		strings.Contains(err.Error(), "NlMtu") || strings.Contains(err.Error(), "RouterDiscoveryBehavior") {
+		t.Errorf("IpInterfaceUpdate.Validate() returned unexpected error for AF_INET: %v", err)
+	}
+
+	if err = (&IpInterfaceUpdate{}).Validate(AF_UNSPEC); err == nil {
+		t.Error("IpInterfaceUpdate.Validate() should have failed for AF_UNSPEC.")
+	}
+
+	if err = (&IpInterfaceUpdate{}).Validate(AF_INET6); err != nil {
+		t.Errorf("Empty IpInterfaceUpdate should be valid: %v", err)
+	}
+}
+
+func TestIpInterfaceUpdateDiff(t *testing.T) {
+
+	ipifc := &IpInterface{
+		ForwardingEnabled:       false,
+		UseAutomaticMetric:      true,
+		RouterDiscoveryBehavior: RouterDiscoveryEnabled,
+		NlMtu:                   1500,
+	}
+
+	mtu := uint32(1420)
+	forwarding := true
+	automaticMetric := true
+	zoneIndices := [ScopeLevelCount]uint32{1: 12}
+
+	update := &IpInterfaceUpdate{
+		ForwardingEnabled:  &forwarding,
+		UseAutomaticMetric: &automaticMetric,
+		ZoneIndices:        &zoneIndices,
+		NlMtu:              &mtu,
+	}
+
+	changes := update.Diff(ipifc)
+
+	expected := []string{
+		"ForwardingEnabled: false -> true",
+		"ZoneIndices: [0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0] -> [0 12 0 0 0 0 0 0 0 0 0 0 0 0 0 0]",
+		"NlMtu: 1500 -> 1420",
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("IpInterfaceUpdate.Diff() returned %d changes instead of %d: %v", len(changes), len(expected),
+			changes)
+	}
+
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("Change %d is \"%s\" instead of \"%s\".", i, change.String(), expected[i])
+		}
+	}
+
+	update.ApplyTo(ipifc)
+
+	if !ipifc.ForwardingEnabled || ipifc.NlMtu != 1420 || ipifc.ZoneIndices[1] != 12 ||
+		ipifc.RouterDiscoveryBehavior != RouterDiscoveryEnabled {
+		t.Errorf("IpInterfaceUpdate.ApplyTo() produced unexpected IpInterface: %s", ipifc.String())
+	}
+
+	if changes = update.Diff(ipifc); len(changes) != 0 {
+		t.Errorf("IpInterfaceUpdate.Diff() returned changes after the update was applied: %v", changes)
+	}
+}
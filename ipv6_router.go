@@ -0,0 +1,164 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+)
+
+// Default MaxRtrAdvInterval (in ms) set by RFC 4861, section 6.2.1.
+const DefaultMaxRouterAdvertisementInterval = 600000
+
+// Settings of an interface acting as IPv6 router; see Interface.EnableIpv6Router().
+type Ipv6RouterConfig struct {
+	// Whether the router advertisements announce the interface as a default router.
+	AdvertiseDefaultRoute bool
+
+	// Router advertisement intervals (in ms). Zero MaxRouterAdvertisementInterval means
+	// DefaultMaxRouterAdvertisementInterval; zero MinRouterAdvertisementInterval means RFC 4861 default, which is
+	// 0.33 * MaxRouterAdvertisementInterval, or 0.75 * MaxRouterAdvertisementInterval if the latter is below 9 seconds,
+	// but at least MinRouterAdvertisementIntervalLowerBound.
+	MinRouterAdvertisementInterval uint32
+	MaxRouterAdvertisementInterval uint32
+
+	// Prefixes advertised in the router advertisements. They are published by setting Publish flag of the
+	// interface's on-link routes to the prefixes, which are added if they don't exist.
+	Prefixes []*net.IPNet
+}
+
+// Returns IpInterface update turning the interface into a router as configured.
+func (config *Ipv6RouterConfig) update() (*IpInterfaceUpdate, error) {
+
+	maxInterval := config.MaxRouterAdvertisementInterval
+
+	if maxInterval == 0 {
+		maxInterval = DefaultMaxRouterAdvertisementInterval
+	}
+
+	minInterval := config.MinRouterAdvertisementInterval
+
+	if minInterval == 0 {
+		if maxInterval >= 9000 {
+			minInterval = uint32(uint64(maxInterval) * 33 / 100)
+		} else {
+			minInterval = uint32(uint64(maxInterval) * 3 / 4)
+		}
+
+		// 0.33 * MaxRouterAdvertisementInterval is below the lower bound for intervals just above 9 seconds.
+		if minInterval < MinRouterAdvertisementIntervalLowerBound {
+			minInterval = MinRouterAdvertisementIntervalLowerBound
+		}
+	}
+
+	if err := ValidateRouterAdvertisementIntervals(minInterval, maxInterval); err != nil {
+		return nil, err
+	}
+
+	for _, prefix := range config.Prefixes {
+		if prefix == nil || prefix.IP.To4() != nil || len(prefix.IP) != net.IPv6len {
+			return nil, fmt.Errorf("Ipv6RouterConfig - prefix %v isn't an IPv6 prefix", prefix)
+		}
+	}
+
+	return &IpInterfaceUpdate{
+		AdvertisingEnabled:             newBool(true),
+		ForwardingEnabled:              newBool(true),
+		AdvertiseDefaultRoute:          newBool(config.AdvertiseDefaultRoute),
+		MinRouterAdvertisementInterval: &minInterval,
+		MaxRouterAdvertisementInterval: &maxInterval,
+	}, nil
+}
+
+// Makes the interface act as IPv6 router: enables forwarding and sending of router advertisements, with the intervals
+// and prefixes from 'config'.
+func (ifc *Interface) EnableIpv6Router(config *Ipv6RouterConfig) error {
+
+	update, err := config.update()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = update.Apply(ifc.Luid, AF_INET6); err != nil {
+		return err
+	}
+
+	for _, prefix := range config.Prefixes {
+		if err = ifc.PublishRoute(prefix); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stops the interface from acting as IPv6 router: disables forwarding and router advertisements, and unpublishes all
+// the interface's IPv6 routes.
+func (ifc *Interface) DisableIpv6Router() error {
+
+	update := &IpInterfaceUpdate{
+		AdvertisingEnabled:    newBool(false),
+		ForwardingEnabled:     newBool(false),
+		AdvertiseDefaultRoute: newBool(false),
+	}
+
+	if _, err := update.Apply(ifc.Luid, AF_INET6); err != nil {
+		return err
+	}
+
+	routes, err := ifc.GetRoutes(AF_INET6)
+
+	if err != nil {
+		return err
+	}
+
+	for _, route := range routes {
+
+		if !route.Publish {
+			continue
+		}
+
+		route.Publish = false
+
+		if err = route.Set(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Sets Publish flag of the interface's on-link route to 'prefix', so that the prefix is included in router
+// advertisements sent by the interface. The route is added if it doesn't exist.
+func (ifc *Interface) PublishRoute(prefix *net.IPNet) error {
+
+	route, err := ifc.GetRoute(prefix, &net.IPv6zero)
+
+	if isNotFoundError(err) {
+
+		if err = ifc.AddRoute(&RouteData{Destination: *prefix, NextHop: net.IPv6zero}); err != nil {
+			return err
+		}
+
+		route, err = ifc.GetRoute(prefix, &net.IPv6zero)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if route.Publish {
+		return nil
+	}
+
+	route.Publish = true
+
+	return route.Set()
+}
@@ -0,0 +1,114 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestValidateRouterAdvertisementIntervals(t *testing.T) {
+
+	tests := []struct {
+		minInterval uint32
+		maxInterval uint32
+		valid       bool
+	}{
+		{198000, 600000, true},
+		{3000, 4000, true},
+		{450000, 600000, true},
+		{1350000, 1800000, true},
+		{450001, 600000, false},
+		{2999, 600000, false},
+		{3000, 3999, false},
+		{3000, 1800001, false},
+	}
+
+	for _, test := range tests {
+		err := ValidateRouterAdvertisementIntervals(test.minInterval, test.maxInterval)
+		if (err == nil) != test.valid {
+			t.Errorf("ValidateRouterAdvertisementIntervals(%d, %d) returned %v.", test.minInterval, test.maxInterval,
+				err)
+		}
+	}
+
+	tooShort := uint32(2000)
+	update := &IpInterfaceUpdate{MaxRouterAdvertisementInterval: &tooShort}
+
+	err := update.Validate(AF_INET6)
+
+	if err == nil || !strings.Contains(err.Error(), "MaxRouterAdvertisementInterval") {
+		t.Errorf("IpInterfaceUpdate.Validate() returned unexpected error: %v", err)
+	}
+
+	// RFC 4861 bounds don't apply to IPv4.
+	if err = update.Validate(AF_INET); err != nil {
+		t.Errorf("IpInterfaceUpdate.Validate() returned an error for AF_INET: %v", err)
+	}
+}
+
+func TestIpv6RouterConfig(t *testing.T) {
+
+	_, prefix, _ := net.ParseCIDR("fd00:1234::/64")
+
+	tests := []struct {
+		config      Ipv6RouterConfig
+		minInterval uint32
+		maxInterval uint32
+	}{
+		{Ipv6RouterConfig{}, 198000, 600000},
+		{Ipv6RouterConfig{MaxRouterAdvertisementInterval: 8000}, 6000, 8000},
+		{Ipv6RouterConfig{MaxRouterAdvertisementInterval: 9000}, 3000, 9000},
+		{Ipv6RouterConfig{MaxRouterAdvertisementInterval: 9090}, 3000, 9090},
+		{Ipv6RouterConfig{MaxRouterAdvertisementInterval: 9100}, 3003, 9100},
+		{Ipv6RouterConfig{MaxRouterAdvertisementInterval: 30000, MinRouterAdvertisementInterval: 20000}, 20000, 30000},
+		{Ipv6RouterConfig{AdvertiseDefaultRoute: true, Prefixes: []*net.IPNet{prefix}}, 198000, 600000},
+	}
+
+	for _, test := range tests {
+
+		update, err := test.config.update()
+
+		if err != nil {
+			t.Errorf("Ipv6RouterConfig.update() returned an error for %+v: %v", test.config, err)
+			continue
+		}
+
+		if *update.MinRouterAdvertisementInterval != test.minInterval ||
+			*update.MaxRouterAdvertisementInterval != test.maxInterval {
+			t.Errorf("Ipv6RouterConfig.update() returned intervals [%d, %d] instead of [%d, %d].",
+				*update.MinRouterAdvertisementInterval, *update.MaxRouterAdvertisementInterval, test.minInterval,
+				test.maxInterval)
+		}
+
+		if !*update.AdvertisingEnabled || !*update.ForwardingEnabled ||
+			*update.AdvertiseDefaultRoute != test.config.AdvertiseDefaultRoute {
+			t.Errorf("Ipv6RouterConfig.update() returned unexpected flags: %+v", update)
+		}
+
+		if err = update.Validate(AF_INET6); err != nil {
+			t.Errorf("Ipv6RouterConfig.update() returned invalid update: %v", err)
+		}
+	}
+
+	_, ipv4Prefix, _ := net.ParseCIDR("10.0.0.0/24")
+
+	invalid := []Ipv6RouterConfig{
+		{MaxRouterAdvertisementInterval: 30000, MinRouterAdvertisementInterval: 25000},
+		{MaxRouterAdvertisementInterval: 1000},
+		{Prefixes: []*net.IPNet{ipv4Prefix}},
+	}
+
+	for _, config := range invalid {
+		if _, err := config.update(); err == nil {
+			t.Errorf("Ipv6RouterConfig.update() should have failed for %+v.", config)
+		}
+	}
+}
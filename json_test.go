@@ -0,0 +1,403 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Marshals 'value', unmarshals the result into 'decoded' and checks that both the decoded value and its encoding are
+// equal to the original ones. Returns the encoding.
+func testJSONRoundTrip(t *testing.T, value interface{}, decoded interface{}) string {
+
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		t.Fatalf("json.Marshal(%T) returned an error: %v", value, err)
+	}
+
+	if err = json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("json.Unmarshal(%T) returned an error: %v\n%s", decoded, err, data)
+	}
+
+	if !reflect.DeepEqual(reflect.ValueOf(decoded).Elem().Interface(), reflect.ValueOf(value).Elem().Interface()) {
+		t.Errorf("Decoded %T isn't equal to the original one.\nOriginal: %+v\nDecoded: %+v\nJSON: %s", decoded, value,
+			decoded, data)
+	}
+
+	again, err := json.Marshal(decoded)
+
+	if err != nil {
+		t.Fatalf("json.Marshal(%T) of the decoded value returned an error: %v", decoded, err)
+	}
+
+	if string(again) != string(data) {
+		t.Errorf("Re-encoded %T differs from the original encoding.\nOriginal: %s\nRe-encoded: %s", decoded, data,
+			again)
+	}
+
+	return string(data)
+}
+
+func checkJSONContains(t *testing.T, data string, expected ...string) {
+	for _, e := range expected {
+		if !strings.Contains(data, e) {
+			t.Errorf("JSON doesn't contain %s:\n%s", e, data)
+		}
+	}
+}
+
+func TestJSONInterface(t *testing.T) {
+
+	linkLocal := SockaddrInet{Family: AF_INET6, Address: net.ParseIP("fe80::1234"), IPv6ScopeId: 12}
+	ipv4 := SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 10)}
+
+	ifc := &Interface{
+		Luid:         1689399616077824,
+		Index:        12,
+		AdapterName:  "{6B29FC40-CA47-1067-B31D-00DD010662DA}",
+		FriendlyName: "Ethernet",
+		UnicastAddresses: []*UnicastAddress{
+			{
+				IpAdapterAddressCommonTypeEx: IpAdapterAddressCommonTypeEx{
+					IpAdapterAddressCommonType: IpAdapterAddressCommonType{
+						InterfaceLuid: 1689399616077824, InterfaceIndex: 12, Length: 48, Address: linkLocal},
+				},
+				PrefixOrigin:       IpPrefixOrigin(IpPrefixOriginWellKnown),
+				SuffixOrigin:       IpSuffixOrigin(IpSuffixOriginLinkLayerAddress),
+				DadState:           IpDadState(IpDadStatePreferred),
+				ValidLifetime:      0xffffffff,
+				PreferredLifetime:  0xffffffff,
+				OnLinkPrefixLength: 64,
+			},
+			{
+				IpAdapterAddressCommonTypeEx: IpAdapterAddressCommonTypeEx{
+					IpAdapterAddressCommonType: IpAdapterAddressCommonType{
+						InterfaceLuid: 1689399616077824, InterfaceIndex: 12, Length: 48, Address: ipv4},
+				},
+				PrefixOrigin:       IpPrefixOrigin(IpPrefixOriginDhcp),
+				SuffixOrigin:       IpSuffixOrigin(IpSuffixOriginDhcp),
+				DadState:           IpDadState(IpDadStatePreferred),
+				OnLinkPrefixLength: 24,
+			},
+		},
+		UnicastIPNets: []*net.IPNet{
+			{IP: net.ParseIP("fe80::1234"), Mask: net.CIDRMask(64, 128)},
+			{IP: net.IPv4(192, 0, 2, 10), Mask: net.CIDRMask(24, 32)},
+		},
+		DnsServerAddresses: []*IpAdapterAddressCommonType{
+			{Address: SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 1), Port: 53}},
+		},
+		DnsSuffix:       "example.com",
+		Description:     "Intel(R) Ethernet Connection",
+		PhysicalAddress: net.HardwareAddr{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03},
+		Flags:           0x1c5,
+		Mtu:             1500,
+		IfType:          IF_TYPE_ETHERNET_CSMACD,
+		OperStatus:      IfOperStatusUp,
+		Ipv6IfIndex:     12,
+		ZoneIndices:     [16]uint32{12, 12, 12, 12, 12, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		Prefixes: []*IpAdapterPrefix{
+			{
+				IpAdapterAddressCommonTypeEx: IpAdapterAddressCommonTypeEx{
+					IpAdapterAddressCommonType: IpAdapterAddressCommonType{
+						InterfaceLuid: 1689399616077824, InterfaceIndex: 12, Length: 24,
+						Address: SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 0)}},
+				},
+				PrefixLength: 24,
+			},
+		},
+		TransmitLinkSpeed: 1000000000,
+		ReceiveLinkSpeed:  1000000000,
+		GatewayAddresses: []*IpAdapterAddressCommonType{
+			{Address: SockaddrInet{Family: AF_INET6, Address: net.ParseIP("fe80::1"), IPv6ScopeId: 12}},
+		},
+		Ipv4Metric:       25,
+		Ipv6Metric:       25,
+		Dhcpv4Server:     &SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 1), Port: 67},
+		NetworkGuid:      GUID{Data1: 0xaaaaaaaa, Data2: 0xbbbb, Data3: 0xcccc, Data4: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		ConnectionType:   NET_IF_CONNECTION_DEDICATED,
+		TunnelType:       TUNNEL_TYPE_NONE,
+		Dhcpv6ClientDuid: []uint8{0x00, 0x01, 0x00, 0x01, 0x24, 0x5c},
+		Dhcpv6Iaid:       100666717,
+		DnsSuffixes:      []string{"example.com", "corp.example.com"},
+	}
+
+	data := testJSONRoundTrip(t, ifc, &Interface{})
+
+	checkJSONContains(t, data, `"luid":1689399616077824`, `"address":{"address":"fe80::1234%12"}`,
+		`"address":{"address":"192.0.2.1","port":53}`, `"unicastIPNets":["fe80::1234/64","192.0.2.10/24"]`,
+		`"prefix":"192.0.2.0/24"`, `"physicalAddress":"00:15:5d:01:02:03"`, `"ifType":"IF_TYPE_ETHERNET_CSMACD"`,
+		`"operStatus":"IfOperStatusUp"`, `"dadState":"IpDadStatePreferred"`,
+		`"networkGuid":"{AAAAAAAA-BBBB-CCCC-0102-030405060708}"`, `"dhcpv6ClientDuid":"00010001245c"`,
+		`"dhcpv6Server":null`, `"anycastAddresses":null`)
+}
+
+func TestJSONIfRow(t *testing.T) {
+
+	ifrow := &IfRow{
+		InterfaceLuid:            1689399616077824,
+		InterfaceIndex:           12,
+		InterfaceGuid:            GUID{Data1: 0x6b29fc40, Data2: 0xca47, Data3: 0x1067},
+		Alias:                    "Ethernet",
+		Description:              "Intel(R) Ethernet Connection",
+		PhysicalAddress:          string([]byte{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03}),
+		PermanentPhysicalAddress: string([]byte{0x00, 0x15, 0x5d, 0x01, 0x02, 0x03}),
+		Mtu:                      1500,
+		Type:                     IF_TYPE_ETHERNET_CSMACD,
+		TunnelType:               TUNNEL_TYPE_NONE,
+		MediaType:                NdisMedium802_3,
+		PhysicalMediumType:       NdisPhysicalMedium802_3,
+		AccessType:               NET_IF_ACCESS_BROADCAST,
+		DirectionType:            NET_IF_DIRECTION_SENDRECEIVE,
+		InterfaceAndOperStatusFlags: InterfaceAndOperStatusFlags{
+			HardwareInterface: true,
+			ConnectorPresent:  true,
+		},
+		OperStatus:        IfOperStatusUp,
+		AdminStatus:       NET_IF_ADMIN_STATUS_UP,
+		MediaConnectState: MediaConnectStateConnected,
+		ConnectionType:    NET_IF_CONNECTION_DEDICATED,
+		TransmitLinkSpeed: 1000000000,
+		ReceiveLinkSpeed:  1000000000,
+		InOctets:          123456789,
+		OutOctets:         987654321,
+	}
+
+	data := testJSONRoundTrip(t, ifrow, &IfRow{})
+
+	checkJSONContains(t, data, `"interfaceGuid":"{6B29FC40-CA47-1067-0000-000000000000}"`,
+		`"physicalAddress":"00:15:5d:01:02:03"`, `"permanentPhysicalAddress":"00:15:5d:01:02:03"`,
+		`"mediaType":"NdisMedium802_3"`, `"adminStatus":"NET_IF_ADMIN_STATUS_UP"`, `"hardwareInterface":true`,
+		`"inOctets":123456789`)
+
+	// Physical addresses which aren't 6 bytes long survive the round trip as well.
+	testJSONRoundTrip(t, &IfRow{PhysicalAddress: string([]byte{0, 0, 0, 0, 0, 0, 0, 0xe0})}, &IfRow{})
+	testJSONRoundTrip(t, &IfRow{}, &IfRow{})
+}
+
+func TestJSONIpInterface(t *testing.T) {
+
+	ipifc := &IpInterface{
+		Family:                             AF_INET6,
+		InterfaceLuid:                      1689399616077824,
+		InterfaceIndex:                     12,
+		MaxReassemblySize:                  65535,
+		InterfaceIdentifier:                0x02155dfffe010203,
+		MinRouterAdvertisementInterval:     200,
+		MaxRouterAdvertisementInterval:     600,
+		UseAutomaticMetric:                 true,
+		UseNeighborUnreachabilityDetection: true,
+		RouterDiscoveryBehavior:            RouterDiscoveryDhcp,
+		DadTransmits:                       1,
+		BaseReachableTime:                  30000,
+		RetransmitTime:                     1000,
+		PathMtuDiscoveryTimeout:            600000,
+		LinkLocalAddressBehavior:           LinkLocalAlwaysOn,
+		ZoneIndices:                        [ScopeLevelCount]uint32{12, 12, 12},
+		SitePrefixLength:                   64,
+		Metric:                             25,
+		NlMtu:                              1500,
+		Connected:                          true,
+		SupportsNeighborDiscovery:          true,
+		SupportsRouterDiscovery:            true,
+		ReachableTime:                      25500,
+		TransmitOffload:                    NlInterfaceOffloadRodFlags{NlChecksumSupported: true},
+		ReceiveOffload:                     NlInterfaceOffloadRodFlags{TlStreamChecksumSupported: true},
+	}
+
+	data := testJSONRoundTrip(t, ipifc, &IpInterface{})
+
+	checkJSONContains(t, data, `"family":"AF_INET6"`, `"routerDiscoveryBehavior":"RouterDiscoveryDhcp"`,
+		`"linkLocalAddressBehavior":"LinkLocalAlwaysOn"`, `"transmitOffload":{"nlChecksumSupported":true,`,
+		`"nlMtu":1500`)
+}
+
+func TestJSONRoute(t *testing.T) {
+
+	routes := []*Route{
+		{
+			InterfaceLuid:  1689399616077824,
+			InterfaceIndex: 12,
+			DestinationPrefix: IpAddressPrefix{
+				Prefix:       SockaddrInet{Family: AF_INET, Address: net.IPv4(0, 0, 0, 0)},
+				PrefixLength: 0,
+			},
+			NextHop:           SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 1)},
+			ValidLifetime:     0xffffffff,
+			PreferredLifetime: 0xffffffff,
+			Metric:            0,
+			Protocol:          RouteProtocolNetMgmt,
+			Origin:            NlroManual,
+		},
+		{
+			InterfaceLuid:  1689399616077824,
+			InterfaceIndex: 12,
+			DestinationPrefix: IpAddressPrefix{
+				Prefix:       SockaddrInet{Family: AF_INET6, Address: net.ParseIP("fe80::"), IPv6ScopeId: 12},
+				PrefixLength: 64,
+			},
+			NextHop:  SockaddrInet{Family: AF_INET6, Address: net.ParseIP("::")},
+			Metric:   256,
+			Protocol: RouteProtocolLocal,
+			Loopback: true,
+			Publish:  true,
+			Immortal: true,
+			Age:      1234,
+		},
+	}
+
+	var decoded []*Route
+
+	data := testJSONRoundTrip(t, &routes, &decoded)
+
+	checkJSONContains(t, data, `"destinationPrefix":"0.0.0.0/0"`, `"nextHop":{"address":"192.0.2.1"}`,
+		`"destinationPrefix":"fe80::%12/64"`, `"nextHop":{"address":"::"}`, `"protocol":"RouteProtocolNetMgmt"`,
+		`"origin":"NlroManual"`)
+
+	routeData := &RouteData{
+		Destination: net.IPNet{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)},
+		NextHop:     net.IPv4(192, 0, 2, 1),
+		Metric:      5,
+	}
+
+	data = testJSONRoundTrip(t, routeData, &RouteData{})
+
+	if data != `{"destination":"10.0.0.0/8","nextHop":"192.0.2.1","metric":5}` {
+		t.Errorf("Unexpected RouteData JSON: %s", data)
+	}
+
+	// Host bits of the destination are kept.
+	routeData = &RouteData{
+		Destination: net.IPNet{IP: net.IPv4(10, 0, 0, 1).To4(), Mask: net.CIDRMask(24, 32)},
+		NextHop:     net.IPv4(192, 0, 2, 1),
+	}
+
+	data = testJSONRoundTrip(t, routeData, &RouteData{})
+
+	checkJSONContains(t, data, `"destination":"10.0.0.1/24"`)
+
+	routeData = &RouteData{Destination: net.IPNet{IP: net.ParseIP("fd00::1"), Mask: net.CIDRMask(64, 128)}}
+
+	data = testJSONRoundTrip(t, routeData, &RouteData{})
+
+	checkJSONContains(t, data, `"destination":"fd00::1/64"`)
+}
+
+func TestJSONSockaddrInetMappedAddress(t *testing.T) {
+
+	sainets := []*SockaddrInet{
+		{Family: AF_INET6, Address: net.ParseIP("::ffff:192.0.2.1"), Port: 53},
+		{Family: AF_INET6, Address: net.ParseIP("::ffff:192.0.2.1"), IPv6ScopeId: 12},
+		{Family: AF_INET, Address: net.ParseIP("192.0.2.1"), Port: 53},
+	}
+
+	expected := []string{
+		`{"address":"::ffff:192.0.2.1","port":53}`,
+		`{"address":"::ffff:192.0.2.1%12"}`,
+		`{"address":"192.0.2.1","port":53}`,
+	}
+
+	for i, sainet := range sainets {
+		if data := testJSONRoundTrip(t, sainet, &SockaddrInet{}); data != expected[i] {
+			t.Errorf("SockaddrInet %d was encoded as %s instead of %s.", i, data, expected[i])
+		}
+	}
+
+	prefix := &IpAddressPrefix{
+		Prefix:       SockaddrInet{Family: AF_INET6, Address: net.ParseIP("::ffff:192.0.2.0")},
+		PrefixLength: 120,
+	}
+
+	data := testJSONRoundTrip(t, prefix, &IpAddressPrefix{})
+
+	if data != `"::ffff:192.0.2.0/120"` {
+		t.Errorf("IpAddressPrefix with an IPv4-mapped address was encoded as %s.", data)
+	}
+
+	adapterPrefix := &IpAdapterPrefix{PrefixLength: 120}
+	adapterPrefix.InterfaceLuid = 1689399616077824
+	adapterPrefix.Address = SockaddrInet{Family: AF_INET6, Address: net.ParseIP("::ffff:192.0.2.0")}
+
+	data = testJSONRoundTrip(t, adapterPrefix, &IpAdapterPrefix{})
+
+	checkJSONContains(t, data, `"prefix":"::ffff:192.0.2.0/120"`)
+}
+
+func TestJSONUnicastIpAddressRow(t *testing.T) {
+
+	rows := []*UnicastIpAddressRow{
+		{
+			Address:            &SockaddrInet{Family: AF_INET6, Address: net.ParseIP("fe80::1234"), IPv6ScopeId: 12},
+			InterfaceLuid:      1689399616077824,
+			InterfaceIndex:     12,
+			PrefixOrigin:       IpPrefixOriginWellKnown,
+			SuffixOrigin:       IpSuffixOriginLinkLayerAddress,
+			ValidLifetime:      0xffffffff,
+			PreferredLifetime:  0xffffffff,
+			OnLinkPrefixLength: 64,
+			DadState:           IpDadStatePreferred,
+			ScopeId:            0x2000000c,
+			CreationTimeStamp:  131976288000000000,
+		},
+		{
+			Address:            &SockaddrInet{Family: AF_INET, Address: net.IPv4(192, 0, 2, 10)},
+			InterfaceLuid:      1689399616077824,
+			InterfaceIndex:     12,
+			PrefixOrigin:       IpPrefixOriginManual,
+			SuffixOrigin:       IpSuffixOriginManual,
+			OnLinkPrefixLength: 24,
+			SkipAsSource:       true,
+			DadState:           IpDadStateTentative,
+		},
+		{},
+	}
+
+	var decoded []*UnicastIpAddressRow
+
+	data := testJSONRoundTrip(t, &rows, &decoded)
+
+	checkJSONContains(t, data, `"address":{"address":"fe80::1234%12"}`, `"address":{"address":"192.0.2.10"}`,
+		`"address":null`, `"prefixOrigin":"IpPrefixOriginWellKnown"`,
+		`"suffixOrigin":"IpSuffixOriginLinkLayerAddress"`, `"dadState":"IpDadStateTentative"`,
+		`"skipAsSource":true`)
+}
+
+func TestJSONSockaddrInetErrors(t *testing.T) {
+
+	invalid := []string{
+		`{"address":"192.0.2.1%12"}`,
+		`{"address":"192.0.2.1","flowInfo":1}`,
+		`{"address":"fe80::1%eth0"}`,
+		`{"address":"not an address"}`,
+		`{"address":""}`,
+	}
+
+	for _, data := range invalid {
+
+		var sainet SockaddrInet
+
+		if err := json.Unmarshal([]byte(data), &sainet); err == nil {
+			t.Errorf("json.Unmarshal(%s) to SockaddrInet should have failed.", data)
+		}
+	}
+
+	for _, data := range []string{`"10.0.0.0/33"`, `"fe80::/129"`, `"10.0.0.0"`, `"fe80::%x/64"`} {
+
+		var prefix IpAddressPrefix
+
+		if err := json.Unmarshal([]byte(data), &prefix); err == nil {
+			t.Errorf("json.Unmarshal(%s) to IpAddressPrefix should have failed.", data)
+		}
+	}
+}
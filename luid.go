@@ -0,0 +1,48 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import "fmt"
+
+// Corresponds to Windows union NET_LUID
+// (https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ns-ifdef-_net_luid_lh). Values of Interface.Luid,
+// IfRow.InterfaceLuid and similar fields can be converted to Luid directly. Luid-typed variants of the LUID conversion
+// functions are its methods (i.e. Luid.Index()) and LuidFrom...() functions (i.e. LuidFromIndex()).
+type Luid uint64
+
+const (
+	luidNetLuidIndexShift = 24
+	luidNetLuidIndexMask  = 0xffffff
+	luidIfTypeShift       = 48
+	luidIfTypeMask        = 0xffff
+)
+
+// Returns Luid made of 'netLuidIndex' (which has to fit in 24 bits) and 'ifType' (which has to fit in 16 bits).
+func NewLuid(netLuidIndex uint32, ifType IfType) (Luid, error) {
+
+	if netLuidIndex > luidNetLuidIndexMask {
+		return 0, fmt.Errorf("NewLuid() - NetLuidIndex has to fit in 24 bits")
+	}
+
+	if ifType > luidIfTypeMask {
+		return 0, fmt.Errorf("NewLuid() - IfType has to fit in 16 bits")
+	}
+
+	return Luid(uint64(netLuidIndex)<<luidNetLuidIndexShift | uint64(ifType)<<luidIfTypeShift), nil
+}
+
+// Returns the index which, together with IfType, uniquely identifies the interface.
+func (luid Luid) NetLuidIndex() uint32 {
+	return uint32(luid>>luidNetLuidIndexShift) & luidNetLuidIndexMask
+}
+
+func (luid Luid) IfType() IfType {
+	return IfType(luid>>luidIfTypeShift) & luidIfTypeMask
+}
+
+func (luid Luid) String() string {
+	return fmt.Sprintf("%d (NetLuidIndex: %d; IfType: %s)", uint64(luid), luid.NetLuidIndex(), luid.IfType().String())
+}
@@ -0,0 +1,45 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import "testing"
+
+func TestLuid(t *testing.T) {
+
+	luid, err := NewLuid(32768, IF_TYPE_ETHERNET_CSMACD)
+
+	if err != nil {
+		t.Fatalf("NewLuid() returned an error: %v", err)
+	}
+
+	if luid != 1689399616077824 {
+		t.Errorf("NewLuid() returned %d although 1689399616077824 is expected.", uint64(luid))
+	}
+
+	if luid.NetLuidIndex() != 32768 {
+		t.Errorf("Luid.NetLuidIndex() returned %d although 32768 is expected.", luid.NetLuidIndex())
+	}
+
+	if luid.IfType() != IF_TYPE_ETHERNET_CSMACD {
+		t.Errorf("Luid.IfType() returned %s although IF_TYPE_ETHERNET_CSMACD is expected.", luid.IfType().String())
+	}
+
+	// Reserved bits are ignored.
+	luid = Luid(uint64(luid) | 0xabcdef)
+
+	if luid.NetLuidIndex() != 32768 || luid.IfType() != IF_TYPE_ETHERNET_CSMACD {
+		t.Errorf("Luid accessors returned %d and %s for a LUID with reserved bits set.", luid.NetLuidIndex(),
+			luid.IfType().String())
+	}
+
+	if _, err = NewLuid(0x1000000, IF_TYPE_OTHER); err == nil {
+		t.Error("NewLuid() didn't return an error for a 25-bit NetLuidIndex.")
+	}
+
+	if _, err = NewLuid(1, IfType(0x10000)); err == nil {
+		t.Error("NewLuid() didn't return an error for a 17-bit IfType.")
+	}
+}
@@ -0,0 +1,268 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Content type of the OpenMetrics text format served by MetricsHandler.
+const OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Source of IpInterfaces exported by MetricsHandler.
+type IpInterfaceSource func(family AddressFamily) ([]*IpInterface, error)
+
+// http.Handler exposing interface counters and state in OpenMetrics text format
+// (https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md), for Prometheus and
+// compatible monitoring systems. Exported are:
+//   - IfRow counters (octets, errors and discards), OutQLen and link speeds;
+//   - OperStatus and MediaConnectState, as state sets;
+//   - IpInterface Metric and NlMtu, per address family.
+//
+// Each interface's metrics are labeled by its alias, GUID and IfType.
+type MetricsHandler struct {
+	ifRows       IfRowSource
+	ipInterfaces IpInterfaceSource
+}
+
+// Creates new MetricsHandler exporting IfRows from 'ifRows' and IpInterfaces from 'ipInterfaces'. Nil sources mean
+// all the interfaces, i.e. AllIfRowsSource() and GetIpInterfaces respectively.
+func NewMetricsHandler(ifRows IfRowSource, ipInterfaces IpInterfaceSource) *MetricsHandler {
+
+	if ifRows == nil {
+		ifRows = AllIfRowsSource()
+	}
+
+	if ipInterfaces == nil {
+		ipInterfaces = GetIpInterfaces
+	}
+
+	return &MetricsHandler{ifRows: ifRows, ipInterfaces: ipInterfaces}
+}
+
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Buffered, so that a failure of the sources results in an error response rather than in truncated metrics.
+	buffer := &bytes.Buffer{}
+
+	if err := h.WriteMetrics(buffer); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", OpenMetricsContentType)
+
+	if r.Method == http.MethodGet {
+		_, _ = buffer.WriteTo(w)
+	}
+}
+
+// Writes the metrics to 'w' in OpenMetrics text format.
+func (h *MetricsHandler) WriteMetrics(w io.Writer) error {
+
+	sourceIfrows, err := h.ifRows()
+
+	if err != nil {
+		return err
+	}
+
+	// Sorted for stable output, without reordering the source's slice.
+	ifrows := append([]*IfRow(nil), sourceIfrows...)
+
+	sort.Slice(ifrows, func(i, j int) bool { return ifrows[i].InterfaceLuid < ifrows[j].InterfaceLuid })
+
+	ipifcs := make([]*IpInterface, 0)
+
+	for _, family := range []AddressFamily{AF_INET, AF_INET6} {
+
+		familyIpifcs, err := h.ipInterfaces(family)
+
+		if err != nil {
+			return err
+		}
+
+		ipifcs = append(ipifcs, familyIpifcs...)
+	}
+
+	sort.SliceStable(ipifcs, func(i, j int) bool { return ipifcs[i].InterfaceLuid < ipifcs[j].InterfaceLuid })
+
+	mw := &metricsWriter{labels: make(map[uint64]string, len(ifrows))}
+
+	for _, ifrow := range ifrows {
+		mw.labels[ifrow.InterfaceLuid] = formatMetricLabels("alias", ifrow.Alias, "guid", ifrow.InterfaceGuid.String(),
+			"type", ifrow.Type.String())
+	}
+
+	ifrowCounters := []struct {
+		name       string
+		help       string
+		unit       string
+		value      func(ifrow *IfRow) uint64
+		metricType string
+	}{
+		{"winipcfg_interface_received_bytes", "Octets received on the interface.", "bytes",
+			func(ifrow *IfRow) uint64 { return ifrow.InOctets }, "counter"},
+		{"winipcfg_interface_sent_bytes", "Octets sent on the interface.", "bytes",
+			func(ifrow *IfRow) uint64 { return ifrow.OutOctets }, "counter"},
+		{"winipcfg_interface_receive_errors", "Inbound packets discarded because of errors.", "",
+			func(ifrow *IfRow) uint64 { return ifrow.InErrors }, "counter"},
+		{"winipcfg_interface_send_errors", "Outbound packets discarded because of errors.", "",
+			func(ifrow *IfRow) uint64 { return ifrow.OutErrors }, "counter"},
+		{"winipcfg_interface_receive_discards", "Inbound packets discarded without errors.", "",
+			func(ifrow *IfRow) uint64 { return ifrow.InDiscards }, "counter"},
+		{"winipcfg_interface_send_discards", "Outbound packets discarded without errors.", "",
+			func(ifrow *IfRow) uint64 { return ifrow.OutDiscards }, "counter"},
+		{"winipcfg_interface_output_queue_length", "Length of the output packet queue.", "",
+			func(ifrow *IfRow) uint64 { return ifrow.OutQLen }, "gauge"},
+		{"winipcfg_interface_transmit_link_speed_bits_per_second", "Speed of the transmit link.", "bits_per_second",
+			func(ifrow *IfRow) uint64 { return ifrow.TransmitLinkSpeed }, "gauge"},
+		{"winipcfg_interface_receive_link_speed_bits_per_second", "Speed of the receive link.", "bits_per_second",
+			func(ifrow *IfRow) uint64 { return ifrow.ReceiveLinkSpeed }, "gauge"},
+	}
+
+	for _, counter := range ifrowCounters {
+
+		mw.header(counter.name, counter.metricType, counter.unit, counter.help)
+
+		suffix := ""
+
+		if counter.metricType == "counter" {
+			suffix = "_total"
+		}
+
+		for _, ifrow := range ifrows {
+			mw.sample(counter.name+suffix, mw.labels[ifrow.InterfaceLuid], "", counter.value(ifrow))
+		}
+	}
+
+	mw.header("winipcfg_interface_oper_status", "stateset", "", "Operational status of the interface.")
+
+	for _, ifrow := range ifrows {
+		mw.stateSet("winipcfg_interface_oper_status", ifrow.InterfaceLuid, ifOperStatusTable,
+			int64(ifrow.OperStatus))
+	}
+
+	mw.header("winipcfg_interface_media_connect_state", "stateset", "", "Connection state of the interface's media.")
+
+	for _, ifrow := range ifrows {
+		mw.stateSet("winipcfg_interface_media_connect_state", ifrow.InterfaceLuid, netIfMediaConnectStateTable,
+			int64(ifrow.MediaConnectState))
+	}
+
+	ipifcGauges := []struct {
+		name  string
+		help  string
+		unit  string
+		value func(ipifc *IpInterface) uint64
+	}{
+		{"winipcfg_ip_interface_metric", "Metric of the interface for the address family.", "",
+			func(ipifc *IpInterface) uint64 { return uint64(ipifc.Metric) }},
+		{"winipcfg_ip_interface_mtu_bytes", "MTU of the interface for the address family.", "bytes",
+			func(ipifc *IpInterface) uint64 { return uint64(ipifc.NlMtu) }},
+	}
+
+	for _, gauge := range ipifcGauges {
+
+		mw.header(gauge.name, "gauge", gauge.unit, gauge.help)
+
+		for _, ipifc := range ipifcs {
+
+			labels, ok := mw.labels[ipifc.InterfaceLuid]
+
+			// Interfaces without IfRow (i.e. not covered by the IfRow source) are left out.
+			if !ok {
+				continue
+			}
+
+			mw.sample(gauge.name, labels, formatMetricLabels("family", ipifc.Family.String()), gauge.value(ipifc))
+		}
+	}
+
+	mw.buffer.WriteString("# EOF\n")
+
+	_, err = w.Write(mw.buffer.Bytes())
+
+	return err
+}
+
+type metricsWriter struct {
+	buffer bytes.Buffer
+
+	// Formatted labels of interfaces, by LUID.
+	labels map[uint64]string
+}
+
+func (mw *metricsWriter) header(name string, metricType string, unit string, help string) {
+
+	fmt.Fprintf(&mw.buffer, "# TYPE %s %s\n", name, metricType)
+
+	if unit != "" {
+		fmt.Fprintf(&mw.buffer, "# UNIT %s %s\n", name, unit)
+	}
+
+	fmt.Fprintf(&mw.buffer, "# HELP %s %s\n", name, help)
+}
+
+func (mw *metricsWriter) sample(name string, labels string, extraLabels string, value uint64) {
+
+	if extraLabels != "" {
+		labels += "," + extraLabels
+	}
+
+	fmt.Fprintf(&mw.buffer, "%s{%s} %d\n", name, labels, value)
+}
+
+// Writes state set sample with all the known values of 'table', the one equal to 'value' being set. Unknown 'value' is
+// written as an additional state.
+func (mw *metricsWriter) stateSet(name string, interfaceLuid uint64, table *enumTable, value int64) {
+
+	known := false
+
+	for _, entry := range table.entries {
+
+		set := uint64(0)
+
+		if entry.value == value {
+			set = 1
+			known = true
+		}
+
+		mw.sample(name, mw.labels[interfaceLuid], formatMetricLabels(name, entry.name), set)
+	}
+
+	if !known {
+		mw.sample(name, mw.labels[interfaceLuid], formatMetricLabels(name, table.format(value)), 1)
+	}
+}
+
+var metricLabelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Formats label pairs given as alternating names and values.
+func formatMetricLabels(namesAndValues ...string) string {
+
+	pairs := make([]string, 0, len(namesAndValues)/2)
+
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, namesAndValues[i],
+			metricLabelValueReplacer.Replace(namesAndValues[i+1])))
+	}
+
+	return strings.Join(pairs, ",")
+}
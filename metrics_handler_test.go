@@ -0,0 +1,108 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func metricsHandlerTestSources() (IfRowSource, IpInterfaceSource) {
+
+	ifrows := []*IfRow{
+		{
+			InterfaceLuid:     2 << 24,
+			InterfaceGuid:     GUID{Data1: 0x2d4bf17a, Data2: 0x8d1a, Data3: 0x4b3c, Data4: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+			Alias:             `Tunnel "wg0"`,
+			Type:              IF_TYPE_PROP_VIRTUAL,
+			OperStatus:        IfOperStatusDown,
+			MediaConnectState: MediaConnectStateDisconnected,
+			InOctets:          10,
+		},
+		{
+			InterfaceLuid:     1 << 24,
+			InterfaceGuid:     GUID{Data1: 0x9a1b2c3d, Data2: 0x1111, Data3: 0x2222, Data4: [8]byte{8, 7, 6, 5, 4, 3, 2, 1}},
+			Alias:             "Ethernet",
+			Type:              IF_TYPE_ETHERNET_CSMACD,
+			OperStatus:        IfOperStatusUp,
+			MediaConnectState: MediaConnectStateConnected,
+			TransmitLinkSpeed: 1000000000,
+			ReceiveLinkSpeed:  1000000000,
+			InOctets:          123456789,
+			OutOctets:         987654321,
+			InErrors:          1,
+			OutErrors:         2,
+			InDiscards:        3,
+			OutDiscards:       4,
+			OutQLen:           5,
+		},
+	}
+
+	ipifcs := map[AddressFamily][]*IpInterface{
+		AF_INET: {
+			{Family: AF_INET, InterfaceLuid: 1 << 24, Metric: 25, NlMtu: 1500},
+			{Family: AF_INET, InterfaceLuid: 2 << 24, Metric: 5, NlMtu: 1420},
+			// Loopback, not covered by the IfRow source.
+			{Family: AF_INET, InterfaceLuid: 3 << 24, Metric: 75, NlMtu: 4294967295},
+		},
+		AF_INET6: {
+			{Family: AF_INET6, InterfaceLuid: 2 << 24, Metric: 5, NlMtu: 1420},
+		},
+	}
+
+	return func() ([]*IfRow, error) {
+			return ifrows, nil
+		}, func(family AddressFamily) ([]*IpInterface, error) {
+			return ipifcs[family], nil
+		}
+}
+
+func TestMetricsHandler(t *testing.T) {
+
+	handler := NewMetricsHandler(metricsHandlerTestSources())
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("MetricsHandler responded with status %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	if contentType := recorder.Header().Get("Content-Type"); contentType != OpenMetricsContentType {
+		t.Errorf("MetricsHandler responded with Content-Type %s.", contentType)
+	}
+
+	checkGolden(t, "metrics.txt", recorder.Body.Bytes())
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+
+	if recorder.Code != http.StatusMethodNotAllowed {
+		t.Errorf("MetricsHandler responded to POST with status %d.", recorder.Code)
+	}
+}
+
+func TestMetricsHandlerSourceError(t *testing.T) {
+
+	ifRows, _ := metricsHandlerTestSources()
+
+	handler := NewMetricsHandler(ifRows, func(family AddressFamily) ([]*IpInterface, error) {
+		return nil, fmt.Errorf("GetIpInterfaceTable failed")
+	})
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("MetricsHandler responded with status %d instead of %d.", recorder.Code,
+			http.StatusInternalServerError)
+	}
+}
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // MIB_IF_ENTRY_LEVEL defined in netioapi.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getifentry2ex)
 type MibIfEntryLevel uint32
@@ -16,13 +14,34 @@ const (
 	MibIfEntryNormalWithoutStatistics MibIfEntryLevel = 2
 )
 
+var mibIfEntryLevelTable = &enumTable{
+	typeName:    "MibIfEntryLevel",
+	unknown:     "MibIfEntryLevel_UNKNOWN",
+	shortPrefix: "MibIfEntry",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(MibIfEntryNormal), "MibIfEntryNormal"},
+		{int64(MibIfEntryNormalWithoutStatistics), "MibIfEntryNormalWithoutStatistics"},
+	},
+}
+
 func (lvl MibIfEntryLevel) String() string {
-	switch lvl {
-	case MibIfEntryNormal:
-		return "MibIfEntryNormal"
-	case MibIfEntryNormalWithoutStatistics:
-		return "MibIfEntryNormalWithoutStatistics"
-	default:
-		return fmt.Sprintf("MibIfEntryLevel_UNKNOWN(%d)", lvl)
+	return mibIfEntryLevelTable.format(int64(lvl))
+}
+
+func (lvl MibIfEntryLevel) MarshalText() ([]byte, error) {
+	return []byte(lvl.String()), nil
+}
+
+func (lvl *MibIfEntryLevel) UnmarshalText(text []byte) error {
+
+	value, err := mibIfEntryLevelTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*lvl = MibIfEntryLevel(value)
+
+	return nil
 }
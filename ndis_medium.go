@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // NDIS_MEDIUM defined in ntddndis.h
 // (https://docs.microsoft.com/en-us/windows-hardware/drivers/ddi/content/ntddndis/ne-ntddndis-_ndis_medium)
 type NdisMedium uint32
@@ -35,51 +33,53 @@ const (
 	NdisMediumMax          NdisMedium = 20
 )
 
+var ndisMediumTable = &enumTable{
+	typeName:    "NdisMedium",
+	unknown:     "NdisMedium_UNKNOWN",
+	shortPrefix: "NdisMedium",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NdisMedium802_3), "NdisMedium802_3"},
+		{int64(NdisMedium802_5), "NdisMedium802_5"},
+		{int64(NdisMediumFddi), "NdisMediumFddi"},
+		{int64(NdisMediumWan), "NdisMediumWan"},
+		{int64(NdisMediumLocalTalk), "NdisMediumLocalTalk"},
+		{int64(NdisMediumDix), "NdisMediumDix"},
+		{int64(NdisMediumArcnetRaw), "NdisMediumArcnetRaw"},
+		{int64(NdisMediumArcnet878_2), "NdisMediumArcnet878_2"},
+		{int64(NdisMediumAtm), "NdisMediumAtm"},
+		{int64(NdisMediumWirelessWan), "NdisMediumWirelessWan"},
+		{int64(NdisMediumIrda), "NdisMediumIrda"},
+		{int64(NdisMediumBpc), "NdisMediumBpc"},
+		{int64(NdisMediumCoWan), "NdisMediumCoWan"},
+		{int64(NdisMedium1394), "NdisMedium1394"},
+		{int64(NdisMediumInfiniBand), "NdisMediumInfiniBand"},
+		{int64(NdisMediumTunnel), "NdisMediumTunnel"},
+		{int64(NdisMediumNative802_11), "NdisMediumNative802_11"},
+		{int64(NdisMediumLoopback), "NdisMediumLoopback"},
+		{int64(NdisMediumWiMAX), "NdisMediumWiMAX"},
+		{int64(NdisMediumIP), "NdisMediumIP"},
+		{int64(NdisMediumMax), "NdisMediumMax"},
+	},
+}
+
 func (nm NdisMedium) String() string {
-	switch nm {
-	case NdisMedium802_3:
-		return "NdisMedium802_3"
-	case NdisMedium802_5:
-		return "NdisMedium802_5"
-	case NdisMediumFddi:
-		return "NdisMediumFddi"
-	case NdisMediumWan:
-		return "NdisMediumWan"
-	case NdisMediumLocalTalk:
-		return "NdisMediumLocalTalk"
-	case NdisMediumDix:
-		return "NdisMediumDix"
-	case NdisMediumArcnetRaw:
-		return "NdisMediumArcnetRaw"
-	case NdisMediumArcnet878_2:
-		return "NdisMediumArcnet878_2"
-	case NdisMediumAtm:
-		return "NdisMediumAtm"
-	case NdisMediumWirelessWan:
-		return "NdisMediumWirelessWan"
-	case NdisMediumIrda:
-		return "NdisMediumIrda"
-	case NdisMediumBpc:
-		return "NdisMediumBpc"
-	case NdisMediumCoWan:
-		return "NdisMediumCoWan"
-	case NdisMedium1394:
-		return "NdisMedium1394"
-	case NdisMediumInfiniBand:
-		return "NdisMediumInfiniBand"
-	case NdisMediumTunnel:
-		return "NdisMediumTunnel"
-	case NdisMediumNative802_11:
-		return "NdisMediumNative802_11"
-	case NdisMediumLoopback:
-		return "NdisMediumLoopback"
-	case NdisMediumWiMAX:
-		return "NdisMediumWiMAX"
-	case NdisMediumIP:
-		return "NdisMediumIP"
-	case NdisMediumMax:
-		return "NdisMediumMax"
-	default:
-		return fmt.Sprintf("NdisMedium_UNKNOWN(%d)", nm)
+	return ndisMediumTable.format(int64(nm))
+}
+
+func (nm NdisMedium) MarshalText() ([]byte, error) {
+	return []byte(nm.String()), nil
+}
+
+func (nm *NdisMedium) UnmarshalText(text []byte) error {
+
+	value, err := ndisMediumTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*nm = NdisMedium(value)
+
+	return nil
 }
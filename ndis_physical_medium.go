@@ -5,10 +5,8 @@
 
 package winipcfg
 
-import "fmt"
-
 // NDIS_PHYSICAL_MEDIUM defined in ntddndis.h
-//(https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/ns-netioapi-_mib_if_row2)
+// (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/ns-netioapi-_mib_if_row2)
 type NdisPhysicalMedium uint32
 
 const (
@@ -36,53 +34,54 @@ const (
 	NdisPhysicalMediumMax            NdisPhysicalMedium = 21
 )
 
+var ndisPhysicalMediumTable = &enumTable{
+	typeName:    "NdisPhysicalMedium",
+	unknown:     "NdisPhysicalMedium_UNKNOWN",
+	shortPrefix: "NdisPhysicalMedium",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NdisPhysicalMediumUnspecified), "NdisPhysicalMediumUnspecified"},
+		{int64(NdisPhysicalMediumWirelessLan), "NdisPhysicalMediumWirelessLan"},
+		{int64(NdisPhysicalMediumCableModem), "NdisPhysicalMediumCableModem"},
+		{int64(NdisPhysicalMediumPhoneLine), "NdisPhysicalMediumPhoneLine"},
+		{int64(NdisPhysicalMediumPowerLine), "NdisPhysicalMediumPowerLine"},
+		{int64(NdisPhysicalMediumDSL), "NdisPhysicalMediumDSL"},
+		{int64(NdisPhysicalMediumFibreChannel), "NdisPhysicalMediumFibreChannel"},
+		{int64(NdisPhysicalMedium1394), "NdisPhysicalMedium1394"},
+		{int64(NdisPhysicalMediumWirelessWan), "NdisPhysicalMediumWirelessWan"},
+		{int64(NdisPhysicalMediumNative802_11), "NdisPhysicalMediumNative802_11"},
+		{int64(NdisPhysicalMediumBluetooth), "NdisPhysicalMediumBluetooth"},
+		{int64(NdisPhysicalMediumInfiniband), "NdisPhysicalMediumInfiniband"},
+		{int64(NdisPhysicalMediumWiMax), "NdisPhysicalMediumWiMax"},
+		{int64(NdisPhysicalMediumUWB), "NdisPhysicalMediumUWB"},
+		{int64(NdisPhysicalMedium802_3), "NdisPhysicalMedium802_3"},
+		{int64(NdisPhysicalMedium802_5), "NdisPhysicalMedium802_5"},
+		{int64(NdisPhysicalMediumIrda), "NdisPhysicalMediumIrda"},
+		{int64(NdisPhysicalMediumWiredWAN), "NdisPhysicalMediumWiredWAN"},
+		{int64(NdisPhysicalMediumWiredCoWan), "NdisPhysicalMediumWiredCoWan"},
+		{int64(NdisPhysicalMediumOther), "NdisPhysicalMediumOther"},
+		{int64(NdisPhysicalMediumNative802_15_4), "NdisPhysicalMediumNative802_15_4"},
+		{int64(NdisPhysicalMediumMax), "NdisPhysicalMediumMax"},
+	},
+}
+
 func (npm NdisPhysicalMedium) String() string {
-	switch npm {
-	case NdisPhysicalMediumUnspecified:
-		return "NdisPhysicalMediumUnspecified"
-	case NdisPhysicalMediumWirelessLan:
-		return "NdisPhysicalMediumWirelessLan"
-	case NdisPhysicalMediumCableModem:
-		return "NdisPhysicalMediumCableModem"
-	case NdisPhysicalMediumPhoneLine:
-		return "NdisPhysicalMediumPhoneLine"
-	case NdisPhysicalMediumPowerLine:
-		return "NdisPhysicalMediumPowerLine"
-	case NdisPhysicalMediumDSL:
-		return "NdisPhysicalMediumDSL"
-	case NdisPhysicalMediumFibreChannel:
-		return "NdisPhysicalMediumFibreChannel"
-	case NdisPhysicalMedium1394:
-		return "NdisPhysicalMedium1394"
-	case NdisPhysicalMediumWirelessWan:
-		return "NdisPhysicalMediumWirelessWan"
-	case NdisPhysicalMediumNative802_11:
-		return "NdisPhysicalMediumNative802_11"
-	case NdisPhysicalMediumBluetooth:
-		return "NdisPhysicalMediumBluetooth"
-	case NdisPhysicalMediumInfiniband:
-		return "NdisPhysicalMediumInfiniband"
-	case NdisPhysicalMediumWiMax:
-		return "NdisPhysicalMediumWiMax"
-	case NdisPhysicalMediumUWB:
-		return "NdisPhysicalMediumUWB"
-	case NdisPhysicalMedium802_3:
-		return "NdisPhysicalMedium802_3"
-	case NdisPhysicalMedium802_5:
-		return "NdisPhysicalMedium802_5"
-	case NdisPhysicalMediumIrda:
-		return "NdisPhysicalMediumIrda"
-	case NdisPhysicalMediumWiredWAN:
-		return "NdisPhysicalMediumWiredWAN"
-	case NdisPhysicalMediumWiredCoWan:
-		return "NdisPhysicalMediumWiredCoWan"
-	case NdisPhysicalMediumOther:
-		return "NdisPhysicalMediumOther"
-	case NdisPhysicalMediumNative802_15_4:
-		return "NdisPhysicalMediumNative802_15_4"
-	case NdisPhysicalMediumMax:
-		return "NdisPhysicalMediumMax"
-	default:
-		return fmt.Sprintf("NdisPhysicalMedium_UNKNOWN(%d)", npm)
+	return ndisPhysicalMediumTable.format(int64(npm))
+}
+
+func (npm NdisPhysicalMedium) MarshalText() ([]byte, error) {
+	return []byte(npm.String()), nil
+}
+
+func (npm *NdisPhysicalMedium) UnmarshalText(text []byte) error {
+
+	value, err := ndisPhysicalMediumTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*npm = NdisPhysicalMedium(value)
+
+	return nil
 }
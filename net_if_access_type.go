@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // NET_IF_ACCESS_TYPE defined in ifdef.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-_net_if_access_type)
 type NetIfAccessType uint32
@@ -19,19 +17,37 @@ const (
 	NET_IF_ACCESS_MAXIMUM              NetIfAccessType = 5
 )
 
+var netIfAccessTypeTable = &enumTable{
+	typeName:    "NetIfAccessType",
+	unknown:     "NetIfAccessType_UNKNOWN",
+	shortPrefix: "NET_IF_ACCESS_",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NET_IF_ACCESS_LOOPBACK), "NET_IF_ACCESS_LOOPBACK"},
+		{int64(NET_IF_ACCESS_BROADCAST), "NET_IF_ACCESS_BROADCAST"},
+		{int64(NET_IF_ACCESS_POINT_TO_POINT), "NET_IF_ACCESS_POINT_TO_POINT"},
+		{int64(NET_IF_ACCESS_POINT_TO_MULTI_POINT), "NET_IF_ACCESS_POINT_TO_MULTI_POINT"},
+		{int64(NET_IF_ACCESS_MAXIMUM), "NET_IF_ACCESS_MAXIMUM"},
+	},
+}
+
 func (niat NetIfAccessType) String() string {
-	switch niat {
-	case NET_IF_ACCESS_LOOPBACK:
-		return "NET_IF_ACCESS_LOOPBACK"
-	case NET_IF_ACCESS_BROADCAST:
-		return "NET_IF_ACCESS_BROADCAST"
-	case NET_IF_ACCESS_POINT_TO_POINT:
-		return "NET_IF_ACCESS_POINT_TO_POINT"
-	case NET_IF_ACCESS_POINT_TO_MULTI_POINT:
-		return "NET_IF_ACCESS_POINT_TO_MULTI_POINT"
-	case NET_IF_ACCESS_MAXIMUM:
-		return "NET_IF_ACCESS_MAXIMUM"
-	default:
-		return fmt.Sprintf("NetIfAccessType_UNKNOWN(%d)", niat)
+	return netIfAccessTypeTable.format(int64(niat))
+}
+
+func (niat NetIfAccessType) MarshalText() ([]byte, error) {
+	return []byte(niat.String()), nil
+}
+
+func (niat *NetIfAccessType) UnmarshalText(text []byte) error {
+
+	value, err := netIfAccessTypeTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*niat = NetIfAccessType(value)
+
+	return nil
 }
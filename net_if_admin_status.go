@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // NET_IF_ADMIN_STATUS defined in ifdef.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-net_if_admin_status)
 type NetIfAdminStatus uint32
@@ -17,15 +15,35 @@ const (
 	NET_IF_ADMIN_STATUS_TESTING NetIfAdminStatus = 3
 )
 
+var netIfAdminStatusTable = &enumTable{
+	typeName:    "NetIfAdminStatus",
+	unknown:     "NetIfAdminStatus_UNKNOWN",
+	shortPrefix: "NET_IF_ADMIN_STATUS_",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NET_IF_ADMIN_STATUS_UP), "NET_IF_ADMIN_STATUS_UP"},
+		{int64(NET_IF_ADMIN_STATUS_DOWN), "NET_IF_ADMIN_STATUS_DOWN"},
+		{int64(NET_IF_ADMIN_STATUS_TESTING), "NET_IF_ADMIN_STATUS_TESTING"},
+	},
+}
+
 func (nias NetIfAdminStatus) String() string {
-	switch nias {
-	case NET_IF_ADMIN_STATUS_UP:
-		return "NET_IF_ADMIN_STATUS_UP"
-	case NET_IF_ADMIN_STATUS_DOWN:
-		return "NET_IF_ADMIN_STATUS_DOWN"
-	case NET_IF_ADMIN_STATUS_TESTING:
-		return "NET_IF_ADMIN_STATUS_TESTING"
-	default:
-		return fmt.Sprintf("NetIfAdminStatus_UNKNOWN(%d)", nias)
+	return netIfAdminStatusTable.format(int64(nias))
+}
+
+func (nias NetIfAdminStatus) MarshalText() ([]byte, error) {
+	return []byte(nias.String()), nil
+}
+
+func (nias *NetIfAdminStatus) UnmarshalText(text []byte) error {
+
+	value, err := netIfAdminStatusTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*nias = NetIfAdminStatus(value)
+
+	return nil
 }
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-_net_if_connection_type
 // NET_IF_CONNECTION_TYPE defined in ifdef.h
 type NetIfConnectionType uint32
@@ -18,17 +16,36 @@ const (
 	NET_IF_CONNECTION_MAXIMUM   NetIfConnectionType = 4
 )
 
+var netIfConnectionTypeTable = &enumTable{
+	typeName:    "NetIfConnectionType",
+	unknown:     "NetIfConnectionType_UNKNOWN",
+	shortPrefix: "NET_IF_CONNECTION_",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NET_IF_CONNECTION_DEDICATED), "NET_IF_CONNECTION_DEDICATED"},
+		{int64(NET_IF_CONNECTION_PASSIVE), "NET_IF_CONNECTION_PASSIVE"},
+		{int64(NET_IF_CONNECTION_DEMAND), "NET_IF_CONNECTION_DEMAND"},
+		{int64(NET_IF_CONNECTION_MAXIMUM), "NET_IF_CONNECTION_MAXIMUM"},
+	},
+}
+
 func (t NetIfConnectionType) String() string {
-	switch t {
-	case NET_IF_CONNECTION_DEDICATED:
-		return "NET_IF_CONNECTION_DEDICATED"
-	case NET_IF_CONNECTION_PASSIVE:
-		return "NET_IF_CONNECTION_PASSIVE"
-	case NET_IF_CONNECTION_DEMAND:
-		return "NET_IF_CONNECTION_DEMAND"
-	case NET_IF_CONNECTION_MAXIMUM:
-		return "NET_IF_CONNECTION_MAXIMUM"
-	default:
-		return fmt.Sprintf("NetIfConnectionType_UNKNOWN(%d)", t)
+	return netIfConnectionTypeTable.format(int64(t))
+}
+
+func (t NetIfConnectionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *NetIfConnectionType) UnmarshalText(text []byte) error {
+
+	value, err := netIfConnectionTypeTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*t = NetIfConnectionType(value)
+
+	return nil
 }
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // NET_IF_DIRECTION_TYPE defined in ifdef.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-net_if_direction_type)
 type NetIfDirectionType uint32
@@ -18,17 +16,36 @@ const (
 	NET_IF_DIRECTION_MAXIMUM     NetIfDirectionType = 3
 )
 
+var netIfDirectionTypeTable = &enumTable{
+	typeName:    "NetIfDirectionType",
+	unknown:     "NetIfDirectionType_UNKNOWN",
+	shortPrefix: "NET_IF_DIRECTION_",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NET_IF_DIRECTION_SENDRECEIVE), "NET_IF_DIRECTION_SENDRECEIVE"},
+		{int64(NET_IF_DIRECTION_SENDONLY), "NET_IF_DIRECTION_SENDONLY"},
+		{int64(NET_IF_DIRECTION_RECEIVEONLY), "NET_IF_DIRECTION_RECEIVEONLY"},
+		{int64(NET_IF_DIRECTION_MAXIMUM), "NET_IF_DIRECTION_MAXIMUM"},
+	},
+}
+
 func (nidt NetIfDirectionType) String() string {
-	switch nidt {
-	case NET_IF_DIRECTION_SENDRECEIVE:
-		return "NET_IF_DIRECTION_SENDRECEIVE"
-	case NET_IF_DIRECTION_SENDONLY:
-		return "NET_IF_DIRECTION_SENDONLY"
-	case NET_IF_DIRECTION_RECEIVEONLY:
-		return "NET_IF_DIRECTION_RECEIVEONLY"
-	case NET_IF_DIRECTION_MAXIMUM:
-		return "NET_IF_DIRECTION_MAXIMUM"
-	default:
-		return fmt.Sprintf("NetIfDirectionType_UNKNOWN(%d)", nidt)
+	return netIfDirectionTypeTable.format(int64(nidt))
+}
+
+func (nidt NetIfDirectionType) MarshalText() ([]byte, error) {
+	return []byte(nidt.String()), nil
+}
+
+func (nidt *NetIfDirectionType) UnmarshalText(text []byte) error {
+
+	value, err := netIfDirectionTypeTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*nidt = NetIfDirectionType(value)
+
+	return nil
 }
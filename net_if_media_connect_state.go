@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // NET_IF_MEDIA_CONNECT_STATE defined in ifdef.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-_net_if_media_connect_state)
 type NetIfMediaConnectState uint32
@@ -17,15 +15,35 @@ const (
 	MediaConnectStateDisconnected NetIfMediaConnectState = 2
 )
 
+var netIfMediaConnectStateTable = &enumTable{
+	typeName:    "NetIfMediaConnectState",
+	unknown:     "NetIfMediaConnectState_UNKNOWN",
+	shortPrefix: "MediaConnectState",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(MediaConnectStateUnknown), "MediaConnectStateUnknown"},
+		{int64(MediaConnectStateConnected), "MediaConnectStateConnected"},
+		{int64(MediaConnectStateDisconnected), "MediaConnectStateDisconnected"},
+	},
+}
+
 func (nimcs NetIfMediaConnectState) String() string {
-	switch nimcs {
-	case MediaConnectStateUnknown:
-		return "MediaConnectStateUnknown"
-	case MediaConnectStateConnected:
-		return "MediaConnectStateConnected"
-	case MediaConnectStateDisconnected:
-		return "MediaConnectStateDisconnected"
-	default:
-		return fmt.Sprintf("NetIfMediaConnectState_UNKNOWN(%d)", nimcs)
+	return netIfMediaConnectStateTable.format(int64(nimcs))
+}
+
+func (nimcs NetIfMediaConnectState) MarshalText() ([]byte, error) {
+	return []byte(nimcs.String()), nil
+}
+
+func (nimcs *NetIfMediaConnectState) UnmarshalText(text []byte) error {
+
+	value, err := netIfMediaConnectStateTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*nimcs = NetIfMediaConnectState(value)
+
+	return nil
 }
@@ -0,0 +1,382 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// Verb of a netsh interface command.
+type NetshVerb string
+
+const (
+	NetshSet    NetshVerb = "set"
+	NetshAdd    NetshVerb = "add"
+	NetshDelete NetshVerb = "delete"
+)
+
+// Object of a netsh interface command.
+type NetshObject string
+
+const (
+	NetshDnsServers   NetshObject = "dnsservers"
+	NetshWinsServers  NetshObject = "winsservers"
+	NetshSubinterface NetshObject = "subinterface"
+	NetshAddress      NetshObject = "address"
+)
+
+// Named parameter of a netsh command, i.e. "mtu=1420".
+type NetshParameter struct {
+	Name  string
+	Value string
+}
+
+// Command of "netsh interface ipv4" or "netsh interface ipv6" context. Commands are typically created by the
+// NewNetsh...() functions, and turned into netsh input lines by Format().
+type NetshCommand struct {
+	Family     AddressFamily
+	Verb       NetshVerb
+	Object     NetshObject
+	Parameters []NetshParameter
+}
+
+// Checks that the command is one of the supported ones, and that its parameters can be formatted.
+func (c *NetshCommand) Validate() error {
+
+	if c.Family != AF_INET && c.Family != AF_INET6 {
+		return fmt.Errorf("NetshCommand.Validate() - family %s isn't supported", c.Family.String())
+	}
+
+	switch c.Verb {
+	case NetshSet, NetshAdd, NetshDelete:
+	default:
+		return fmt.Errorf("NetshCommand.Validate() - verb '%s' isn't supported", c.Verb)
+	}
+
+	switch c.Object {
+	case NetshDnsServers, NetshAddress:
+	case NetshWinsServers:
+		if c.Family != AF_INET {
+			return fmt.Errorf("NetshCommand.Validate() - %s are only supported for %s", c.Object,
+				AF_INET.String())
+		}
+	case NetshSubinterface:
+		if c.Verb != NetshSet {
+			return fmt.Errorf("NetshCommand.Validate() - %s only supports verb '%s'", c.Object, NetshSet)
+		}
+	default:
+		return fmt.Errorf("NetshCommand.Validate() - object '%s' isn't supported", c.Object)
+	}
+
+	for _, parameter := range c.Parameters {
+
+		if parameter.Name == "" || strings.IndexFunc(parameter.Name, func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		}) >= 0 {
+			return fmt.Errorf("NetshCommand.Validate() - invalid parameter name '%s'", parameter.Name)
+		}
+
+		// netsh has no way of escaping quotes, and a line break would end the command.
+		if strings.ContainsAny(parameter.Value, "\"\r\n\x00") {
+			return fmt.Errorf("NetshCommand.Validate() - value of parameter %s contains a character which can't be "+
+				"escaped: %q", parameter.Name, parameter.Value)
+		}
+	}
+
+	return nil
+}
+
+// Returns the command as a line of netsh input (without a line break), i.e.
+// "interface ipv4 set subinterface interface=12 mtu=1420 store=persistent". Values which are empty or contain
+// whitespace or '=' are quoted.
+func (c *NetshCommand) Format() (string, error) {
+
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	context := "ipv4"
+
+	if c.Family == AF_INET6 {
+		context = "ipv6"
+	}
+
+	words := []string{"interface", context, string(c.Verb), string(c.Object)}
+
+	for _, parameter := range c.Parameters {
+		words = append(words, parameter.Name+"="+quoteNetshValue(parameter.Value))
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// Returns the command if it's valid, or the validation error.
+func (c *NetshCommand) validated() (*NetshCommand, error) {
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *NetshCommand) String() string {
+
+	line, err := c.Format()
+
+	if err != nil {
+		return fmt.Sprintf("<invalid netsh command: %v>", err)
+	}
+
+	return line
+}
+
+func quoteNetshValue(value string) string {
+
+	if value == "" || strings.ContainsAny(value, " \t=") {
+		return `"` + value + `"`
+	}
+
+	return value
+}
+
+// Returns the interface's index for 'family', which netsh accepts instead of interface name.
+func netshInterfaceIndex(ifc *Interface, family AddressFamily) string {
+
+	if family == AF_INET6 {
+		return strconv.FormatUint(uint64(ifc.Ipv6IfIndex), 10)
+	}
+
+	return strconv.FormatUint(uint64(ifc.Index), 10)
+}
+
+// Returns address family of 'ip', with IPv4 address converted to 4-byte form.
+func netshAddressFamily(ip net.IP) (AddressFamily, net.IP, error) {
+
+	if v4 := ip.To4(); v4 != nil {
+		return AF_INET, v4, nil
+	}
+
+	if v6 := ip.To16(); v6 != nil {
+		return AF_INET6, v6, nil
+	}
+
+	return AF_UNSPEC, nil, fmt.Errorf("netshAddressFamily() - invalid IP address %v", ip)
+}
+
+// Returns command setting static DNS server of the interface for 'family' to 'dns', removing the others. Nil 'dns'
+// removes all the static DNS servers.
+func NewNetshSetDnsServers(ifc *Interface, family AddressFamily, dns net.IP) (*NetshCommand, error) {
+	return newNetshSetServers(NetshDnsServers, ifc, family, dns,
+		NetshParameter{"validate", "no"}, NetshParameter{"register", "both"})
+}
+
+// Returns command adding 'dns' to static DNS servers of the interface.
+func NewNetshAddDnsServer(ifc *Interface, dns net.IP) (*NetshCommand, error) {
+	return newNetshAddServer(NetshDnsServers, ifc, dns, NetshParameter{"validate", "no"})
+}
+
+// Returns command removing 'dns' from static DNS servers of the interface for 'family'. Nil 'dns' removes all of
+// them.
+func NewNetshDeleteDnsServer(ifc *Interface, family AddressFamily, dns net.IP) (*NetshCommand, error) {
+	return newNetshDeleteServer(NetshDnsServers, ifc, family, dns, NetshParameter{"validate", "no"})
+}
+
+// Returns command setting static WINS server of the interface to 'wins', removing the others. Nil 'wins' removes all
+// the static WINS servers. WINS is IPv4 only.
+func NewNetshSetWinsServers(ifc *Interface, wins net.IP) (*NetshCommand, error) {
+	return newNetshSetServers(NetshWinsServers, ifc, AF_INET, wins)
+}
+
+// Returns command adding IPv4 address 'wins' to static WINS servers of the interface.
+func NewNetshAddWinsServer(ifc *Interface, wins net.IP) (*NetshCommand, error) {
+	return newNetshAddServer(NetshWinsServers, ifc, wins)
+}
+
+// Returns command removing 'wins' from static WINS servers of the interface. Nil 'wins' removes all of them.
+func NewNetshDeleteWinsServer(ifc *Interface, wins net.IP) (*NetshCommand, error) {
+	return newNetshDeleteServer(NetshWinsServers, ifc, AF_INET, wins)
+}
+
+func newNetshSetServers(object NetshObject, ifc *Interface, family AddressFamily, server net.IP,
+	extra ...NetshParameter) (*NetshCommand, error) {
+
+	address := "none"
+
+	if server != nil {
+
+		serverFamily, ip, err := netshAddressFamily(server)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if serverFamily != family {
+			return nil, fmt.Errorf("NewNetshSet...() - %s isn't an %s address", ip.String(), family.String())
+		}
+
+		address = ip.String()
+	}
+
+	cmd := &NetshCommand{Family: family, Verb: NetshSet, Object: object, Parameters: []NetshParameter{
+		{"name", netshInterfaceIndex(ifc, family)},
+		{"source", "static"},
+		{"address", address},
+	}}
+
+	cmd.Parameters = append(cmd.Parameters, extra...)
+
+	return cmd.validated()
+}
+
+func newNetshAddServer(object NetshObject, ifc *Interface, server net.IP,
+	extra ...NetshParameter) (*NetshCommand, error) {
+
+	family, ip, err := netshAddressFamily(server)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &NetshCommand{Family: family, Verb: NetshAdd, Object: object, Parameters: []NetshParameter{
+		{"name", netshInterfaceIndex(ifc, family)},
+		{"address", ip.String()},
+	}}
+
+	cmd.Parameters = append(cmd.Parameters, extra...)
+
+	return cmd.validated()
+}
+
+func newNetshDeleteServer(object NetshObject, ifc *Interface, family AddressFamily, server net.IP,
+	extra ...NetshParameter) (*NetshCommand, error) {
+
+	address := "all"
+
+	if server != nil {
+
+		serverFamily, ip, err := netshAddressFamily(server)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if serverFamily != family {
+			return nil, fmt.Errorf("NewNetshDelete...() - %s isn't an %s address", ip.String(), family.String())
+		}
+
+		address = ip.String()
+	}
+
+	cmd := &NetshCommand{Family: family, Verb: NetshDelete, Object: object, Parameters: []NetshParameter{
+		{"name", netshInterfaceIndex(ifc, family)},
+		{"address", address},
+	}}
+
+	cmd.Parameters = append(cmd.Parameters, extra...)
+
+	return cmd.validated()
+}
+
+// Returns command persistently setting the interface's MTU for 'family'.
+func NewNetshSetSubinterfaceMtu(ifc *Interface, family AddressFamily, mtu uint32) (*NetshCommand, error) {
+
+	cmd := &NetshCommand{Family: family, Verb: NetshSet, Object: NetshSubinterface, Parameters: []NetshParameter{
+		{"interface", netshInterfaceIndex(ifc, family)},
+		{"mtu", strconv.FormatUint(uint64(mtu), 10)},
+		{"store", "persistent"},
+	}}
+
+	return cmd.validated()
+}
+
+// Returns command setting the interface's only static IPv4 address to 'address', or command changing the interface's
+// existing IPv6 address 'address' to a static one. The family is that of 'address'.
+func NewNetshSetAddress(ifc *Interface, address *net.IPNet) (*NetshCommand, error) {
+	return newNetshAddress(NetshSet, ifc, address)
+}
+
+// Returns command adding 'address' to the interface.
+func NewNetshAddAddress(ifc *Interface, address *net.IPNet) (*NetshCommand, error) {
+	return newNetshAddress(NetshAdd, ifc, address)
+}
+
+// Returns command removing 'address' from the interface.
+func NewNetshDeleteAddress(ifc *Interface, address net.IP) (*NetshCommand, error) {
+	return newNetshAddress(NetshDelete, ifc, &net.IPNet{IP: address})
+}
+
+func newNetshAddress(verb NetshVerb, ifc *Interface, address *net.IPNet) (*NetshCommand, error) {
+
+	if address == nil {
+		return nil, fmt.Errorf("newNetshAddress() - address is nil")
+	}
+
+	family, ip, err := netshAddressFamily(address.IP)
+
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := &NetshCommand{Family: family, Verb: verb, Object: NetshAddress}
+
+	if family == AF_INET {
+
+		cmd.Parameters = []NetshParameter{{"name", netshInterfaceIndex(ifc, family)}}
+
+		if verb == NetshSet {
+			cmd.Parameters = append(cmd.Parameters, NetshParameter{"source", "static"})
+		}
+
+		cmd.Parameters = append(cmd.Parameters, NetshParameter{"address", ip.String()})
+
+		if verb != NetshDelete {
+
+			mask := address.Mask
+
+			if len(mask) == net.IPv6len {
+				mask = mask[12:]
+			}
+
+			if len(mask) != net.IPv4len {
+				return nil, fmt.Errorf("newNetshAddress() - %s has no IPv4 mask", ip.String())
+			}
+
+			cmd.Parameters = append(cmd.Parameters, NetshParameter{"mask", net.IP(mask).String()})
+		}
+	} else {
+
+		value := ip.String()
+
+		// IPv6 addresses are added with prefix length; 'set' and 'delete' take just the address.
+		if verb == NetshAdd {
+
+			ones, bits := address.Mask.Size()
+
+			if bits != 8*net.IPv6len {
+				return nil, fmt.Errorf("newNetshAddress() - %s has no IPv6 mask", ip.String())
+			}
+
+			value = fmt.Sprintf("%s/%d", value, ones)
+		}
+
+		cmd.Parameters = []NetshParameter{{"interface", netshInterfaceIndex(ifc, family)}, {"address", value}}
+
+		if verb == NetshSet {
+			cmd.Parameters = append(cmd.Parameters, NetshParameter{"type", "unicast"})
+		}
+	}
+
+	// Without 'store', deleted address is removed both from the active and the persistent configuration.
+	if verb != NetshDelete {
+		cmd.Parameters = append(cmd.Parameters, NetshParameter{"store", "persistent"})
+	}
+
+	return cmd.validated()
+}
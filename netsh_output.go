@@ -0,0 +1,180 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Kind of an error reported by netsh, see NetshError.
+type NetshErrorKind uint32
+
+const (
+	// Message isn't one of the known ones (which includes all the messages of localized Windows).
+	NetshErrorUnknown NetshErrorKind = 0
+	// The interface, address or server doesn't exist.
+	NetshErrorElementNotFound NetshErrorKind = 1
+	// Typically because netsh isn't run elevated.
+	NetshErrorAccessDenied NetshErrorKind = 2
+	// Invalid or missing parameter, or unknown command.
+	NetshErrorInvalidParameter NetshErrorKind = 3
+	// The address or server being added exists already.
+	NetshErrorObjectAlreadyExists NetshErrorKind = 4
+)
+
+var netshErrorKindTable = &enumTable{
+	typeName:    "NetshErrorKind",
+	unknown:     "NetshErrorKind_UNKNOWN",
+	shortPrefix: "NetshError",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NetshErrorUnknown), "NetshErrorUnknown"},
+		{int64(NetshErrorElementNotFound), "NetshErrorElementNotFound"},
+		{int64(NetshErrorAccessDenied), "NetshErrorAccessDenied"},
+		{int64(NetshErrorInvalidParameter), "NetshErrorInvalidParameter"},
+		{int64(NetshErrorObjectAlreadyExists), "NetshErrorObjectAlreadyExists"},
+	},
+}
+
+func (k NetshErrorKind) String() string {
+	return netshErrorKindTable.format(int64(k))
+}
+
+func (k NetshErrorKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *NetshErrorKind) UnmarshalText(text []byte) error {
+
+	value, err := netshErrorKindTable.parse(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*k = NetshErrorKind(value)
+
+	return nil
+}
+
+// Error reported by netsh in its output.
+type NetshError struct {
+	Kind NetshErrorKind
+
+	// Input line which caused the error, or empty string if the output couldn't be attributed to a line.
+	Command string
+
+	// The output of the command, without benign messages, with lines separated by "\n".
+	Message string
+}
+
+func (e *NetshError) Error() string {
+
+	if e.Command == "" {
+		return fmt.Sprintf("netsh failed (%s): %s", e.Kind.String(), e.Message)
+	}
+
+	return fmt.Sprintf("netsh command '%s' failed (%s): %s", e.Command, e.Kind.String(), e.Message)
+}
+
+// Lines of netsh output which aren't errors.
+var netshBenignMessages = []string{
+	"Ok.",
+	// Output by "set dnsservers ... address=none" when there were no servers to remove.
+	"There are no Domain Name Servers (DNS) configured on this computer.",
+	// Output by "set winsservers ... address=none" when there were no servers to remove.
+	"There are no WINS servers configured on this computer.",
+}
+
+// Lines of netsh output identifying error kinds.
+var netshErrorMessages = []struct {
+	message string
+	kind    NetshErrorKind
+}{
+	{"Element not found.", NetshErrorElementNotFound},
+	// Output for interface names or indices which don't exist.
+	{"The filename, directory name, or volume label syntax is incorrect.", NetshErrorElementNotFound},
+	{"The system cannot find the file specified.", NetshErrorElementNotFound},
+	{"Access is denied.", NetshErrorAccessDenied},
+	{"The requested operation requires elevation (Run as administrator).", NetshErrorAccessDenied},
+	{"The parameter is incorrect.", NetshErrorInvalidParameter},
+	{"One or more essential parameters were not entered.", NetshErrorInvalidParameter},
+	{"The following command was not found:", NetshErrorInvalidParameter},
+	{"The object already exists.", NetshErrorObjectAlreadyExists},
+}
+
+// Prompt netsh writes before reading each input line.
+const netshPrompt = "netsh>"
+
+// Parses output of netsh run with input lines 'commands'. Since netsh writes its prompt before reading each line, the
+// output following the n-th prompt is attributed to the n-th line. Returns error for each command whose output has
+// anything but benign messages, or nil if there are none.
+func ParseNetshOutput(output string, commands []string) []*NetshError {
+
+	output = strings.Replace(output, "\r\n", "\n", -1)
+
+	var errs []*NetshError
+
+	for i, segment := range strings.Split(output, netshPrompt) {
+
+		lines := make([]string, 0)
+		kind := NetshErrorUnknown
+
+		for _, line := range strings.Split(segment, "\n") {
+
+			line = strings.TrimSpace(line)
+
+			if line == "" || isNetshBenignMessage(line) {
+				continue
+			}
+
+			// Usage text which follows some of the errors doesn't change the kind.
+			if lineKind := netshErrorKind(line); kind == NetshErrorUnknown {
+				kind = lineKind
+			}
+
+			lines = append(lines, line)
+		}
+
+		if len(lines) == 0 {
+			continue
+		}
+
+		command := ""
+
+		// The segment before the first prompt has no command.
+		if i > 0 && i <= len(commands) {
+			command = commands[i-1]
+		}
+
+		errs = append(errs, &NetshError{Kind: kind, Command: command, Message: strings.Join(lines, "\n")})
+	}
+
+	return errs
+}
+
+func isNetshBenignMessage(line string) bool {
+
+	for _, message := range netshBenignMessages {
+		if line == message {
+			return true
+		}
+	}
+
+	return false
+}
+
+func netshErrorKind(line string) NetshErrorKind {
+
+	for _, entry := range netshErrorMessages {
+		if strings.HasPrefix(line, entry.message) {
+			return entry.kind
+		}
+	}
+
+	return NetshErrorUnknown
+}
@@ -0,0 +1,189 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNetshCommandFormat(t *testing.T) {
+
+	ifc := &Interface{Index: 12, Ipv6IfIndex: 13}
+
+	_, ipv4net, _ := net.ParseCIDR("10.0.0.2/24")
+	ipv4net.IP = net.ParseIP("10.0.0.2")
+	_, ipv6net, _ := net.ParseCIDR("fd00::2/64")
+	ipv6net.IP = net.ParseIP("fd00::2")
+
+	builders := []func() (*NetshCommand, error){
+		func() (*NetshCommand, error) { return NewNetshSetDnsServers(ifc, AF_INET, nil) },
+		func() (*NetshCommand, error) { return NewNetshSetDnsServers(ifc, AF_INET6, net.ParseIP("fd00::53")) },
+		func() (*NetshCommand, error) { return NewNetshAddDnsServer(ifc, net.ParseIP("1.1.1.1")) },
+		func() (*NetshCommand, error) { return NewNetshAddDnsServer(ifc, net.ParseIP("2606:4700:4700::1111")) },
+		func() (*NetshCommand, error) { return NewNetshDeleteDnsServer(ifc, AF_INET, net.ParseIP("1.1.1.1")) },
+		func() (*NetshCommand, error) { return NewNetshDeleteDnsServer(ifc, AF_INET6, nil) },
+		func() (*NetshCommand, error) { return NewNetshSetWinsServers(ifc, net.ParseIP("10.0.0.1")) },
+		func() (*NetshCommand, error) { return NewNetshAddWinsServer(ifc, net.ParseIP("10.0.0.1")) },
+		func() (*NetshCommand, error) { return NewNetshDeleteWinsServer(ifc, nil) },
+		func() (*NetshCommand, error) { return NewNetshSetSubinterfaceMtu(ifc, AF_INET, 1420) },
+		func() (*NetshCommand, error) { return NewNetshSetSubinterfaceMtu(ifc, AF_INET6, 1420) },
+		func() (*NetshCommand, error) { return NewNetshSetAddress(ifc, ipv4net) },
+		func() (*NetshCommand, error) { return NewNetshSetAddress(ifc, ipv6net) },
+		func() (*NetshCommand, error) { return NewNetshAddAddress(ifc, ipv4net) },
+		func() (*NetshCommand, error) { return NewNetshAddAddress(ifc, ipv6net) },
+		func() (*NetshCommand, error) { return NewNetshDeleteAddress(ifc, ipv4net.IP) },
+		func() (*NetshCommand, error) { return NewNetshDeleteAddress(ifc, ipv6net.IP) },
+		func() (*NetshCommand, error) {
+			return &NetshCommand{Family: AF_INET, Verb: NetshSet, Object: NetshSubinterface,
+				Parameters: []NetshParameter{{"interface", "Local Area Connection"}, {"mtu", "1500"}, {"store", ""}}}, nil
+		},
+	}
+
+	var buffer bytes.Buffer
+
+	for i, builder := range builders {
+
+		cmd, err := builder()
+
+		if err != nil {
+			t.Errorf("Builder %d returned an error: %v", i, err)
+			continue
+		}
+
+		line, err := cmd.Format()
+
+		if err != nil {
+			t.Errorf("NetshCommand.Format() returned an error for builder %d: %v", i, err)
+			continue
+		}
+
+		buffer.WriteString(line + "\n")
+	}
+
+	checkGolden(t, "netsh_commands.txt", buffer.Bytes())
+}
+
+func TestNetshCommandValidate(t *testing.T) {
+
+	ifc := &Interface{Index: 12, Ipv6IfIndex: 13}
+
+	if _, err := NewNetshAddWinsServer(ifc, net.ParseIP("fd00::1")); err == nil {
+		t.Errorf("NewNetshAddWinsServer() accepted an IPv6 address.")
+	}
+
+	if _, err := NewNetshSetDnsServers(ifc, AF_INET, net.ParseIP("fd00::53")); err == nil {
+		t.Errorf("NewNetshSetDnsServers() accepted an IPv6 address for %s.", AF_INET.String())
+	}
+
+	if _, err := NewNetshAddDnsServer(ifc, nil); err == nil {
+		t.Errorf("NewNetshAddDnsServer() accepted nil address.")
+	}
+
+	if _, err := NewNetshAddAddress(ifc, &net.IPNet{IP: net.ParseIP("10.0.0.2")}); err == nil {
+		t.Errorf("NewNetshAddAddress() accepted an address without mask.")
+	}
+
+	invalid := []*NetshCommand{
+		{Family: AF_UNSPEC, Verb: NetshSet, Object: NetshDnsServers},
+		{Family: AF_INET, Verb: "show", Object: NetshDnsServers},
+		{Family: AF_INET, Verb: NetshAdd, Object: NetshSubinterface},
+		{Family: AF_INET6, Verb: NetshSet, Object: NetshWinsServers},
+		{Family: AF_INET, Verb: NetshSet, Object: "route"},
+		{Family: AF_INET, Verb: NetshSet, Object: NetshDnsServers, Parameters: []NetshParameter{{"Name", "12"}}},
+		{Family: AF_INET, Verb: NetshSet, Object: NetshDnsServers, Parameters: []NetshParameter{{"name", `a"b`}}},
+		{Family: AF_INET, Verb: NetshSet, Object: NetshDnsServers,
+			Parameters: []NetshParameter{{"name", "12\r\nexit"}}},
+	}
+
+	for _, cmd := range invalid {
+		if line, err := cmd.Format(); err == nil {
+			t.Errorf("NetshCommand.Format() accepted invalid command: %s", line)
+		}
+	}
+}
+
+func TestParseNetshOutput(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		commands []string
+	}{
+		{"success", []string{
+			"interface ipv4 set dnsservers name=12 source=static address=none validate=no register=both",
+			"interface ipv6 set dnsservers name=13 source=static address=none validate=no register=both",
+			"interface ipv4 add dnsservers name=12 address=1.1.1.1 validate=no",
+			"interface ipv6 add dnsservers name=13 address=2606:4700:4700::1111 validate=no",
+		}},
+		{"access_denied", []string{
+			"interface ipv4 set subinterface interface=12 mtu=1420 store=persistent",
+			"interface ipv6 set subinterface interface=13 mtu=1420 store=persistent",
+		}},
+		{"element_not_found", []string{
+			"interface ipv4 delete dnsservers name=12 address=9.9.9.9 validate=no",
+			"interface ipv4 add dnsservers name=12 address=1.1.1.1 validate=no",
+			"interface ipv4 add dnsservers name=99 address=1.1.1.1 validate=no",
+		}},
+		{"invalid_parameter", []string{
+			"interface ipv4 add dnsservers name=12",
+			"interface ipv6 add address interface=13 address=fd00::2/129 store=persistent",
+		}},
+		{"already_exists", []string{
+			"interface ipv4 add address name=12 address=10.0.0.2 mask=255.255.255.0 store=persistent",
+		}},
+		{"localized", []string{
+			"interface ipv4 delete dnsservers name=12 address=9.9.9.9 validate=no",
+		}},
+	}
+
+	for _, test := range tests {
+
+		output, err := ioutil.ReadFile(filepath.Join("testdata", "netsh", test.name+".txt"))
+
+		if err != nil {
+			t.Fatalf("ioutil.ReadFile() returned an error: %v", err)
+		}
+
+		var buffer bytes.Buffer
+
+		errs := ParseNetshOutput(string(output), test.commands)
+
+		for _, err := range errs {
+			fmt.Fprintf(&buffer, "Kind: %s\nCommand: %s\nMessage:\n%s\n\n", err.Kind.String(), err.Command,
+				toIndentedText(err.Message, "    "))
+		}
+
+		if len(errs) == 0 {
+			buffer.WriteString("no errors\n")
+		}
+
+		checkGolden(t, filepath.Join("netsh", test.name+".golden"), buffer.Bytes())
+	}
+}
+
+func TestNetshError(t *testing.T) {
+
+	err := &NetshError{Kind: NetshErrorAccessDenied, Command: "interface ipv4 set subinterface interface=12 mtu=1420",
+		Message: "Access is denied."}
+
+	if !strings.Contains(err.Error(), err.Command) || !strings.Contains(err.Error(), "NetshErrorAccessDenied") {
+		t.Errorf("NetshError.Error() returned '%s'.", err.Error())
+	}
+
+	var kind NetshErrorKind
+
+	if e := kind.UnmarshalText([]byte("element-not-found")); e != nil || kind != NetshErrorElementNotFound {
+		t.Errorf("NetshErrorKind.UnmarshalText() returned %s, %v.", kind.String(), e)
+	}
+}
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/nldef/ne-nldef-nl_dad_state
 // NL_DAD_STATE defined in nldef.h
 type NlDadState uint32
@@ -19,21 +17,39 @@ const (
 	IpDadStatePreferred  NlDadState = 4
 )
 
+var nlDadStateTable = &enumTable{
+	typeName:    "NlDadState",
+	unknown:     "NlDadState_UNKNOWN",
+	shortPrefix: "IpDadState",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(IpDadStateInvalid), "IpDadStateInvalid"},
+		{int64(IpDadStateTentative), "IpDadStateTentative"},
+		{int64(IpDadStateDuplicate), "IpDadStateDuplicate"},
+		{int64(IpDadStateDeprecated), "IpDadStateDeprecated"},
+		{int64(IpDadStatePreferred), "IpDadStatePreferred"},
+	},
+}
+
 func (s NlDadState) String() string {
-	switch s {
-	case IpDadStateInvalid:
-		return "IpDadStateInvalid"
-	case IpDadStateTentative:
-		return "IpDadStateTentative"
-	case IpDadStateDuplicate:
-		return "IpDadStateDuplicate"
-	case IpDadStateDeprecated:
-		return "IpDadStateDeprecated"
-	case IpDadStatePreferred:
-		return "IpDadStatePreferred"
-	default:
-		return fmt.Sprintf("NlDadState_UNKNOWN(%d)", s)
+	return nlDadStateTable.format(int64(s))
+}
+
+func (s NlDadState) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *NlDadState) UnmarshalText(text []byte) error {
+
+	value, err := nlDadStateTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*s = NlDadState(value)
+
+	return nil
 }
 
 // IP_DAD_STATE defined in iptypes.h
@@ -42,3 +58,11 @@ type IpDadState NlDadState
 func (s IpDadState) String() string {
 	return NlDadState(s).String()
 }
+
+func (s IpDadState) MarshalText() ([]byte, error) {
+	return NlDadState(s).MarshalText()
+}
+
+func (s *IpDadState) UnmarshalText(text []byte) error {
+	return (*NlDadState)(s).UnmarshalText(text)
+}
@@ -8,14 +8,14 @@ package winipcfg
 import "fmt"
 
 type NlInterfaceOffloadRodFlags struct {
-	NlChecksumSupported         bool
-	NlOptionsSupported          bool
-	TlDatagramChecksumSupported bool
-	TlStreamChecksumSupported   bool
-	TlStreamOptionsSupported    bool
-	FastPathCompatible          bool
-	TlLargeSendOffloadSupported bool
-	TlGiantSendOffloadSupported bool
+	NlChecksumSupported         bool `json:"nlChecksumSupported"`
+	NlOptionsSupported          bool `json:"nlOptionsSupported"`
+	TlDatagramChecksumSupported bool `json:"tlDatagramChecksumSupported"`
+	TlStreamChecksumSupported   bool `json:"tlStreamChecksumSupported"`
+	TlStreamOptionsSupported    bool `json:"tlStreamOptionsSupported"`
+	FastPathCompatible          bool `json:"fastPathCompatible"`
+	TlLargeSendOffloadSupported bool `json:"tlLargeSendOffloadSupported"`
+	TlGiantSendOffloadSupported bool `json:"tlGiantSendOffloadSupported"`
 }
 
 func (iorf *NlInterfaceOffloadRodFlags) toWtNlInterfaceOffloadRod() wtNlInterfaceOffloadRodByte {
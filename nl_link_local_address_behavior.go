@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/nldef/ne-nldef-_nl_link_local_address_behavior
 // NL_LINK_LOCAL_ADDRESS_BEHAVIOR defined in nldef.h
 type NlLinkLocalAddressBehavior int32
@@ -26,17 +24,36 @@ const (
 	LinkLocalUnchanged NlLinkLocalAddressBehavior = -1
 )
 
+var nlLinkLocalAddressBehaviorTable = &enumTable{
+	typeName:    "NlLinkLocalAddressBehavior",
+	unknown:     "NlLinkLocalAddressBehavior_UNKNOWN",
+	shortPrefix: "LinkLocal",
+	kind:        enumInt32,
+	entries: []enumEntry{
+		{int64(LinkLocalAlwaysOff), "LinkLocalAlwaysOff"},
+		{int64(LinkLocalDelayed), "LinkLocalDelayed"},
+		{int64(LinkLocalAlwaysOn), "LinkLocalAlwaysOn"},
+		{int64(LinkLocalUnchanged), "LinkLocalUnchanged"},
+	},
+}
+
 func (llab NlLinkLocalAddressBehavior) String() string {
-	switch llab {
-	case LinkLocalAlwaysOff:
-		return "LinkLocalAlwaysOff"
-	case LinkLocalDelayed:
-		return "LinkLocalDelayed"
-	case LinkLocalAlwaysOn:
-		return "LinkLocalAlwaysOn"
-	case LinkLocalUnchanged:
-		return "LinkLocalUnchanged"
-	default:
-		return fmt.Sprintf("NlLinkLocalAddressBehavior_UNKNOWN(%d)", llab)
+	return nlLinkLocalAddressBehaviorTable.format(int64(llab))
+}
+
+func (llab NlLinkLocalAddressBehavior) MarshalText() ([]byte, error) {
+	return []byte(llab.String()), nil
+}
+
+func (llab *NlLinkLocalAddressBehavior) UnmarshalText(text []byte) error {
+
+	value, err := nlLinkLocalAddressBehaviorTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*llab = NlLinkLocalAddressBehavior(value)
+
+	return nil
 }
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/nldef/ne-nldef-nl_prefix_origin
 // NL_PREFIX_ORIGIN defined in nldef.h
 type NlPrefixOrigin uint32
@@ -20,23 +18,40 @@ const (
 	IpPrefixOriginUnchanged           NlPrefixOrigin = 1 << 4
 )
 
+var nlPrefixOriginTable = &enumTable{
+	typeName:    "NlPrefixOrigin",
+	unknown:     "NlPrefixOrigin_UNKNOWN",
+	shortPrefix: "IpPrefixOrigin",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(IpPrefixOriginOther), "IpPrefixOriginOther"},
+		{int64(IpPrefixOriginManual), "IpPrefixOriginManual"},
+		{int64(IpPrefixOriginWellKnown), "IpPrefixOriginWellKnown"},
+		{int64(IpPrefixOriginDhcp), "IpPrefixOriginDhcp"},
+		{int64(IpPrefixOriginRouterAdvertisement), "IpPrefixOriginRouterAdvertisement"},
+		{int64(IpPrefixOriginUnchanged), "IpPrefixOriginUnchanged"},
+	},
+}
+
 func (o NlPrefixOrigin) String() string {
-	switch o {
-	case IpPrefixOriginOther:
-		return "IpPrefixOriginOther"
-	case IpPrefixOriginManual:
-		return "IpPrefixOriginManual"
-	case IpPrefixOriginWellKnown:
-		return "IpPrefixOriginWellKnown"
-	case IpPrefixOriginDhcp:
-		return "IpPrefixOriginDhcp"
-	case IpPrefixOriginRouterAdvertisement:
-		return "IpPrefixOriginRouterAdvertisement"
-	case IpPrefixOriginUnchanged:
-		return "IpPrefixOriginUnchanged"
-	default:
-		return fmt.Sprintf("NlPrefixOrigin_UNKNOWN(%d)", o)
+	return nlPrefixOriginTable.format(int64(o))
+}
+
+func (o NlPrefixOrigin) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *NlPrefixOrigin) UnmarshalText(text []byte) error {
+
+	value, err := nlPrefixOriginTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*o = NlPrefixOrigin(value)
+
+	return nil
 }
 
 // IP_PREFIX_ORIGIN defined in iptypes.h
@@ -45,3 +60,11 @@ type IpPrefixOrigin NlPrefixOrigin
 func (o IpPrefixOrigin) String() string {
 	return NlPrefixOrigin(o).String()
 }
+
+func (o IpPrefixOrigin) MarshalText() ([]byte, error) {
+	return NlPrefixOrigin(o).MarshalText()
+}
+
+func (o *IpPrefixOrigin) UnmarshalText(text []byte) error {
+	return (*NlPrefixOrigin)(o).UnmarshalText(text)
+}
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // NL_ROUTE_ORIGIN defined in nldef.h
 type NlRouteOrigin uint32
 
@@ -18,19 +16,37 @@ const (
 	Nlro6to4                NlRouteOrigin = 4
 )
 
+var nlRouteOriginTable = &enumTable{
+	typeName:    "NlRouteOrigin",
+	unknown:     "NlRouteOrigin_UNKNOWN",
+	shortPrefix: "Nlro",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(NlroManual), "NlroManual"},
+		{int64(NlroWellKnown), "NlroWellKnown"},
+		{int64(NlroDHCP), "NlroDHCP"},
+		{int64(NlroRouterAdvertisement), "NlroRouterAdvertisement"},
+		{int64(Nlro6to4), "Nlro6to4"},
+	},
+}
+
 func (o NlRouteOrigin) String() string {
-	switch o {
-	case NlroManual:
-		return "NlroManual"
-	case NlroWellKnown:
-		return "NlroWellKnown"
-	case NlroDHCP:
-		return "NlroDHCP"
-	case NlroRouterAdvertisement:
-		return "NlroRouterAdvertisement"
-	case Nlro6to4:
-		return "Nlro6to4"
-	default:
-		return fmt.Sprintf("NlRouteOrigin_UNKNOWN(%d)", o)
+	return nlRouteOriginTable.format(int64(o))
+}
+
+func (o NlRouteOrigin) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *NlRouteOrigin) UnmarshalText(text []byte) error {
+
+	value, err := nlRouteOriginTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*o = NlRouteOrigin(value)
+
+	return nil
 }
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/nldef/ne-nldef-nl_route_protocol
 // NL_ROUTE_PROTOCOL defined in nldef.h
 type NlRouteProtocol uint32
@@ -40,53 +38,59 @@ const (
 	NT_STATIC_NON_DOD NlRouteProtocol = 10007
 )
 
+var nlRouteProtocolTable = &enumTable{
+	typeName:    "NlRouteProtocol",
+	unknown:     "NlRouteProtocol_UNKNOWN",
+	shortPrefix: "RouteProtocol",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(RouteProtocolOther), "RouteProtocolOther"},
+		{int64(RouteProtocolLocal), "RouteProtocolLocal"},
+		{int64(RouteProtocolNetMgmt), "RouteProtocolNetMgmt"},
+		{int64(RouteProtocolIcmp), "RouteProtocolIcmp"},
+		{int64(RouteProtocolEgp), "RouteProtocolEgp"},
+		{int64(RouteProtocolGgp), "RouteProtocolGgp"},
+		{int64(RouteProtocolHello), "RouteProtocolHello"},
+		{int64(RouteProtocolRip), "RouteProtocolRip"},
+		{int64(RouteProtocolIsIs), "RouteProtocolIsIs"},
+		{int64(RouteProtocolEsIs), "RouteProtocolEsIs"},
+		{int64(RouteProtocolCisco), "RouteProtocolCisco"},
+		{int64(RouteProtocolBbn), "RouteProtocolBbn"},
+		{int64(RouteProtocolOspf), "RouteProtocolOspf"},
+		{int64(RouteProtocolBgp), "RouteProtocolBgp"},
+		{int64(RouteProtocolIdpr), "RouteProtocolIdpr"},
+		{int64(RouteProtocolEigrp), "RouteProtocolEigrp"},
+		{int64(RouteProtocolDvmrp), "RouteProtocolDvmrp"},
+		{int64(RouteProtocolRpl), "RouteProtocolRpl"},
+		{int64(RouteProtocolDhcp), "RouteProtocolDhcp"},
+		{int64(NT_AUTOSTATIC), "NT_AUTOSTATIC"},
+		{int64(NT_STATIC), "NT_STATIC"},
+		{int64(NT_STATIC_NON_DOD), "NT_STATIC_NON_DOD"},
+	},
+	aliases: map[string]int64{
+		"autostatic":   int64(NT_AUTOSTATIC),
+		"static":       int64(NT_STATIC),
+		"staticnondod": int64(NT_STATIC_NON_DOD),
+	},
+}
+
 func (protocol NlRouteProtocol) String() string {
-	switch protocol {
-	case RouteProtocolOther:
-		return "RouteProtocolOther"
-	case RouteProtocolLocal:
-		return "RouteProtocolLocal"
-	case RouteProtocolNetMgmt:
-		return "RouteProtocolNetMgmt"
-	case RouteProtocolIcmp:
-		return "RouteProtocolIcmp"
-	case RouteProtocolEgp:
-		return "RouteProtocolEgp"
-	case RouteProtocolGgp:
-		return "RouteProtocolGgp"
-	case RouteProtocolHello:
-		return "RouteProtocolHello"
-	case RouteProtocolRip:
-		return "RouteProtocolRip"
-	case RouteProtocolIsIs:
-		return "RouteProtocolIsIs"
-	case RouteProtocolEsIs:
-		return "RouteProtocolEsIs"
-	case RouteProtocolCisco:
-		return "RouteProtocolCisco"
-	case RouteProtocolBbn:
-		return "RouteProtocolBbn"
-	case RouteProtocolOspf:
-		return "RouteProtocolOspf"
-	case RouteProtocolBgp:
-		return "RouteProtocolBgp"
-	case RouteProtocolIdpr:
-		return "RouteProtocolIdpr"
-	case RouteProtocolEigrp:
-		return "RouteProtocolEigrp"
-	case RouteProtocolDvmrp:
-		return "RouteProtocolDvmrp"
-	case RouteProtocolRpl:
-		return "RouteProtocolRpl"
-	case RouteProtocolDhcp:
-		return "RouteProtocolDhcp"
-	case NT_AUTOSTATIC:
-		return "NT_AUTOSTATIC"
-	case NT_STATIC:
-		return "NT_STATIC"
-	case NT_STATIC_NON_DOD:
-		return "NT_STATIC_NON_DOD"
-	default:
-		return fmt.Sprintf("NlRouteProtocol_UNKNOWN(%d)", protocol)
+	return nlRouteProtocolTable.format(int64(protocol))
+}
+
+func (protocol NlRouteProtocol) MarshalText() ([]byte, error) {
+	return []byte(protocol.String()), nil
+}
+
+func (protocol *NlRouteProtocol) UnmarshalText(text []byte) error {
+
+	value, err := nlRouteProtocolTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*protocol = NlRouteProtocol(value)
+
+	return nil
 }
@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/nldef/ne-nldef-_nl_router_discovery_behavior
 // NL_ROUTER_DISCOVERY_BEHAVIOR defined in nldef.h
 type NlRouterDiscoveryBehavior int32
@@ -18,17 +16,36 @@ const (
 	RouterDiscoveryUnchanged NlRouterDiscoveryBehavior = -1
 )
 
+var nlRouterDiscoveryBehaviorTable = &enumTable{
+	typeName:    "NlRouterDiscoveryBehavior",
+	unknown:     "NlRouterDiscoveryBehavior_UNKNOWN",
+	shortPrefix: "RouterDiscovery",
+	kind:        enumInt32,
+	entries: []enumEntry{
+		{int64(RouterDiscoveryDisabled), "RouterDiscoveryDisabled"},
+		{int64(RouterDiscoveryEnabled), "RouterDiscoveryEnabled"},
+		{int64(RouterDiscoveryDhcp), "RouterDiscoveryDhcp"},
+		{int64(RouterDiscoveryUnchanged), "RouterDiscoveryUnchanged"},
+	},
+}
+
 func (rdb NlRouterDiscoveryBehavior) String() string {
-	switch rdb {
-	case RouterDiscoveryDisabled:
-		return "RouterDiscoveryDisabled"
-	case RouterDiscoveryEnabled:
-		return "RouterDiscoveryEnabled"
-	case RouterDiscoveryDhcp:
-		return "RouterDiscoveryDhcp"
-	case RouterDiscoveryUnchanged:
-		return "RouterDiscoveryUnchanged"
-	default:
-		return fmt.Sprintf("NlRouterDiscoveryBehavior_UNKNOWN(%d)", rdb)
+	return nlRouterDiscoveryBehaviorTable.format(int64(rdb))
+}
+
+func (rdb NlRouterDiscoveryBehavior) MarshalText() ([]byte, error) {
+	return []byte(rdb.String()), nil
+}
+
+func (rdb *NlRouterDiscoveryBehavior) UnmarshalText(text []byte) error {
+
+	value, err := nlRouterDiscoveryBehaviorTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*rdb = NlRouterDiscoveryBehavior(value)
+
+	return nil
 }
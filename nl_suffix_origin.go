@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/nldef/ne-nldef-nl_suffix_origin
 // NL_SUFFIX_ORIGIN defined in nldef.h
 type NlSuffixOrigin uint32
@@ -21,25 +19,41 @@ const (
 	IpSuffixOriginUnchanged        NlSuffixOrigin = 1 << 4
 )
 
+var nlSuffixOriginTable = &enumTable{
+	typeName:    "NlSuffixOrigin",
+	unknown:     "NlSuffixOrigin_UNKNOWN",
+	shortPrefix: "IpSuffixOrigin",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(IpSuffixOriginOther), "IpSuffixOriginOther"},
+		{int64(IpSuffixOriginManual), "IpSuffixOriginManual"},
+		{int64(IpSuffixOriginWellKnown), "IpSuffixOriginWellKnown"},
+		{int64(IpSuffixOriginDhcp), "IpSuffixOriginDhcp"},
+		{int64(IpSuffixOriginLinkLayerAddress), "IpSuffixOriginLinkLayerAddress"},
+		{int64(IpSuffixOriginRandom), "IpSuffixOriginRandom"},
+		{int64(IpSuffixOriginUnchanged), "IpSuffixOriginUnchanged"},
+	},
+}
+
 func (o NlSuffixOrigin) String() string {
-	switch o {
-	case IpSuffixOriginOther:
-		return "IpSuffixOriginOther"
-	case IpSuffixOriginManual:
-		return "IpSuffixOriginManual"
-	case IpSuffixOriginWellKnown:
-		return "IpSuffixOriginWellKnown"
-	case IpSuffixOriginDhcp:
-		return "IpSuffixOriginDhcp"
-	case IpSuffixOriginLinkLayerAddress:
-		return "IpSuffixOriginLinkLayerAddress"
-	case IpSuffixOriginRandom:
-		return "IpSuffixOriginRandom"
-	case IpSuffixOriginUnchanged:
-		return "IpSuffixOriginUnchanged"
-	default:
-		return fmt.Sprintf("NlSuffixOrigin_UNKNOWN(%d)", o)
+	return nlSuffixOriginTable.format(int64(o))
+}
+
+func (o NlSuffixOrigin) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+func (o *NlSuffixOrigin) UnmarshalText(text []byte) error {
+
+	value, err := nlSuffixOriginTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*o = NlSuffixOrigin(value)
+
+	return nil
 }
 
 // IP_SUFFIX_ORIGIN defined in iptypes.h
@@ -48,3 +62,11 @@ type IpSuffixOrigin NlSuffixOrigin
 func (o IpSuffixOrigin) String() string {
 	return NlSuffixOrigin(o).String()
 }
+
+func (o IpSuffixOrigin) MarshalText() ([]byte, error) {
+	return NlSuffixOrigin(o).MarshalText()
+}
+
+func (o *IpSuffixOrigin) UnmarshalText(text []byte) error {
+	return (*NlSuffixOrigin)(o).UnmarshalText(text)
+}
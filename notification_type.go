@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/ne-netioapi-_mib_notification_type
 // MIB_NOTIFICATION_TYPE defined in netioapi.h
 type MibNotificationType uint32
@@ -30,17 +28,36 @@ const (
 	MibInitialNotification MibNotificationType = 3
 )
 
+var mibNotificationTypeTable = &enumTable{
+	typeName:    "MibNotificationType",
+	unknown:     "MibNotificationType_UNKNOWN",
+	shortPrefix: "Mib",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(MibParameterNotification), "MibParameterNotification"},
+		{int64(MibAddInstance), "MibAddInstance"},
+		{int64(MibDeleteInstance), "MibDeleteInstance"},
+		{int64(MibInitialNotification), "MibInitialNotification"},
+	},
+}
+
 func (mnt MibNotificationType) String() string {
-	switch mnt {
-	case MibParameterNotification:
-		return "MibParameterNotification"
-	case MibAddInstance:
-		return "MibAddInstance"
-	case MibDeleteInstance:
-		return "MibDeleteInstance"
-	case MibInitialNotification:
-		return "MibInitialNotification"
-	default:
-		return fmt.Sprintf("MibNotificationType_UNKNOWN(%d)", mnt)
+	return mibNotificationTypeTable.format(int64(mnt))
+}
+
+func (mnt MibNotificationType) MarshalText() ([]byte, error) {
+	return []byte(mnt.String()), nil
+}
+
+func (mnt *MibNotificationType) UnmarshalText(text []byte) error {
+
+	value, err := mibNotificationTypeTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*mnt = MibNotificationType(value)
+
+	return nil
 }
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -13,21 +16,21 @@ import (
 // Corresponds to MIB_IPFORWARD_ROW2 defined in netioapi.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/ns-netioapi-_mib_ipforward_row2).
 type Route struct {
-	InterfaceLuid        uint64
-	InterfaceIndex       uint32
-	DestinationPrefix    IpAddressPrefix
-	NextHop              SockaddrInet
-	SitePrefixLength     uint8
-	ValidLifetime        uint32
-	PreferredLifetime    uint32
-	Metric               uint32
-	Protocol             NlRouteProtocol
-	Loopback             bool
-	AutoconfigureAddress bool
-	Publish              bool
-	Immortal             bool
-	Age                  uint32
-	Origin               NlRouteOrigin
+	InterfaceLuid        uint64          `json:"interfaceLuid"`
+	InterfaceIndex       uint32          `json:"interfaceIndex"`
+	DestinationPrefix    IpAddressPrefix `json:"destinationPrefix"`
+	NextHop              SockaddrInet    `json:"nextHop"`
+	SitePrefixLength     uint8           `json:"sitePrefixLength"`
+	ValidLifetime        uint32          `json:"validLifetime"`
+	PreferredLifetime    uint32          `json:"preferredLifetime"`
+	Metric               uint32          `json:"metric"`
+	Protocol             NlRouteProtocol `json:"protocol"`
+	Loopback             bool            `json:"loopback"`
+	AutoconfigureAddress bool            `json:"autoconfigureAddress"`
+	Publish              bool            `json:"publish"`
+	Immortal             bool            `json:"immortal"`
+	Age                  uint32          `json:"age"`
+	Origin               NlRouteOrigin   `json:"origin"`
 }
 
 func getRoutes(family AddressFamily) ([]*Route, error) {
@@ -5,10 +5,56 @@
 
 package winipcfg
 
-import "net"
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+)
 
 type RouteData struct {
-	Destination net.IPNet
-	NextHop     net.IP
-	Metric      uint32
+	Destination net.IPNet `json:"destination"`
+	NextHop     net.IP    `json:"nextHop"`
+	Metric      uint32    `json:"metric"`
+}
+
+type routeDataJSON struct {
+	Destination string `json:"destination"`
+	NextHop     net.IP `json:"nextHop"`
+	Metric      uint32 `json:"metric"`
+}
+
+// Encodes the route as {"destination": "<CIDR>", "nextHop": "<ip>", "metric": <Metric>}. Host bits of the
+// destination's IP are kept, i.e. "10.0.0.1/24" is decoded as such rather than as "10.0.0.0/24".
+func (rd RouteData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&routeDataJSON{
+		Destination: rd.Destination.String(),
+		NextHop:     rd.NextHop,
+		Metric:      rd.Metric,
+	})
+}
+
+func (rd *RouteData) UnmarshalJSON(data []byte) error {
+
+	var rdj routeDataJSON
+
+	if err := json.Unmarshal(data, &rdj); err != nil {
+		return err
+	}
+
+	ip, destination, err := net.ParseCIDR(rdj.Destination)
+
+	if err != nil {
+		return fmt.Errorf("RouteData.UnmarshalJSON() - invalid destination '%s'", rdj.Destination)
+	}
+
+	// net.ParseCIDR() masks the IP of the returned IPNet; keep its length (4 bytes for IPv4) but not its value.
+	if len(destination.IP) == net.IPv4len {
+		ip = ip.To4()
+	}
+
+	destination.IP = ip
+
+	*rd = RouteData{Destination: *destination, NextHop: rdj.NextHop, Metric: rdj.Metric}
+
+	return nil
 }
@@ -0,0 +1,109 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Options of DiffIfRows() and DiffIpInterfaces().
+type DiffOptions struct {
+	// Ignores fields which change on their own all the time: IfRow's traffic counters (InOctets...OutQLen, but not
+	// the link speeds), and IpInterface's ReachableTime, which is randomized by the stack (RFC 4861, section 6.3.2).
+	IgnoreVolatile bool
+
+	// Names of additional fields to ignore. Fields of nested structs are named with a dot, i.e.
+	// "InterfaceAndOperStatusFlags.ConnectorPresent".
+	IgnoreFields []string
+}
+
+// Volatile fields of IfRow, see DiffOptions.IgnoreVolatile.
+var ifRowVolatileFields = []string{
+	"InOctets", "InUcastPkts", "InNUcastPkts", "InDiscards", "InErrors", "InUnknownProtos", "InUcastOctets",
+	"InMulticastOctets", "InBroadcastOctets", "OutOctets", "OutUcastPkts", "OutNUcastPkts", "OutDiscards",
+	"OutErrors", "OutUcastOctets", "OutMulticastOctets", "OutBroadcastOctets", "OutQLen",
+}
+
+// Volatile fields of IpInterface, see DiffOptions.IgnoreVolatile.
+var ipInterfaceVolatileFields = []string{"ReachableTime"}
+
+// Returns changes of fields between 'oldRow' and 'newRow', i.e. two snapshots of an interface's IfRow taken before
+// and after an interface change notification. Both arguments have to be non-nil; 'options' may be nil.
+func DiffIfRows(oldRow *IfRow, newRow *IfRow, options *DiffOptions) []*FieldChange {
+	return diffStructs(reflect.ValueOf(oldRow).Elem(), reflect.ValueOf(newRow).Elem(),
+		options.ignoredFields(ifRowVolatileFields))
+}
+
+// Returns changes of fields between 'oldIpifc' and 'newIpifc', i.e. two snapshots of an interface's IpInterface
+// taken before and after a MibParameterNotification. Both arguments have to be non-nil; 'options' may be nil.
+func DiffIpInterfaces(oldIpifc *IpInterface, newIpifc *IpInterface, options *DiffOptions) []*FieldChange {
+	return diffStructs(reflect.ValueOf(oldIpifc).Elem(), reflect.ValueOf(newIpifc).Elem(),
+		options.ignoredFields(ipInterfaceVolatileFields))
+}
+
+func (options *DiffOptions) ignoredFields(volatileFields []string) map[string]bool {
+
+	ignored := make(map[string]bool)
+
+	if options == nil {
+		return ignored
+	}
+
+	if options.IgnoreVolatile {
+		for _, name := range volatileFields {
+			ignored[name] = true
+		}
+	}
+
+	for _, name := range options.IgnoreFields {
+		ignored[name] = true
+	}
+
+	return ignored
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// Compares fields of two struct values of the same type, in the order of declaration. Struct fields which don't have
+// their own String() method (i.e. flag sets) are compared field by field, named "<field>.<nested field>".
+func diffStructs(oldValue reflect.Value, newValue reflect.Value, ignored map[string]bool) []*FieldChange {
+
+	changes := make([]*FieldChange, 0)
+
+	var diff func(prefix string, oldValue reflect.Value, newValue reflect.Value)
+
+	diff = func(prefix string, oldValue reflect.Value, newValue reflect.Value) {
+
+		for i := 0; i < oldValue.NumField(); i++ {
+
+			field := oldValue.Type().Field(i)
+			name := prefix + field.Name
+
+			if field.PkgPath != "" || ignored[name] {
+				continue
+			}
+
+			oldField, newField := oldValue.Field(i), newValue.Field(i)
+
+			if field.Type.Kind() == reflect.Struct && !field.Type.Implements(stringerType) {
+				diff(name+".", oldField, newField)
+				continue
+			}
+
+			if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+				changes = append(changes, &FieldChange{Name: name, Old: oldField.Interface(), New: newField.Interface()})
+			}
+		}
+	}
+
+	diff("", oldValue, newValue)
+
+	return changes
+}
@@ -0,0 +1,107 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func fieldChangeStrings(changes []*FieldChange) []string {
+
+	result := make([]string, len(changes))
+
+	for i, change := range changes {
+		result[i] = change.String()
+	}
+
+	return result
+}
+
+func TestDiffIfRows(t *testing.T) {
+
+	oldRow := &IfRow{
+		InterfaceLuid: 1 << 24,
+		Alias:         "Ethernet",
+		OperStatus:    IfOperStatusUp,
+		InOctets:      1000,
+		OutQLen:       1,
+		InterfaceAndOperStatusFlags: InterfaceAndOperStatusFlags{
+			ConnectorPresent: true,
+		},
+	}
+
+	newRow := *oldRow
+	newRow.Alias = "Ethernet 2"
+	newRow.OperStatus = IfOperStatusDown
+	newRow.InOctets = 2000
+	newRow.OutQLen = 0
+	newRow.InterfaceAndOperStatusFlags.NotMediaConnected = true
+
+	tests := []struct {
+		options  *DiffOptions
+		expected []string
+	}{
+		{nil, []string{
+			"Alias: Ethernet -> Ethernet 2",
+			"InterfaceAndOperStatusFlags.NotMediaConnected: false -> true",
+			"OperStatus: IfOperStatusUp -> IfOperStatusDown",
+			"InOctets: 1000 -> 2000",
+			"OutQLen: 1 -> 0",
+		}},
+		{&DiffOptions{IgnoreVolatile: true}, []string{
+			"Alias: Ethernet -> Ethernet 2",
+			"InterfaceAndOperStatusFlags.NotMediaConnected: false -> true",
+			"OperStatus: IfOperStatusUp -> IfOperStatusDown",
+		}},
+		{&DiffOptions{IgnoreVolatile: true, IgnoreFields: []string{"Alias",
+			"InterfaceAndOperStatusFlags.NotMediaConnected"}}, []string{
+			"OperStatus: IfOperStatusUp -> IfOperStatusDown",
+		}},
+	}
+
+	for i, test := range tests {
+		actual := fieldChangeStrings(DiffIfRows(oldRow, &newRow, test.options))
+		if !reflect.DeepEqual(actual, test.expected) {
+			t.Errorf("DiffIfRows() test %d returned %v instead of %v.", i, actual, test.expected)
+		}
+	}
+
+	if changes := DiffIfRows(oldRow, oldRow, nil); len(changes) != 0 {
+		t.Errorf("DiffIfRows() returned changes for identical rows: %v", fieldChangeStrings(changes))
+	}
+}
+
+func TestDiffIpInterfaces(t *testing.T) {
+
+	oldIpifc := &IpInterface{Family: AF_INET6, InterfaceLuid: 1 << 24, NlMtu: 1500, ReachableTime: 30000}
+
+	newIpifc := *oldIpifc
+	newIpifc.NlMtu = 1420
+	newIpifc.ReachableTime = 27500
+	newIpifc.ZoneIndices[1] = 12
+	newIpifc.TransmitOffload.NlChecksumSupported = true
+
+	expected := []string{
+		"ZoneIndices: [0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0] -> [0 12 0 0 0 0 0 0 0 0 0 0 0 0 0 0]",
+		"NlMtu: 1500 -> 1420",
+		"TransmitOffload.NlChecksumSupported: false -> true",
+	}
+
+	actual := fieldChangeStrings(DiffIpInterfaces(oldIpifc, &newIpifc, &DiffOptions{IgnoreVolatile: true}))
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("DiffIpInterfaces() returned %v instead of %v.", actual, expected)
+	}
+
+	if changes := DiffIpInterfaces(oldIpifc, &newIpifc, nil); len(changes) != len(expected)+1 {
+		t.Errorf("DiffIpInterfaces() without options should report ReachableTime too: %v",
+			fieldChangeStrings(changes))
+	}
+}
@@ -6,8 +6,10 @@
 package winipcfg
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
+	"strings"
 	"unsafe"
 )
 
@@ -139,6 +141,87 @@ func (sainet *SockaddrInet) toWtSocketAddress() (*wtSocketAddress, error) {
 	return &wtsa, nil
 }
 
+type sockaddrInetJSON struct {
+	Address  string `json:"address"`
+	Port     uint16 `json:"port,omitempty"`
+	FlowInfo uint32 `json:"flowInfo,omitempty"`
+}
+
+// Encodes the address as {"address": "<ip>[%<IPv6ScopeId>]", "port": <Port>, "flowInfo": <IPv6FlowInfo>}, where
+// zero port and flow info are omitted. Family is implied by the address; IPv4-mapped addresses of AF_INET6 family are
+// encoded in their "::ffff:a.b.c.d" form. Zero SockaddrInet is encoded as null.
+func (sainet SockaddrInet) MarshalJSON() ([]byte, error) {
+
+	if sainet.Family == 0 && sainet.Address == nil {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(&sockaddrInetJSON{
+		Address:  sainet.jsonAddress(),
+		Port:     sainet.Port,
+		FlowInfo: sainet.IPv6FlowInfo,
+	})
+}
+
+func (sainet *SockaddrInet) UnmarshalJSON(data []byte) error {
+
+	if string(data) == "null" {
+		return nil
+	}
+
+	var sj sockaddrInetJSON
+
+	if err := json.Unmarshal(data, &sj); err != nil {
+		return err
+	}
+
+	ip, zone, err := parseIPWithZone(sj.Address)
+
+	if err != nil {
+		return err
+	}
+
+	*sainet = SockaddrInet{
+		Family:       AF_INET6,
+		Port:         sj.Port,
+		Address:      ip,
+		IPv6FlowInfo: sj.FlowInfo,
+		IPv6ScopeId:  zone,
+	}
+
+	if ip.To4() != nil && !isIPv6Text(sj.Address) {
+
+		if sj.FlowInfo != 0 {
+			return fmt.Errorf("SockaddrInet.UnmarshalJSON() - IPv4 address '%s' cannot have flow info", sj.Address)
+		}
+
+		sainet.Family = AF_INET
+	}
+
+	return nil
+}
+
+// Formats the address and zone the way JSON encodings do. Unlike ipWithZoneToString(), keeps the "::ffff:" prefix of
+// IPv4-mapped addresses of AF_INET6 family, so that the family survives a round trip.
+func (sainet *SockaddrInet) jsonAddress() string {
+
+	if v4 := sainet.Address.To4(); v4 != nil && sainet.Family == AF_INET6 {
+
+		if sainet.IPv6ScopeId == 0 {
+			return "::ffff:" + v4.String()
+		}
+
+		return fmt.Sprintf("::ffff:%s%%%d", v4.String(), sainet.IPv6ScopeId)
+	}
+
+	return ipWithZoneToString(sainet.Address, sainet.IPv6ScopeId)
+}
+
+// Returns true if 'str' is an address in IPv6 text form, even if it's an IPv4-mapped one.
+func isIPv6Text(str string) bool {
+	return strings.IndexByte(str, ':') != -1
+}
+
 func (sainet *SockaddrInet) String() string {
 
 	if sainet == nil {
@@ -0,0 +1,376 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"net"
+)
+
+// An entry of the prefix policy table described in RFC 6724, section 2.1.
+type PrefixPolicy struct {
+	Prefix     net.IPNet
+	Precedence uint32
+	Label      uint32
+}
+
+// Returns the default prefix policy table as defined in RFC 6724, section 2.1. IPv4 addresses are looked up in the
+// table as IPv4-mapped IPv6 addresses.
+func DefaultPrefixPolicyTable() []*PrefixPolicy {
+	return []*PrefixPolicy{
+		newPrefixPolicy("::1/128", 50, 0),
+		newPrefixPolicy("::/0", 40, 1),
+		newPrefixPolicy("::ffff:0:0/96", 35, 4),
+		newPrefixPolicy("2002::/16", 30, 2),
+		newPrefixPolicy("2001::/32", 5, 5),
+		newPrefixPolicy("fc00::/7", 3, 13),
+		newPrefixPolicy("::/96", 1, 3),
+		newPrefixPolicy("fec0::/10", 1, 11),
+		newPrefixPolicy("3ffe::/16", 1, 12),
+	}
+}
+
+func newPrefixPolicy(prefix string, precedence, label uint32) *PrefixPolicy {
+
+	_, ipnet, err := net.ParseCIDR(prefix)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return &PrefixPolicy{Prefix: *ipnet, Precedence: precedence, Label: label}
+}
+
+// Rule of RFC 6724, section 5 which has decided the outcome of SelectSourceAddress().
+type SourceAddressSelectionRule uint32
+
+const (
+	// There was only one usable candidate.
+	SourceRuleSingleCandidate SourceAddressSelectionRule = 0
+
+	SourceRulePreferSameAddress        SourceAddressSelectionRule = 1
+	SourceRulePreferAppropriateScope   SourceAddressSelectionRule = 2
+	SourceRuleAvoidDeprecatedAddresses SourceAddressSelectionRule = 3
+	SourceRulePreferHomeAddresses      SourceAddressSelectionRule = 4 // Mobile IPv6 only, never decides here.
+	SourceRulePreferOutgoingInterface  SourceAddressSelectionRule = 5
+	SourceRulePreferMatchingLabel      SourceAddressSelectionRule = 6
+	SourceRulePreferTemporaryAddresses SourceAddressSelectionRule = 7
+	SourceRuleUseLongestMatchingPrefix SourceAddressSelectionRule = 8
+
+	// No rule could tell the candidates apart, so the first one was chosen.
+	SourceRuleCandidateOrder SourceAddressSelectionRule = 9
+)
+
+var sourceAddressSelectionRuleTable = &enumTable{
+	typeName:    "SourceAddressSelectionRule",
+	unknown:     "SourceAddressSelectionRule_UNKNOWN",
+	shortPrefix: "SourceRule",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(SourceRuleSingleCandidate), "SourceRuleSingleCandidate"},
+		{int64(SourceRulePreferSameAddress), "SourceRulePreferSameAddress"},
+		{int64(SourceRulePreferAppropriateScope), "SourceRulePreferAppropriateScope"},
+		{int64(SourceRuleAvoidDeprecatedAddresses), "SourceRuleAvoidDeprecatedAddresses"},
+		{int64(SourceRulePreferHomeAddresses), "SourceRulePreferHomeAddresses"},
+		{int64(SourceRulePreferOutgoingInterface), "SourceRulePreferOutgoingInterface"},
+		{int64(SourceRulePreferMatchingLabel), "SourceRulePreferMatchingLabel"},
+		{int64(SourceRulePreferTemporaryAddresses), "SourceRulePreferTemporaryAddresses"},
+		{int64(SourceRuleUseLongestMatchingPrefix), "SourceRuleUseLongestMatchingPrefix"},
+		{int64(SourceRuleCandidateOrder), "SourceRuleCandidateOrder"},
+	},
+}
+
+func (rule SourceAddressSelectionRule) String() string {
+	return sourceAddressSelectionRuleTable.format(int64(rule))
+}
+
+func (rule SourceAddressSelectionRule) MarshalText() ([]byte, error) {
+	return []byte(rule.String()), nil
+}
+
+func (rule *SourceAddressSelectionRule) UnmarshalText(text []byte) error {
+
+	value, err := sourceAddressSelectionRuleTable.parse(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*rule = SourceAddressSelectionRule(value)
+
+	return nil
+}
+
+// Predicts which of the 'candidates' (typically gotten from GetUnicastAddresses() function) would be used as the
+// source address for 'destination', by applying the source address selection rules from RFC 6724, section 5. Candidates
+// of the other address family, candidates with SkipAsSource set and candidates which aren't in preferred or deprecated
+// DAD state are not considered. Argument 'outgoingInterfaceLuid' is the LUID of the interface the destination is
+// routed through, or 0 if unknown, in which case rule 5 is skipped. If 'policyTable' is nil,
+// DefaultPrefixPolicyTable() is used.
+//
+// Returned rule is the one which was needed to prefer the chosen address over all other usable candidates.
+func SelectSourceAddress(destination net.IP, outgoingInterfaceLuid uint64, candidates []*UnicastIpAddressRow,
+	policyTable []*PrefixPolicy) (*UnicastIpAddressRow, SourceAddressSelectionRule, error) {
+
+	destination = canonicalIP(destination)
+
+	if destination == nil {
+		return nil, SourceRuleSingleCandidate, fmt.Errorf("SelectSourceAddress() - invalid destination address")
+	}
+
+	if policyTable == nil {
+		policyTable = DefaultPrefixPolicyTable()
+	}
+
+	var usable []*UnicastIpAddressRow
+
+	for _, candidate := range candidates {
+		if isUsableSourceAddress(candidate, destination) {
+			usable = append(usable, candidate)
+		}
+	}
+
+	if len(usable) < 1 {
+		return nil, SourceRuleSingleCandidate,
+			fmt.Errorf("SelectSourceAddress() - no usable source address for %s", destination.String())
+	}
+
+	selector := sourceAddressSelector{
+		destination:           destination,
+		outgoingInterfaceLuid: outgoingInterfaceLuid,
+		policyTable:           policyTable,
+	}
+
+	best := usable[0]
+
+	for _, candidate := range usable[1:] {
+		if preferred, _ := selector.compare(best, candidate); preferred > 0 {
+			best = candidate
+		}
+	}
+
+	decidingRule := SourceRuleSingleCandidate
+
+	for _, candidate := range usable {
+
+		if candidate == best {
+			continue
+		}
+
+		preferred, rule := selector.compare(best, candidate)
+
+		if preferred == 0 {
+			rule = SourceRuleCandidateOrder
+		}
+
+		if rule > decidingRule {
+			decidingRule = rule
+		}
+	}
+
+	return best, decidingRule, nil
+}
+
+func isUsableSourceAddress(candidate *UnicastIpAddressRow, destination net.IP) bool {
+
+	if candidate == nil || candidate.Address == nil || candidate.SkipAsSource {
+		return false
+	}
+
+	if candidate.DadState != IpDadStatePreferred && candidate.DadState != IpDadStateDeprecated {
+		return false
+	}
+
+	ip := canonicalIP(candidate.Address.Address)
+
+	return ip != nil && len(ip) == len(destination)
+}
+
+type sourceAddressSelector struct {
+	destination           net.IP
+	outgoingInterfaceLuid uint64
+	policyTable           []*PrefixPolicy
+}
+
+// Compares two candidate source addresses. The first returned value is negative if 'a' is preferred, positive if 'b' is
+// preferred and 0 if the rules cannot tell them apart. The second returned value is the rule which has decided.
+func (s *sourceAddressSelector) compare(a, b *UnicastIpAddressRow) (int, SourceAddressSelectionRule) {
+
+	ipA := canonicalIP(a.Address.Address)
+	ipB := canonicalIP(b.Address.Address)
+
+	// Rule 1: Prefer same address.
+	if ipA.Equal(s.destination) != ipB.Equal(s.destination) {
+		return preferIf(ipA.Equal(s.destination)), SourceRulePreferSameAddress
+	}
+
+	// Rule 2: Prefer appropriate scope.
+	scopeA, scopeB, scopeD := addressScope(ipA), addressScope(ipB), addressScope(s.destination)
+
+	if scopeA < scopeB {
+		return preferIf(scopeA >= scopeD), SourceRulePreferAppropriateScope
+	} else if scopeB < scopeA {
+		return preferIf(scopeB < scopeD), SourceRulePreferAppropriateScope
+	}
+
+	// Rule 3: Avoid deprecated addresses.
+	if isDeprecatedAddress(a) != isDeprecatedAddress(b) {
+		return preferIf(!isDeprecatedAddress(a)), SourceRuleAvoidDeprecatedAddresses
+	}
+
+	// Rule 4 (prefer home addresses) applies to Mobile IPv6 only.
+
+	// Rule 5: Prefer outgoing interface.
+	if s.outgoingInterfaceLuid != 0 {
+
+		outgoingA := a.InterfaceLuid == s.outgoingInterfaceLuid
+		outgoingB := b.InterfaceLuid == s.outgoingInterfaceLuid
+
+		if outgoingA != outgoingB {
+			return preferIf(outgoingA), SourceRulePreferOutgoingInterface
+		}
+	}
+
+	// Rule 6: Prefer matching label.
+	labelD := s.lookupPolicy(s.destination).Label
+	matchA := s.lookupPolicy(ipA).Label == labelD
+	matchB := s.lookupPolicy(ipB).Label == labelD
+
+	if matchA != matchB {
+		return preferIf(matchA), SourceRulePreferMatchingLabel
+	}
+
+	// Rule 7: Prefer temporary addresses.
+	temporaryA := a.SuffixOrigin == IpSuffixOriginRandom
+	temporaryB := b.SuffixOrigin == IpSuffixOriginRandom
+
+	if temporaryA != temporaryB {
+		return preferIf(temporaryA), SourceRulePreferTemporaryAddresses
+	}
+
+	// Rule 8: Use longest matching prefix.
+	prefixA := commonPrefixLength(ipA, s.destination, int(a.OnLinkPrefixLength))
+	prefixB := commonPrefixLength(ipB, s.destination, int(b.OnLinkPrefixLength))
+
+	if prefixA != prefixB {
+		return preferIf(prefixA > prefixB), SourceRuleUseLongestMatchingPrefix
+	}
+
+	return 0, SourceRuleCandidateOrder
+}
+
+func preferIf(first bool) int {
+	if first {
+		return -1
+	} else {
+		return 1
+	}
+}
+
+// Returns the policy table entry with the longest prefix matching 'ip'.
+func (s *sourceAddressSelector) lookupPolicy(ip net.IP) *PrefixPolicy {
+
+	ip6 := ip.To16()
+
+	var best *PrefixPolicy
+	bestOnes := -1
+
+	for _, policy := range s.policyTable {
+
+		if policy == nil || !policy.Prefix.Contains(ip6) {
+			continue
+		}
+
+		ones, _ := policy.Prefix.Mask.Size()
+
+		if ones > bestOnes {
+			best = policy
+			bestOnes = ones
+		}
+	}
+
+	if best == nil {
+		return &PrefixPolicy{}
+	}
+
+	return best
+}
+
+func isDeprecatedAddress(address *UnicastIpAddressRow) bool {
+	return address.DadState == IpDadStateDeprecated || address.PreferredLifetime == 0
+}
+
+// Returns 'ip' in its 4-byte form if it's an IPv4 address, 16-byte form if it's an IPv6 address and nil otherwise.
+func canonicalIP(ip net.IP) net.IP {
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+
+	return ip.To16()
+}
+
+// Returns the scope of 'ip' as defined in RFC 6724, section 3.1, using ScopeLevel* values.
+func addressScope(ip net.IP) wtScopeLevel {
+
+	if ip4 := ip.To4(); ip4 != nil {
+		// RFC 6724, section 3.2: loopback and auto-configuration addresses are link-local, all other IPv4 addresses
+		// (including private ones) are global.
+		if ip4.IsLoopback() || ip4.IsLinkLocalUnicast() || ip4.IsLinkLocalMulticast() {
+			return ScopeLevelLink
+		}
+
+		return ScopeLevelGlobal
+	}
+
+	if ip.IsMulticast() {
+		return wtScopeLevel(ip[1] & 0x0f)
+	}
+
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return ScopeLevelLink
+	}
+
+	if ip[0] == 0xfe && ip[1]&0xc0 == 0xc0 {
+		// Deprecated site-local fec0::/10 addresses.
+		return ScopeLevelSite
+	}
+
+	return ScopeLevelGlobal
+}
+
+// Returns the number of leading bits 'source' and 'destination' have in common, limited to 'prefixLength' (the length
+// of the source's prefix).
+func commonPrefixLength(source, destination net.IP, prefixLength int) int {
+
+	length := 0
+
+	for i := 0; i < len(source) && i < len(destination); i++ {
+
+		diff := source[i] ^ destination[i]
+
+		if diff == 0 {
+			length += 8
+			continue
+		}
+
+		for diff&0x80 == 0 {
+			length++
+			diff <<= 1
+		}
+
+		break
+	}
+
+	if length > prefixLength {
+		return prefixLength
+	}
+
+	return length
+}
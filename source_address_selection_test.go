@@ -0,0 +1,152 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"net"
+	"testing"
+)
+
+type sourceAddressCandidate struct {
+	ip         string
+	deprecated bool
+	temporary  bool
+}
+
+func sourceAddressCandidateRows(candidates ...sourceAddressCandidate) []*UnicastIpAddressRow {
+
+	rows := make([]*UnicastIpAddressRow, len(candidates))
+
+	for i, candidate := range candidates {
+
+		sainet, _ := createSockaddrInet(net.ParseIP(candidate.ip))
+
+		rows[i] = &UnicastIpAddressRow{
+			Address:            sainet,
+			InterfaceLuid:      1,
+			SuffixOrigin:       IpSuffixOriginLinkLayerAddress,
+			ValidLifetime:      0xffffffff,
+			PreferredLifetime:  0xffffffff,
+			OnLinkPrefixLength: 64,
+			DadState:           IpDadStatePreferred,
+		}
+
+		if candidate.ip == "10.1.2.3" || candidate.ip == "169.254.1.1" {
+			rows[i].OnLinkPrefixLength = 16
+		}
+
+		if candidate.deprecated {
+			rows[i].DadState = IpDadStateDeprecated
+		}
+
+		if candidate.temporary {
+			rows[i].SuffixOrigin = IpSuffixOriginRandom
+		}
+	}
+
+	return rows
+}
+
+// Examples from RFC 6724, section 10.1 (except the Mobile IPv6 one) plus a few IPv4 ones.
+func TestSelectSourceAddressRfc6724Examples(t *testing.T) {
+
+	examples := []struct {
+		destination string
+		candidates  []sourceAddressCandidate
+		expected    string
+		rule        SourceAddressSelectionRule
+	}{
+		{"2001:db8:1::1", []sourceAddressCandidate{{ip: "2001:db8:3::1"}, {ip: "fe80::1"}},
+			"2001:db8:3::1", SourceRulePreferAppropriateScope},
+		{"ff05::1", []sourceAddressCandidate{{ip: "2001:db8:3::1"}, {ip: "fe80::1"}},
+			"2001:db8:3::1", SourceRulePreferAppropriateScope},
+		{"2001:db8:1::1", []sourceAddressCandidate{{ip: "2001:db8:1::1", deprecated: true}, {ip: "2001:db8:2::1"}},
+			"2001:db8:1::1", SourceRulePreferSameAddress},
+		{"fe80::1", []sourceAddressCandidate{{ip: "fe80::2", deprecated: true}, {ip: "2001:db8:1::1"}},
+			"fe80::2", SourceRulePreferAppropriateScope},
+		{"2001:db8:1::1", []sourceAddressCandidate{{ip: "2001:db8:1::2"}, {ip: "2001:db8:3::2"}},
+			"2001:db8:1::2", SourceRuleUseLongestMatchingPrefix},
+		{"2002:c633:6401::1", []sourceAddressCandidate{{ip: "2002:c633:6401::d5e3:7953:13eb:22e8", temporary: true},
+			{ip: "2001:db8:1::2"}},
+			"2002:c633:6401::d5e3:7953:13eb:22e8", SourceRulePreferMatchingLabel},
+		{"2001:db8:1::d5e3:0:0:1", []sourceAddressCandidate{{ip: "2001:db8:1::2"},
+			{ip: "2001:db8:1::d5e3:7953:13eb:22e8", temporary: true}},
+			"2001:db8:1::d5e3:7953:13eb:22e8", SourceRulePreferTemporaryAddresses},
+		{"8.8.8.8", []sourceAddressCandidate{{ip: "169.254.1.1"}, {ip: "10.1.2.3"}, {ip: "2001:db8:1::2"}},
+			"10.1.2.3", SourceRulePreferAppropriateScope},
+		{"8.8.8.8", []sourceAddressCandidate{{ip: "10.1.2.3"}},
+			"10.1.2.3", SourceRuleSingleCandidate},
+	}
+
+	for _, example := range examples {
+
+		chosen, rule, err := SelectSourceAddress(net.ParseIP(example.destination), 0,
+			sourceAddressCandidateRows(example.candidates...), nil)
+
+		if err != nil {
+			t.Errorf("SelectSourceAddress() returned an error for destination %s: %v", example.destination, err)
+			continue
+		}
+
+		if !chosen.Address.Address.Equal(net.ParseIP(example.expected)) {
+			t.Errorf("SelectSourceAddress() chose %s for destination %s although %s is expected.",
+				chosen.Address.Address.String(), example.destination, example.expected)
+		}
+
+		if rule != example.rule {
+			t.Errorf("SelectSourceAddress() reported %s for destination %s although %s is expected.", rule,
+				example.destination, example.rule)
+		}
+	}
+}
+
+func TestSelectSourceAddressSkipsUnusable(t *testing.T) {
+
+	rows := sourceAddressCandidateRows(sourceAddressCandidate{ip: "2001:db8:1::1"},
+		sourceAddressCandidate{ip: "2001:db8:1::2"}, sourceAddressCandidate{ip: "2001:db8:1::3"})
+
+	rows[0].SkipAsSource = true
+	rows[1].DadState = IpDadStateTentative
+
+	chosen, rule, err := SelectSourceAddress(net.ParseIP("2001:db8:1::1"), 0, rows, nil)
+
+	if err != nil {
+		t.Fatalf("SelectSourceAddress() returned an error: %v", err)
+	}
+
+	if chosen != rows[2] || rule != SourceRuleSingleCandidate {
+		t.Errorf("SelectSourceAddress() chose %s (%s) although 2001:db8:1::3 is the only usable candidate.",
+			chosen.Address.Address.String(), rule)
+	}
+
+	rows[2].DadState = IpDadStateDuplicate
+
+	if _, _, err = SelectSourceAddress(net.ParseIP("2001:db8:1::1"), 0, rows, nil); err == nil {
+		t.Error("SelectSourceAddress() didn't return an error although there are no usable candidates.")
+	}
+}
+
+func TestSelectSourceAddressOutgoingInterface(t *testing.T) {
+
+	rows := sourceAddressCandidateRows(sourceAddressCandidate{ip: "2001:db8:1::1"},
+		sourceAddressCandidate{ip: "2001:db8:2::1"})
+
+	rows[1].InterfaceLuid = 2
+
+	chosen, rule, err := SelectSourceAddress(net.ParseIP("2001:db8:1::5"), 2, rows, nil)
+
+	if err != nil {
+		t.Fatalf("SelectSourceAddress() returned an error: %v", err)
+	}
+
+	if chosen != rows[1] || rule != SourceRulePreferOutgoingInterface {
+		t.Errorf("SelectSourceAddress() chose %s (%s) although 2001:db8:2::1 on the outgoing interface is expected.",
+			chosen.Address.Address.String(), rule)
+	}
+}
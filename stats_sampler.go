@@ -0,0 +1,350 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Per-second rates of an interface's traffic, computed by StatsSampler.
+type InterfaceRates struct {
+	InterfaceLuid uint64 `json:"interfaceLuid"`
+
+	// Time of the sample the rates were last updated with.
+	Time time.Time `json:"time"`
+
+	InBitsPerSecond      float64 `json:"inBitsPerSecond"`
+	OutBitsPerSecond     float64 `json:"outBitsPerSecond"`
+	InPacketsPerSecond   float64 `json:"inPacketsPerSecond"`
+	OutPacketsPerSecond  float64 `json:"outPacketsPerSecond"`
+	InErrorsPerSecond    float64 `json:"inErrorsPerSecond"`
+	OutErrorsPerSecond   float64 `json:"outErrorsPerSecond"`
+	InDiscardsPerSecond  float64 `json:"inDiscardsPerSecond"`
+	OutDiscardsPerSecond float64 `json:"outDiscardsPerSecond"`
+}
+
+func (r *InterfaceRates) String() string {
+	return fmt.Sprintf("InterfaceLuid: %d; In: %.0f bps, %.1f pps, %.1f errors/s, %.1f discards/s; "+
+		"Out: %.0f bps, %.1f pps, %.1f errors/s, %.1f discards/s", r.InterfaceLuid, r.InBitsPerSecond,
+		r.InPacketsPerSecond, r.InErrorsPerSecond, r.InDiscardsPerSecond, r.OutBitsPerSecond, r.OutPacketsPerSecond,
+		r.OutErrorsPerSecond, r.OutDiscardsPerSecond)
+}
+
+// Source of IfRows sampled by StatsSampler.
+type IfRowSource func() ([]*IfRow, error)
+
+// Returns source of IfRows (with statistics) of all the interfaces.
+func AllIfRowsSource() IfRowSource {
+	return func() ([]*IfRow, error) {
+		return GetIfRows(MibIfEntryNormal)
+	}
+}
+
+// Returns source of IfRows (with statistics) of the interfaces with LUIDs 'interfaceLuids'.
+func IfRowsByLuidSource(interfaceLuids ...uint64) IfRowSource {
+	return func() ([]*IfRow, error) {
+
+		ifrows := make([]*IfRow, len(interfaceLuids))
+
+		for i, luid := range interfaceLuids {
+
+			ifrow, err := GetIfRow(luid, MibIfEntryNormal)
+
+			if err != nil {
+				return nil, err
+			}
+
+			ifrows[i] = ifrow
+		}
+
+		return ifrows, nil
+	}
+}
+
+// Counters of an interface at the time of a sample, in the order of statsCounters().
+type statsSample struct {
+	time     time.Time
+	counters [8]uint64
+}
+
+// Returns IfRow's counters StatsSampler computes rates of: octets, packets, errors and discards, in and out.
+func statsCounters(ifrow *IfRow) [8]uint64 {
+	return [8]uint64{
+		ifrow.InOctets, ifrow.OutOctets,
+		ifrow.InUcastPkts + ifrow.InNUcastPkts, ifrow.OutUcastPkts + ifrow.OutNUcastPkts,
+		ifrow.InErrors, ifrow.OutErrors,
+		ifrow.InDiscards, ifrow.OutDiscards,
+	}
+}
+
+// Samples IfRow counters periodically, and computes per-interface rates smoothed by an exponentially weighted moving
+// average (EWMA).
+//
+// Rates are computed over the time actually elapsed between samples as measured by the sampler's clock, and EWMA
+// weight of each sample depends on that time as well, so that late or early samples (i.e. caused by scheduling jitter)
+// don't skew the rates. All MIB_IF_ROW2 counters are 64-bit ones, so they don't wrap around in practice; counters
+// which go backwards are assumed to have been reset (i.e. by the adapter being restarted), and the sample only becomes
+// the new baseline of the interface, without changing its rates.
+type StatsSampler struct {
+	interval     time.Duration
+	timeConstant time.Duration
+	clock        Clock
+	source       IfRowSource
+	callback     func(rates []*InterfaceRates)
+
+	// Serializes samples, so that the callback is never called concurrently with itself.
+	sampleMutex sync.Mutex
+
+	mutex    sync.Mutex
+	previous map[uint64]*statsSample
+	rates    map[uint64]*InterfaceRates
+	timer    ClockTimer
+	started  bool
+}
+
+// Creates new StatsSampler which samples IfRows from 'source' every 'interval' measured by 'clock' (typically
+// SystemClock). 'timeConstant' is the EWMA time constant: a sample taken after time t has weight 1 - exp(-t /
+// timeConstant). Zero 'timeConstant' disables smoothing, so the rates are those of the last sampling interval.
+// 'callback', if not nil, is called with the rates of all the interfaces after each sample, from its own goroutine,
+// never concurrently with itself. The sampler has to be started with Start() method, or driven by Sample() method.
+func NewStatsSampler(interval time.Duration, timeConstant time.Duration, clock Clock, source IfRowSource,
+	callback func(rates []*InterfaceRates)) *StatsSampler {
+
+	return &StatsSampler{
+		interval:     interval,
+		timeConstant: timeConstant,
+		clock:        clock,
+		source:       source,
+		callback:     callback,
+		previous:     make(map[uint64]*statsSample),
+		rates:        make(map[uint64]*InterfaceRates),
+	}
+}
+
+// Takes the initial sample and starts sampling every interval.
+func (s *StatsSampler) Start() error {
+
+	if s.interval <= 0 {
+		return fmt.Errorf("StatsSampler.Start() - interval has to be positive")
+	}
+
+	s.mutex.Lock()
+
+	if s.started {
+		s.mutex.Unlock()
+		return fmt.Errorf("StatsSampler.Start() - already started")
+	}
+
+	s.started = true
+
+	s.mutex.Unlock()
+
+	if err := s.Sample(); err != nil {
+		s.mutex.Lock()
+		s.started = false
+		s.mutex.Unlock()
+		return err
+	}
+
+	s.mutex.Lock()
+	if s.started {
+		s.timer = s.clock.AfterFunc(s.interval, s.tick)
+	}
+	s.mutex.Unlock()
+
+	return nil
+}
+
+// Stops sampling. The rates computed so far are kept.
+func (s *StatsSampler) Stop() {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.started = false
+
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+}
+
+func (s *StatsSampler) tick() {
+
+	// Errors (i.e. a transient failure of the source) don't stop the sampling; the next sample covers the gap.
+	_ = s.Sample()
+
+	s.mutex.Lock()
+	if s.started {
+		s.timer = s.clock.AfterFunc(s.interval, s.tick)
+	}
+	s.mutex.Unlock()
+}
+
+// Takes a sample from the source and updates the rates. It's called periodically once the sampler is started, but it
+// can also be called directly to drive the sampler manually.
+func (s *StatsSampler) Sample() error {
+
+	s.sampleMutex.Lock()
+	defer s.sampleMutex.Unlock()
+
+	ifrows, err := s.source()
+
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+
+	s.mutex.Lock()
+
+	seen := make(map[uint64]bool, len(ifrows))
+
+	for _, ifrow := range ifrows {
+
+		luid := ifrow.InterfaceLuid
+		seen[luid] = true
+
+		sample := &statsSample{time: now, counters: statsCounters(ifrow)}
+		previous := s.previous[luid]
+		s.previous[luid] = sample
+
+		if previous == nil {
+			continue
+		}
+
+		s.update(luid, previous, sample)
+	}
+
+	// Interfaces which have disappeared.
+	for luid := range s.previous {
+		if !seen[luid] {
+			delete(s.previous, luid)
+			delete(s.rates, luid)
+		}
+	}
+
+	rates := s.ratesLocked()
+
+	s.mutex.Unlock()
+
+	if s.callback != nil {
+		s.callback(rates)
+	}
+
+	return nil
+}
+
+// Updates rates of interface 'luid' with the change of its counters between 'previous' and 'current' samples.
+func (s *StatsSampler) update(luid uint64, previous *statsSample, current *statsSample) {
+
+	elapsed := current.time.Sub(previous.time)
+
+	if elapsed <= 0 {
+		return
+	}
+
+	var sampleRates [8]float64
+
+	for i := range current.counters {
+
+		delta, ok := counterDelta(previous.counters[i], current.counters[i])
+
+		if !ok {
+			// Counters have been reset; the current sample is just the new baseline.
+			return
+		}
+
+		sampleRates[i] = float64(delta) / elapsed.Seconds()
+	}
+
+	// Octets to bits.
+	sampleRates[0] *= 8
+	sampleRates[1] *= 8
+
+	weight := 1.0
+
+	rates, ok := s.rates[luid]
+
+	if ok && s.timeConstant > 0 {
+		weight = 1 - math.Exp(-elapsed.Seconds()/s.timeConstant.Seconds())
+	} else if !ok {
+		rates = &InterfaceRates{InterfaceLuid: luid}
+		s.rates[luid] = rates
+	}
+
+	fields := []*float64{
+		&rates.InBitsPerSecond, &rates.OutBitsPerSecond,
+		&rates.InPacketsPerSecond, &rates.OutPacketsPerSecond,
+		&rates.InErrorsPerSecond, &rates.OutErrorsPerSecond,
+		&rates.InDiscardsPerSecond, &rates.OutDiscardsPerSecond,
+	}
+
+	for i, field := range fields {
+		*field += weight * (sampleRates[i] - *field)
+	}
+
+	rates.Time = current.time
+}
+
+// Returns how much a counter has grown from 'previous' to 'current'. Returns false if the counter has been reset.
+// MIB_IF_ROW2 counters are ULONG64, so any decrease is a reset rather than a wraparound.
+func counterDelta(previous uint64, current uint64) (uint64, bool) {
+
+	if current < previous {
+		return 0, false
+	}
+
+	return current - previous, true
+}
+
+// Returns copies of the current rates of all the interfaces, sorted by LUID. Interfaces sampled only once so far have
+// no rates yet.
+func (s *StatsSampler) Rates() []*InterfaceRates {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.ratesLocked()
+}
+
+// Returns copy of the current rates of interface 'interfaceLuid', or nil if there are none.
+func (s *StatsSampler) RatesOf(interfaceLuid uint64) *InterfaceRates {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	rates, ok := s.rates[interfaceLuid]
+
+	if !ok {
+		return nil
+	}
+
+	copied := *rates
+
+	return &copied
+}
+
+func (s *StatsSampler) ratesLocked() []*InterfaceRates {
+
+	result := make([]*InterfaceRates, 0, len(s.rates))
+
+	for _, rates := range s.rates {
+		copied := *rates
+		result = append(result, &copied)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].InterfaceLuid < result[j].InterfaceLuid })
+
+	return result
+}
@@ -0,0 +1,219 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+// IfRows returned by StatsSampler's source in tests, changed by the test between samples.
+type statsSamplerTestSource struct {
+	mutex  sync.Mutex
+	ifrows map[uint64]*IfRow
+}
+
+func (s *statsSamplerTestSource) source() ([]*IfRow, error) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ifrows := make([]*IfRow, 0, len(s.ifrows))
+
+	for _, ifrow := range s.ifrows {
+		copied := *ifrow
+		ifrows = append(ifrows, &copied)
+	}
+
+	return ifrows, nil
+}
+
+// Adds the deltas to the interface's counters, creating the interface if needed.
+func (s *statsSamplerTestSource) add(luid uint64, inOctets uint64, outOctets uint64, inPackets uint64,
+	inErrors uint64) {
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ifrow, ok := s.ifrows[luid]
+
+	if !ok {
+		ifrow = &IfRow{InterfaceLuid: luid}
+		s.ifrows[luid] = ifrow
+	}
+
+	ifrow.InOctets += inOctets
+	ifrow.OutOctets += outOctets
+	ifrow.InUcastPkts += inPackets
+	ifrow.InErrors += inErrors
+}
+
+func assertRate(t *testing.T, name string, actual float64, expected float64) {
+	t.Helper()
+	if math.Abs(actual-expected) > 1e-6*math.Max(1, math.Abs(expected)) {
+		t.Errorf("%s is %f instead of %f.", name, actual, expected)
+	}
+}
+
+func TestStatsSamplerRates(t *testing.T) {
+
+	clock := newFakeClock()
+	source := &statsSamplerTestSource{ifrows: make(map[uint64]*IfRow)}
+	source.add(1, 1000, 0, 0, 0)
+
+	var callbackRates []*InterfaceRates
+
+	sampler := NewStatsSampler(time.Second, 0, clock, source.source, func(rates []*InterfaceRates) {
+		callbackRates = rates
+	})
+
+	if err := sampler.Start(); err != nil {
+		t.Fatalf("StatsSampler.Start() returned an error: %v", err)
+	}
+
+	if sampler.RatesOf(1) != nil {
+		t.Error("Interface sampled once shouldn't have rates yet.")
+	}
+
+	source.add(1, 125000, 62500, 100, 2)
+	clock.advance(time.Second)
+
+	rates := sampler.RatesOf(1)
+
+	if rates == nil {
+		t.Fatal("StatsSampler hasn't computed rates after the second sample.")
+	}
+
+	assertRate(t, "InBitsPerSecond", rates.InBitsPerSecond, 1000000)
+	assertRate(t, "OutBitsPerSecond", rates.OutBitsPerSecond, 500000)
+	assertRate(t, "InPacketsPerSecond", rates.InPacketsPerSecond, 100)
+	assertRate(t, "InErrorsPerSecond", rates.InErrorsPerSecond, 2)
+
+	if len(callbackRates) != 1 || callbackRates[0].InterfaceLuid != 1 {
+		t.Errorf("Callback received unexpected rates: %v", callbackRates)
+	}
+
+	// Late sample: the rate is computed over the time actually elapsed. The timer fires after the interval, so the
+	// jitter is simulated by advancing the clock in two steps, with the counters changing in between.
+	clock.advance(500 * time.Millisecond)
+	source.add(1, 187500, 0, 0, 0)
+	clock.advance(time.Second)
+
+	assertRate(t, "InBitsPerSecond after a late sample", sampler.RatesOf(1).InBitsPerSecond, 1000000)
+
+	sampler.Stop()
+
+	if clock.pendingTimers() != 0 {
+		t.Error("StatsSampler.Stop() hasn't stopped the timer.")
+	}
+}
+
+func TestStatsSamplerEWMA(t *testing.T) {
+
+	clock := newFakeClock()
+	source := &statsSamplerTestSource{ifrows: make(map[uint64]*IfRow)}
+	source.add(1, 0, 0, 0, 0)
+
+	sampler := NewStatsSampler(time.Second, 2*time.Second, clock, source.source, nil)
+
+	if err := sampler.Sample(); err != nil {
+		t.Fatalf("StatsSampler.Sample() returned an error: %v", err)
+	}
+
+	// The first rate initializes the average.
+	clock.advance(time.Second)
+	source.add(1, 1000, 0, 0, 0)
+	_ = sampler.Sample()
+
+	assertRate(t, "Initial InBitsPerSecond", sampler.RatesOf(1).InBitsPerSecond, 8000)
+
+	clock.advance(time.Second)
+	_ = sampler.Sample()
+
+	expected := 8000 * math.Exp(-0.5)
+
+	assertRate(t, "Smoothed InBitsPerSecond", sampler.RatesOf(1).InBitsPerSecond, expected)
+
+	// A sample twice as late weighs as much as two regular ones.
+	clock.advance(2 * time.Second)
+	_ = sampler.Sample()
+
+	expected *= math.Exp(-1)
+
+	assertRate(t, "Smoothed InBitsPerSecond after a late sample", sampler.RatesOf(1).InBitsPerSecond, expected)
+}
+
+func TestStatsSamplerCounterReset(t *testing.T) {
+
+	clock := newFakeClock()
+	source := &statsSamplerTestSource{ifrows: make(map[uint64]*IfRow)}
+	source.add(1, math.MaxUint32-99, 0, 0, 0)
+	source.add(2, 0, 0, 0, 0)
+
+	sampler := NewStatsSampler(time.Second, 0, clock, source.source, nil)
+	_ = sampler.Sample()
+
+	// Interface 1 has a 64-bit counter growing past 32-bit range.
+	clock.advance(time.Second)
+	source.ifrows[1].InOctets = math.MaxUint32 + 901
+	source.add(2, 5000, 0, 0, 0)
+	_ = sampler.Sample()
+
+	assertRate(t, "InBitsPerSecond past 32-bit range", sampler.RatesOf(1).InBitsPerSecond, 8000)
+
+	// Interface 2 is restarted: its counters start from zero again. Its rates are kept, and the next sample is
+	// relative to the reset counters.
+	clock.advance(time.Second)
+	source.ifrows[2].InOctets = 100
+	_ = sampler.Sample()
+
+	assertRate(t, "InBitsPerSecond after reset", sampler.RatesOf(2).InBitsPerSecond, 40000)
+
+	clock.advance(time.Second)
+	source.add(2, 250, 0, 0, 0)
+	_ = sampler.Sample()
+
+	assertRate(t, "InBitsPerSecond after reset", sampler.RatesOf(2).InBitsPerSecond, 2000)
+
+	// Interface which has disappeared is dropped.
+	delete(source.ifrows, 1)
+	clock.advance(time.Second)
+	_ = sampler.Sample()
+
+	if rates := sampler.Rates(); len(rates) != 1 || rates[0].InterfaceLuid != 2 {
+		t.Errorf("StatsSampler.Rates() returned %v.", rates)
+	}
+}
+
+func TestCounterDelta(t *testing.T) {
+
+	tests := []struct {
+		previous uint64
+		current  uint64
+		delta    uint64
+		ok       bool
+	}{
+		{10, 25, 15, true},
+		{10, 10, 0, true},
+		{math.MaxUint32, math.MaxUint32 + 5, 5, true},
+		{math.MaxUint32, 4, 0, false},
+		{math.MaxUint32/2 + 1, 0, 0, false},
+		{1000, 10, 0, false},
+		{math.MaxUint64, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		if delta, ok := counterDelta(test.previous, test.current); delta != test.delta || ok != test.ok {
+			t.Errorf("counterDelta(%d, %d) returned (%d, %v) instead of (%d, %v).", test.previous, test.current,
+				delta, ok, test.delta, test.ok)
+		}
+	}
+}
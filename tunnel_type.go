@@ -5,8 +5,6 @@
 
 package winipcfg
 
-import "fmt"
-
 // https://docs.microsoft.com/en-us/windows/desktop/api/ifdef/ne-ifdef-tunnel_type
 // TUNNEL_TYPE defined in ifdef.h
 type TunnelType uint32
@@ -21,23 +19,39 @@ const (
 	TUNNEL_TYPE_IPHTTPS TunnelType = 15
 )
 
+var tunnelTypeTable = &enumTable{
+	typeName:    "TunnelType",
+	unknown:     "TunnelType_UNKNOWN",
+	shortPrefix: "TUNNEL_TYPE_",
+	kind:        enumUint32,
+	entries: []enumEntry{
+		{int64(TUNNEL_TYPE_NONE), "TUNNEL_TYPE_NONE"},
+		{int64(TUNNEL_TYPE_OTHER), "TUNNEL_TYPE_OTHER"},
+		{int64(TUNNEL_TYPE_DIRECT), "TUNNEL_TYPE_DIRECT"},
+		{int64(TUNNEL_TYPE_6TO4), "TUNNEL_TYPE_6TO4"},
+		{int64(TUNNEL_TYPE_ISATAP), "TUNNEL_TYPE_ISATAP"},
+		{int64(TUNNEL_TYPE_TEREDO), "TUNNEL_TYPE_TEREDO"},
+		{int64(TUNNEL_TYPE_IPHTTPS), "TUNNEL_TYPE_IPHTTPS"},
+	},
+}
+
 func (t TunnelType) String() string {
-	switch t {
-	case TUNNEL_TYPE_NONE:
-		return "TUNNEL_TYPE_NONE"
-	case TUNNEL_TYPE_OTHER:
-		return "TUNNEL_TYPE_OTHER"
-	case TUNNEL_TYPE_DIRECT:
-		return "TUNNEL_TYPE_DIRECT"
-	case TUNNEL_TYPE_6TO4:
-		return "TUNNEL_TYPE_6TO4"
-	case TUNNEL_TYPE_ISATAP:
-		return "TUNNEL_TYPE_ISATAP"
-	case TUNNEL_TYPE_TEREDO:
-		return "TUNNEL_TYPE_TEREDO"
-	case TUNNEL_TYPE_IPHTTPS:
-		return "TUNNEL_TYPE_IPHTTPS"
-	default:
-		return fmt.Sprintf("TunnelType_UNKNOWN(%d)", t)
+	return tunnelTypeTable.format(int64(t))
+}
+
+func (t TunnelType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *TunnelType) UnmarshalText(text []byte) error {
+
+	value, err := tunnelTypeTable.parse(string(text))
+
+	if err != nil {
+		return err
 	}
+
+	*t = TunnelType(value)
+
+	return nil
 }
@@ -12,14 +12,14 @@ import (
 type UnicastAddress struct {
 	IpAdapterAddressCommonTypeEx
 
-	PrefixOrigin IpPrefixOrigin
-	SuffixOrigin IpSuffixOrigin
-	DadState     IpDadState
+	PrefixOrigin IpPrefixOrigin `json:"prefixOrigin"`
+	SuffixOrigin IpSuffixOrigin `json:"suffixOrigin"`
+	DadState     IpDadState     `json:"dadState"`
 
-	ValidLifetime      uint32
-	PreferredLifetime  uint32
-	LeaseLifetime      uint32
-	OnLinkPrefixLength uint8
+	ValidLifetime      uint32 `json:"validLifetime"`
+	PreferredLifetime  uint32 `json:"preferredLifetime"`
+	LeaseLifetime      uint32 `json:"leaseLifetime"`
+	OnLinkPrefixLength uint8  `json:"onLinkPrefixLength"`
 }
 
 func (ua *UnicastAddress) String() string {
@@ -0,0 +1,391 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Summary of an interface's unicast addresses, delivered by UnicastAddressChangeCoalescer after a batch of changes.
+type UnicastAddressChange struct {
+	InterfaceLuid uint64
+
+	// All unicast addresses the interface has after the changes, sorted by address. Empty if the interface has no
+	// addresses left (i.e. because it has been removed).
+	Addresses []*UnicastIpAddressRow
+
+	// Addresses which have appeared on, or disappeared from the interface since the previous summary.
+	Added   []net.IP
+	Removed []net.IP
+}
+
+// Coalesces unicast address change notifications. RegisterUnicastAddressChangeCallback() callbacks are called once per
+// address per state change (duplicate address detection alone causes several of them), while
+// UnicastAddressChangeCoalescer collects notifications over a time window, dedupes them by (interface LUID, address)
+// and then calls its callback once, with a summary of each interface whose set of addresses (or their prefix lengths
+// or DAD states) has actually changed.
+type UnicastAddressChangeCoalescer struct {
+	window   time.Duration
+	clock    Clock
+	callback func(changes []*UnicastAddressChange)
+
+	// Replaceable for tests.
+	getAddresses     func(family AddressFamily) ([]*UnicastIpAddressRow, error)
+	registerCallback func(callback func(notificationType MibNotificationType, interfaceLuid uint64,
+		ip *net.IP)) (*UnicastAddressChangeCallback, error)
+
+	// Serializes flushes, so that the callback is never called concurrently with itself.
+	flushMutex sync.Mutex
+
+	mutex   sync.Mutex
+	pending map[unicastAddressKey]MibNotificationType
+	timer   ClockTimer
+	known   map[uint64][]*UnicastIpAddressRow
+	// Set from the beginning of Start() until Stop(), so that concurrent Start() calls can't both register.
+	started bool
+	// Set while Start() is in progress.
+	starting bool
+	// Set by Stop(); notifications, flushes and retries arriving afterwards are dropped.
+	stopped      bool
+	registration *UnicastAddressChangeCallback
+}
+
+type unicastAddressKey struct {
+	interfaceLuid uint64
+	address       [net.IPv6len]byte
+}
+
+// Creates new UnicastAddressChangeCoalescer which batches notifications over 'window' measured by 'clock' (typically
+// SystemClock), and delivers summaries to 'callback'. The callback is called from its own goroutine, never
+// concurrently with itself. The coalescer has to be started with Start() method.
+func NewUnicastAddressChangeCoalescer(window time.Duration, clock Clock,
+	callback func(changes []*UnicastAddressChange)) *UnicastAddressChangeCoalescer {
+
+	return &UnicastAddressChangeCoalescer{
+		window:           window,
+		clock:            clock,
+		callback:         callback,
+		getAddresses:     GetUnicastAddresses,
+		registerCallback: RegisterUnicastAddressChangeCallback,
+		pending:          make(map[unicastAddressKey]MibNotificationType),
+		known:            make(map[uint64][]*UnicastIpAddressRow),
+	}
+}
+
+// Registers for change notifications and takes the initial snapshot of unicast addresses. Changes are reported
+// relative to the initial snapshot; since it's taken after registering, no change following it is missed. A stopped
+// coalescer can be started again.
+func (c *UnicastAddressChangeCoalescer) Start() error {
+
+	c.mutex.Lock()
+
+	if c.started {
+		c.mutex.Unlock()
+		return fmt.Errorf("UnicastAddressChangeCoalescer.Start() - already started")
+	}
+
+	c.started = true
+	c.starting = true
+	c.stopped = false
+
+	c.mutex.Unlock()
+
+	// Registering first ensures that any change following the snapshot is notified.
+	registration, err := c.registerCallback(c.notify)
+
+	if err != nil {
+		c.abortStart()
+		return err
+	}
+
+	// Flushes of notifications arriving meanwhile wait for the snapshot, and are computed relative to it.
+	c.flushMutex.Lock()
+	defer c.flushMutex.Unlock()
+
+	rows, err := c.getAddresses(AF_UNSPEC)
+
+	if err != nil {
+		c.abortStart()
+
+		if registration != nil {
+			_ = registration.Unregister()
+		}
+
+		return err
+	}
+
+	c.mutex.Lock()
+
+	if c.stopped {
+		// Stop() has been called meanwhile.
+		c.started = false
+		c.starting = false
+		c.mutex.Unlock()
+
+		if registration != nil {
+			_ = registration.Unregister()
+		}
+
+		return fmt.Errorf("UnicastAddressChangeCoalescer.Start() - stopped while starting")
+	}
+
+	c.known = groupUnicastAddressRows(rows)
+	c.registration = registration
+	c.starting = false
+
+	c.mutex.Unlock()
+
+	return nil
+}
+
+// Releases the slot claimed by Start() which has failed.
+func (c *UnicastAddressChangeCoalescer) abortStart() {
+	c.mutex.Lock()
+	c.started = false
+	c.starting = false
+	c.mutex.Unlock()
+}
+
+// Unregisters from change notifications and drops pending notifications. If a flush is in progress, waits for it to
+// complete, so the callback isn't called after Stop() returns. Therefore Stop() must not be called from the callback.
+func (c *UnicastAddressChangeCoalescer) Stop() error {
+
+	c.mutex.Lock()
+
+	registration := c.registration
+	c.registration = nil
+	c.stopped = true
+
+	// If Start() is still in progress, it releases the slot itself once it notices 'stopped'.
+	if !c.starting {
+		c.started = false
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+
+	c.pending = make(map[unicastAddressKey]MibNotificationType)
+
+	c.mutex.Unlock()
+
+	var err error
+
+	if registration != nil {
+		err = registration.Unregister()
+	}
+
+	// Wait for a flush in progress.
+	c.flushMutex.Lock()
+	c.flushMutex.Unlock()
+
+	return err
+}
+
+func (c *UnicastAddressChangeCoalescer) notify(notificationType MibNotificationType, interfaceLuid uint64,
+	ip *net.IP) {
+
+	key := unicastAddressKey{interfaceLuid: interfaceLuid}
+
+	if ip != nil {
+		copy(key.address[:], ip.To16())
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.stopped {
+		return
+	}
+
+	c.pending[key] = notificationType
+
+	if c.timer == nil {
+		c.timer = c.clock.AfterFunc(c.window, c.flush)
+	}
+}
+
+func (c *UnicastAddressChangeCoalescer) flush() {
+
+	c.flushMutex.Lock()
+	defer c.flushMutex.Unlock()
+
+	c.mutex.Lock()
+
+	if c.stopped {
+		c.mutex.Unlock()
+		return
+	}
+
+	pending := c.pending
+	c.pending = make(map[unicastAddressKey]MibNotificationType)
+	c.timer = nil
+
+	c.mutex.Unlock()
+
+	if len(pending) < 1 {
+		return
+	}
+
+	rows, err := c.getAddresses(AF_UNSPEC)
+
+	if err != nil {
+		// Try again after another window.
+		c.mutex.Lock()
+
+		if c.stopped {
+			c.mutex.Unlock()
+			return
+		}
+
+		for key, notificationType := range pending {
+			if _, ok := c.pending[key]; !ok {
+				c.pending[key] = notificationType
+			}
+		}
+
+		if c.timer == nil {
+			c.timer = c.clock.AfterFunc(c.window, c.flush)
+		}
+
+		c.mutex.Unlock()
+
+		return
+	}
+
+	current := groupUnicastAddressRows(rows)
+
+	c.mutex.Lock()
+
+	if c.stopped {
+		c.mutex.Unlock()
+		return
+	}
+
+	touched := make(map[uint64]bool)
+
+	for key := range pending {
+		if key.interfaceLuid == 0 {
+			// Notification without a row; we don't know which interface has changed, so check all of them.
+			for luid := range c.known {
+				touched[luid] = true
+			}
+			for luid := range current {
+				touched[luid] = true
+			}
+		} else {
+			touched[key.interfaceLuid] = true
+		}
+	}
+
+	luids := make([]uint64, 0, len(touched))
+
+	for luid := range touched {
+		luids = append(luids, luid)
+	}
+
+	sort.Slice(luids, func(i, j int) bool { return luids[i] < luids[j] })
+
+	var changes []*UnicastAddressChange
+
+	for _, luid := range luids {
+
+		change := diffUnicastAddressRows(luid, c.known[luid], current[luid])
+
+		if change == nil {
+			continue
+		}
+
+		if len(current[luid]) > 0 {
+			c.known[luid] = current[luid]
+		} else {
+			delete(c.known, luid)
+		}
+
+		changes = append(changes, change)
+	}
+
+	c.mutex.Unlock()
+
+	if len(changes) > 0 {
+		c.callback(changes)
+	}
+}
+
+// Groups rows by interface LUID, sorting each group by address.
+func groupUnicastAddressRows(rows []*UnicastIpAddressRow) map[uint64][]*UnicastIpAddressRow {
+
+	grouped := make(map[uint64][]*UnicastIpAddressRow)
+
+	for _, row := range rows {
+		if row != nil && row.Address != nil {
+			grouped[row.InterfaceLuid] = append(grouped[row.InterfaceLuid], row)
+		}
+	}
+
+	for _, group := range grouped {
+		sort.Slice(group, func(i, j int) bool {
+			return bytes.Compare(group[i].Address.Address.To16(), group[j].Address.Address.To16()) < 0
+		})
+	}
+
+	return grouped
+}
+
+// Returns nil if 'old' and 'new' (both sorted by address) contain the same addresses, with the same prefix lengths and
+// DAD states.
+func diffUnicastAddressRows(interfaceLuid uint64, old, new []*UnicastIpAddressRow) *UnicastAddressChange {
+
+	change := &UnicastAddressChange{InterfaceLuid: interfaceLuid, Addresses: new}
+	changed := false
+
+	i, j := 0, 0
+
+	for i < len(old) || j < len(new) {
+
+		v := 0
+
+		if i >= len(old) {
+			v = 1
+		} else if j >= len(new) {
+			v = -1
+		} else {
+			v = bytes.Compare(old[i].Address.Address.To16(), new[j].Address.Address.To16())
+		}
+
+		switch {
+		case v < 0:
+			change.Removed = append(change.Removed, old[i].Address.Address)
+			changed = true
+			i++
+		case v > 0:
+			change.Added = append(change.Added, new[j].Address.Address)
+			changed = true
+			j++
+		default:
+			if old[i].OnLinkPrefixLength != new[j].OnLinkPrefixLength || old[i].DadState != new[j].DadState {
+				changed = true
+			}
+			i++
+			j++
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return change
+}
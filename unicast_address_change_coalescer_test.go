@@ -0,0 +1,335 @@
+//go:build windows
+// +build windows
+
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"errors"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func newCoalescerTestRow(luid uint64, ip string, dadState NlDadState) *UnicastIpAddressRow {
+	return &UnicastIpAddressRow{
+		Address:            &SockaddrInet{Family: AF_INET6, Address: net.ParseIP(ip)},
+		InterfaceLuid:      luid,
+		OnLinkPrefixLength: 64,
+		DadState:           dadState,
+	}
+}
+
+func TestUnicastAddressChangeCoalescer(t *testing.T) {
+
+	clock := newFakeClock()
+
+	var calls [][]*UnicastAddressChange
+
+	coalescer := NewUnicastAddressChangeCoalescer(time.Second, clock, func(changes []*UnicastAddressChange) {
+		calls = append(calls, changes)
+	})
+
+	existing := newCoalescerTestRow(1, "2001:db8::1", IpDadStatePreferred)
+	other := newCoalescerTestRow(2, "2001:db8:2::1", IpDadStatePreferred)
+
+	coalescer.known = groupUnicastAddressRows([]*UnicastIpAddressRow{existing, other})
+
+	current := []*UnicastIpAddressRow{existing, other}
+
+	coalescer.getAddresses = func(family AddressFamily) ([]*UnicastIpAddressRow, error) {
+		return current, nil
+	}
+
+	added := net.ParseIP("2001:db8::2")
+
+	// Address appears tentative, then becomes preferred; also a spurious notification for the other interface.
+	coalescer.notify(MibAddInstance, 1, &added)
+	coalescer.notify(MibParameterNotification, 1, &added)
+	coalescer.notify(MibParameterNotification, 1, &added)
+	coalescer.notify(MibParameterNotification, 2, &other.Address.Address)
+
+	if clock.pendingTimers() != 1 {
+		t.Fatalf("UnicastAddressChangeCoalescer has %d timers pending although 1 is expected.",
+			clock.pendingTimers())
+	}
+
+	current = []*UnicastIpAddressRow{existing, newCoalescerTestRow(1, "2001:db8::2", IpDadStatePreferred), other}
+
+	clock.advance(500 * time.Millisecond)
+
+	if len(calls) != 0 {
+		t.Fatal("UnicastAddressChangeCoalescer called the callback before the window has elapsed.")
+	}
+
+	clock.advance(500 * time.Millisecond)
+
+	if len(calls) != 1 {
+		t.Fatalf("UnicastAddressChangeCoalescer called the callback %d times although 1 is expected.", len(calls))
+	}
+
+	if len(calls[0]) != 1 {
+		t.Fatalf("UnicastAddressChangeCoalescer reported %d changed interfaces although 1 is expected.",
+			len(calls[0]))
+	}
+
+	change := calls[0][0]
+
+	if change.InterfaceLuid != 1 || len(change.Addresses) != 2 || len(change.Removed) != 0 ||
+		len(change.Added) != 1 || !change.Added[0].Equal(added) {
+		t.Errorf("UnicastAddressChangeCoalescer reported unexpected change: %+v", change)
+	}
+
+	// Removal of the original address.
+	current = []*UnicastIpAddressRow{newCoalescerTestRow(1, "2001:db8::2", IpDadStatePreferred), other}
+
+	coalescer.notify(MibDeleteInstance, 1, &existing.Address.Address)
+
+	clock.advance(time.Second)
+
+	if len(calls) != 2 {
+		t.Fatalf("UnicastAddressChangeCoalescer called the callback %d times although 2 is expected.", len(calls))
+	}
+
+	change = calls[1][0]
+
+	if len(calls[1]) != 1 || change.InterfaceLuid != 1 || len(change.Added) != 0 || len(change.Removed) != 1 ||
+		!change.Removed[0].Equal(existing.Address.Address) {
+		t.Errorf("UnicastAddressChangeCoalescer reported unexpected change: %+v", change)
+	}
+
+	// Notifications which don't change anything don't call the callback.
+	coalescer.notify(MibParameterNotification, 2, &other.Address.Address)
+
+	clock.advance(time.Second)
+
+	if len(calls) != 2 {
+		t.Errorf("UnicastAddressChangeCoalescer called the callback although nothing has changed.")
+	}
+}
+
+func TestDiffUnicastAddressRows(t *testing.T) {
+
+	old := []*UnicastIpAddressRow{newCoalescerTestRow(1, "2001:db8::1", IpDadStateTentative)}
+	new := []*UnicastIpAddressRow{newCoalescerTestRow(1, "2001:db8::1", IpDadStatePreferred)}
+
+	change := diffUnicastAddressRows(1, old, new)
+
+	if change == nil || len(change.Added) != 0 || len(change.Removed) != 0 {
+		t.Errorf("diffUnicastAddressRows() returned %+v for a DAD state change.", change)
+	}
+
+	if change = diffUnicastAddressRows(1, new, new); change != nil {
+		t.Errorf("diffUnicastAddressRows() returned %+v for identical rows.", change)
+	}
+}
+
+func TestUnicastAddressChangeCoalescerStop(t *testing.T) {
+
+	clock := newFakeClock()
+	calls := 0
+
+	coalescer := NewUnicastAddressChangeCoalescer(time.Second, clock, func(changes []*UnicastAddressChange) {
+		calls++
+	})
+
+	coalescer.getAddresses = func(family AddressFamily) ([]*UnicastIpAddressRow, error) {
+		return []*UnicastIpAddressRow{newCoalescerTestRow(1, "2001:db8::1", IpDadStatePreferred)}, nil
+	}
+
+	added := net.ParseIP("2001:db8::1")
+
+	coalescer.notify(MibAddInstance, 1, &added)
+
+	if err := coalescer.Stop(); err != nil {
+		t.Errorf("UnicastAddressChangeCoalescer.Stop() returned an error: %v", err)
+	}
+
+	if clock.pendingTimers() != 0 {
+		t.Errorf("UnicastAddressChangeCoalescer has %d timers pending after Stop().", clock.pendingTimers())
+	}
+
+	// Notification delivered, and timer fired concurrently with Stop().
+	coalescer.notify(MibAddInstance, 1, &added)
+	coalescer.flush()
+
+	if calls != 0 || clock.pendingTimers() != 0 {
+		t.Errorf("UnicastAddressChangeCoalescer called the callback %d times and has %d timers pending after "+
+			"Stop().", calls, clock.pendingTimers())
+	}
+}
+
+func TestUnicastAddressChangeCoalescerStopDuringFlush(t *testing.T) {
+
+	clock := newFakeClock()
+
+	coalescer := NewUnicastAddressChangeCoalescer(time.Second, clock, func(changes []*UnicastAddressChange) {
+		t.Errorf("UnicastAddressChangeCoalescer called the callback although listing addresses failed.")
+	})
+
+	listing := make(chan struct{})
+	proceed := make(chan struct{})
+
+	coalescer.getAddresses = func(family AddressFamily) ([]*UnicastIpAddressRow, error) {
+		close(listing)
+		<-proceed
+		return nil, errors.New("listing failed")
+	}
+
+	added := net.ParseIP("2001:db8::1")
+
+	coalescer.notify(MibAddInstance, 1, &added)
+
+	flushed := make(chan struct{})
+
+	go func() {
+		clock.advance(time.Second)
+		close(flushed)
+	}()
+
+	<-listing
+
+	stopped := make(chan error)
+
+	go func() {
+		stopped <- coalescer.Stop()
+	}()
+
+	for {
+		coalescer.mutex.Lock()
+		isStopped := coalescer.stopped
+		coalescer.mutex.Unlock()
+
+		if isStopped {
+			break
+		}
+
+		runtime.Gosched()
+	}
+
+	select {
+	case <-stopped:
+		t.Errorf("UnicastAddressChangeCoalescer.Stop() returned before the flush in progress has completed.")
+	default:
+	}
+
+	close(proceed)
+	<-flushed
+	<-stopped
+
+	if clock.pendingTimers() != 0 {
+		t.Errorf("UnicastAddressChangeCoalescer scheduled a retry after Stop().")
+	}
+}
+
+func TestUnicastAddressChangeCoalescerStart(t *testing.T) {
+
+	coalescer := NewUnicastAddressChangeCoalescer(time.Second, newFakeClock(),
+		func(changes []*UnicastAddressChange) {})
+
+	coalescer.getAddresses = func(family AddressFamily) ([]*UnicastIpAddressRow, error) {
+		return nil, nil
+	}
+
+	registering := make(chan struct{})
+	release := make(chan struct{})
+	registrations := 0
+
+	coalescer.registerCallback = func(callback func(notificationType MibNotificationType, interfaceLuid uint64,
+		ip *net.IP)) (*UnicastAddressChangeCallback, error) {
+
+		registrations++
+		close(registering)
+		<-release
+
+		return nil, nil
+	}
+
+	errs := make(chan error)
+
+	go func() {
+		errs <- coalescer.Start()
+	}()
+
+	<-registering
+
+	if err := coalescer.Start(); err == nil {
+		t.Errorf("UnicastAddressChangeCoalescer.Start() succeeded while another Start() was in progress.")
+	}
+
+	close(release)
+
+	if err := <-errs; err != nil {
+		t.Errorf("UnicastAddressChangeCoalescer.Start() returned an error: %v", err)
+	}
+
+	if registrations != 1 {
+		t.Errorf("UnicastAddressChangeCoalescer registered %d times although 1 is expected.", registrations)
+	}
+
+	if err := coalescer.Start(); err == nil {
+		t.Errorf("UnicastAddressChangeCoalescer.Start() succeeded although already started.")
+	}
+
+	if err := coalescer.Stop(); err != nil {
+		t.Errorf("UnicastAddressChangeCoalescer.Stop() returned an error: %v", err)
+	}
+
+	// Stopped coalescer can be started again.
+	registering = make(chan struct{})
+
+	if err := coalescer.Start(); err != nil || registrations != 2 {
+		t.Errorf("UnicastAddressChangeCoalescer.Start() returned %v after Stop(), registering %d times.", err,
+			registrations)
+	}
+}
+
+func TestUnicastAddressChangeCoalescerStartOrder(t *testing.T) {
+
+	clock := newFakeClock()
+
+	var calls [][]*UnicastAddressChange
+
+	coalescer := NewUnicastAddressChangeCoalescer(time.Second, clock, func(changes []*UnicastAddressChange) {
+		calls = append(calls, changes)
+	})
+
+	existing := newCoalescerTestRow(1, "2001:db8::1", IpDadStatePreferred)
+	current := []*UnicastIpAddressRow{existing}
+
+	var notify func(notificationType MibNotificationType, interfaceLuid uint64, ip *net.IP)
+
+	coalescer.registerCallback = func(callback func(notificationType MibNotificationType, interfaceLuid uint64,
+		ip *net.IP)) (*UnicastAddressChangeCallback, error) {
+
+		notify = callback
+		return nil, nil
+	}
+
+	coalescer.getAddresses = func(family AddressFamily) ([]*UnicastIpAddressRow, error) {
+		if notify == nil {
+			t.Error("UnicastAddressChangeCoalescer took the snapshot before registering for notifications.")
+		}
+		return current, nil
+	}
+
+	if err := coalescer.Start(); err != nil {
+		t.Fatalf("UnicastAddressChangeCoalescer.Start() returned an error: %v", err)
+	}
+
+	// Address added right after the snapshot.
+	added := net.ParseIP("2001:db8::2")
+	current = []*UnicastIpAddressRow{existing, newCoalescerTestRow(1, "2001:db8::2", IpDadStatePreferred)}
+	notify(MibAddInstance, 1, &added)
+
+	clock.advance(time.Second)
+
+	if len(calls) != 1 || len(calls[0]) != 1 || len(calls[0][0].Added) != 1 || !calls[0][0].Added[0].Equal(added) {
+		t.Errorf("UnicastAddressChangeCoalescer reported %v instead of the address added after the snapshot.", calls)
+	}
+}
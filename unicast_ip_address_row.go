@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -13,18 +16,18 @@ import (
 // Corresponds to MIB_UNICASTIPADDRESS_ROW defined in netioapi.h
 // (https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/ns-netioapi-_mib_unicastipaddress_row).
 type UnicastIpAddressRow struct {
-	Address            *SockaddrInet
-	InterfaceLuid      uint64
-	InterfaceIndex     uint32
-	PrefixOrigin       NlPrefixOrigin
-	SuffixOrigin       NlSuffixOrigin
-	ValidLifetime      uint32
-	PreferredLifetime  uint32
-	OnLinkPrefixLength uint8
-	SkipAsSource       bool
-	DadState           NlDadState
-	ScopeId            uint32
-	CreationTimeStamp  int64
+	Address            *SockaddrInet  `json:"address"`
+	InterfaceLuid      uint64         `json:"interfaceLuid"`
+	InterfaceIndex     uint32         `json:"interfaceIndex"`
+	PrefixOrigin       NlPrefixOrigin `json:"prefixOrigin"`
+	SuffixOrigin       NlSuffixOrigin `json:"suffixOrigin"`
+	ValidLifetime      uint32         `json:"validLifetime"`
+	PreferredLifetime  uint32         `json:"preferredLifetime"`
+	OnLinkPrefixLength uint8          `json:"onLinkPrefixLength"`
+	SkipAsSource       bool           `json:"skipAsSource"`
+	DadState           NlDadState     `json:"dadState"`
+	ScopeId            uint32         `json:"scopeId"`
+	CreationTimeStamp  int64          `json:"creationTimeStamp"`
 }
 
 func (address *UnicastIpAddressRow) equal(other *UnicastIpAddressRow) bool {
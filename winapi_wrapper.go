@@ -37,6 +37,24 @@ package winipcfg
 // https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfaceguidtoluid
 //sys	convertInterfaceGuidToLuid(InterfaceGuid *windows.GUID, InterfaceLuid *uint64) (result int32) = iphlpapi.ConvertInterfaceGuidToLuid
 
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfaceluidtoindex
+//sys	convertInterfaceLuidToIndex(InterfaceLuid *uint64, InterfaceIndex *uint32) (result int32) = iphlpapi.ConvertInterfaceLuidToIndex
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfaceindextoluid
+//sys	convertInterfaceIndexToLuid(InterfaceIndex uint32, InterfaceLuid *uint64) (result int32) = iphlpapi.ConvertInterfaceIndexToLuid
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfaceluidtoalias
+//sys	convertInterfaceLuidToAlias(InterfaceLuid *uint64, InterfaceAlias *uint16, Length uintptr) (result int32) = iphlpapi.ConvertInterfaceLuidToAlias
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfacealiastoluid
+//sys	convertInterfaceAliasToLuid(InterfaceAlias *uint16, InterfaceLuid *uint64) (result int32) = iphlpapi.ConvertInterfaceAliasToLuid
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfaceluidtonamew
+//sys	convertInterfaceLuidToName(InterfaceLuid *uint64, InterfaceName *uint16, Length uintptr) (result int32) = iphlpapi.ConvertInterfaceLuidToNameW
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-convertinterfacenametoluidw
+//sys	convertInterfaceNameToLuid(InterfaceName *uint16, InterfaceLuid *uint64) (result int32) = iphlpapi.ConvertInterfaceNameToLuidW
+
 // Unicast IP address - related functions
 
 // https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getunicastipaddresstable
@@ -104,3 +122,28 @@ package winipcfg
 
 // https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-cancelmibchangenotify2
 //sys	cancelMibChangeNotify2(NotificationHandle uintptr) (result int32) = iphlpapi.CancelMibChangeNotify2
+
+// Compartment - related functions
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getcurrentthreadcompartmentid
+//sys	getCurrentThreadCompartmentId() (compartmentId uint32) = iphlpapi.GetCurrentThreadCompartmentId
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-setcurrentthreadcompartmentid
+//sys	setCurrentThreadCompartmentId(CompartmentId uint32) (result int32) = iphlpapi.SetCurrentThreadCompartmentId
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getsessioncompartmentid
+//sys	getSessionCompartmentId(SessionId uint32) (compartmentId uint32) = iphlpapi.GetSessionCompartmentId
+
+// https://docs.microsoft.com/en-us/windows/desktop/api/netioapi/nf-netioapi-getdefaultcompartmentid
+//sys	getDefaultCompartmentId() (compartmentId uint32) = iphlpapi.GetDefaultCompartmentId
+
+// DNS - related functions
+
+// https://docs.microsoft.com/en-us/windows/win32/api/netioapi/nf-netioapi-setinterfacednssettings
+// The interface GUID is passed by value, which on amd64 means by reference, and on 386 as four 32-bit words; see
+// callSetInterfaceDnsSettings().
+//sys	setInterfaceDnsSettingsByRef(Interface *windows.GUID, Settings *wtDnsInterfaceSettings) (result int32) = iphlpapi.SetInterfaceDnsSettings
+//sys	setInterfaceDnsSettingsByValue(Interface1 uint32, Interface2 uint32, Interface3 uint32, Interface4 uint32, Settings *wtDnsInterfaceSettings) (result int32) = iphlpapi.SetInterfaceDnsSettings
+
+// Undocumented; it's what "ipconfig /flushdns" calls. Returns BOOL.
+//sys	dnsFlushResolverCache() (result uint32) = dnsapi.DnsFlushResolverCache
@@ -0,0 +1,32 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+// Values of wtDnsInterfaceSettings.Version and Flags fields, defined in netioapi.h.
+const (
+	dnsInterfaceSettingsVersion1 uint32 = 1
+
+	dnsSettingIpv6       uint64 = 0x0001
+	dnsSettingNameserver uint64 = 0x0002
+)
+
+// https://docs.microsoft.com/en-us/windows/win32/api/netioapi/ns-netioapi-dns_interface_settings
+// DNS_INTERFACE_SETTINGS defined in netioapi.h
+type wtDnsInterfaceSettings struct {
+	Version uint32 // Windows type: ULONG
+
+	offset1 [4]uint8 // Layout correction field
+
+	Flags               uint64  // Windows type: ULONG64
+	Domain              *uint16 // Windows type: PWSTR
+	NameServer          *uint16 // Windows type: PWSTR
+	SearchList          *uint16 // Windows type: PWSTR
+	RegistrationEnabled uint32  // Windows type: ULONG
+	RegisterAdapterName uint32  // Windows type: ULONG
+	EnableLLMNR         uint32  // Windows type: ULONG
+	QueryAdapterName    uint32  // Windows type: ULONG
+	ProfileNameServer   *uint16 // Windows type: PWSTR
+}
@@ -0,0 +1,75 @@
+/* SPDX-License-Identifier: MIT
+ *
+ * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
+ */
+
+package winipcfg
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestWtDnsInterfaceSettingsSize(t *testing.T) {
+
+	const actualWtDnsInterfaceSettingsSize = unsafe.Sizeof(wtDnsInterfaceSettings{})
+
+	if actualWtDnsInterfaceSettingsSize != wtDnsInterfaceSettings_Size {
+		t.Errorf("Size of wtDnsInterfaceSettings is %d, although %d is expected.", actualWtDnsInterfaceSettingsSize,
+			wtDnsInterfaceSettings_Size)
+	}
+}
+
+func TestWtDnsInterfaceSettingsOffsets(t *testing.T) {
+
+	s := wtDnsInterfaceSettings{}
+	sp := uintptr(unsafe.Pointer(&s))
+
+	offset := uintptr(unsafe.Pointer(&s.Flags)) - sp
+
+	if offset != wtDnsInterfaceSettings_Flags_Offset {
+		t.Errorf("wtDnsInterfaceSettings.Flags offset is %d although %d is expected", offset,
+			wtDnsInterfaceSettings_Flags_Offset)
+		return
+	}
+
+	offset = uintptr(unsafe.Pointer(&s.Domain)) - sp
+
+	if offset != wtDnsInterfaceSettings_Domain_Offset {
+		t.Errorf("wtDnsInterfaceSettings.Domain offset is %d although %d is expected", offset,
+			wtDnsInterfaceSettings_Domain_Offset)
+		return
+	}
+
+	offset = uintptr(unsafe.Pointer(&s.NameServer)) - sp
+
+	if offset != wtDnsInterfaceSettings_NameServer_Offset {
+		t.Errorf("wtDnsInterfaceSettings.NameServer offset is %d although %d is expected", offset,
+			wtDnsInterfaceSettings_NameServer_Offset)
+		return
+	}
+
+	offset = uintptr(unsafe.Pointer(&s.SearchList)) - sp
+
+	if offset != wtDnsInterfaceSettings_SearchList_Offset {
+		t.Errorf("wtDnsInterfaceSettings.SearchList offset is %d although %d is expected", offset,
+			wtDnsInterfaceSettings_SearchList_Offset)
+		return
+	}
+
+	offset = uintptr(unsafe.Pointer(&s.RegistrationEnabled)) - sp
+
+	if offset != wtDnsInterfaceSettings_RegistrationEnabled_Offset {
+		t.Errorf("wtDnsInterfaceSettings.RegistrationEnabled offset is %d although %d is expected", offset,
+			wtDnsInterfaceSettings_RegistrationEnabled_Offset)
+		return
+	}
+
+	offset = uintptr(unsafe.Pointer(&s.ProfileNameServer)) - sp
+
+	if offset != wtDnsInterfaceSettings_ProfileNameServer_Offset {
+		t.Errorf("wtDnsInterfaceSettings.ProfileNameServer offset is %d although %d is expected", offset,
+			wtDnsInterfaceSettings_ProfileNameServer_Offset)
+		return
+	}
+}
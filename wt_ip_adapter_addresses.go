@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -95,7 +98,7 @@ func (wtiaa *wtIpAdapterAddresses) toInterface() (*Interface, error) {
 		Ipv4Metric:        wtiaa.Ipv4Metric,
 		Ipv6Metric:        wtiaa.Ipv6Metric,
 		CompartmentId:     wtiaa.CompartmentId,
-		NetworkGuid:       wtiaa.NetworkGuid,
+		NetworkGuid:       GUID(wtiaa.NetworkGuid),
 		ConnectionType:    wtiaa.ConnectionType,
 		TunnelType:        wtiaa.TunnelType,
 		Dhcpv6Iaid:        wtiaa.Dhcpv6Iaid,
@@ -1,3 +1,6 @@
+//go:build windows
+// +build windows
+
 /* SPDX-License-Identifier: MIT
  *
  * Copyright (C) 2019 WireGuard LLC. All Rights Reserved.
@@ -93,14 +96,22 @@ func (row *wtMibIfRow2) toIfRow() *IfRow {
 		return nil
 	}
 
+	// Physical addresses are binary, so they mustn't be cut at the first zero byte. PhysicalAddressLength applies to
+	// both of them.
+	physicalAddressLength := row.PhysicalAddressLength
+
+	if physicalAddressLength > if_max_phys_address_length {
+		physicalAddressLength = if_max_phys_address_length
+	}
+
 	return &IfRow{
 		InterfaceLuid:               row.InterfaceLuid,
 		InterfaceIndex:              row.InterfaceIndex,
-		InterfaceGuid:               row.InterfaceGuid,
+		InterfaceGuid:               GUID(row.InterfaceGuid),
 		Alias:                       wcharToString(&row.Alias[0], if_max_string_size+1),
 		Description:                 wcharToString(&row.Description[0], if_max_string_size+1),
-		PhysicalAddress:             charToString(&row.PhysicalAddress[0], row.PhysicalAddressLength),
-		PermanentPhysicalAddress:    charToString(&row.PermanentPhysicalAddress[0], if_max_phys_address_length),
+		PhysicalAddress:             string(row.PhysicalAddress[:physicalAddressLength]),
+		PermanentPhysicalAddress:    string(row.PermanentPhysicalAddress[:physicalAddressLength]),
 		Mtu:                         row.Mtu,
 		Type:                        row.Type,
 		TunnelType:                  row.TunnelType,
@@ -112,7 +123,7 @@ func (row *wtMibIfRow2) toIfRow() *IfRow {
 		OperStatus:                  row.OperStatus,
 		AdminStatus:                 row.AdminStatus,
 		MediaConnectState:           row.MediaConnectState,
-		NetworkGuid:                 row.NetworkGuid,
+		NetworkGuid:                 GUID(row.NetworkGuid),
 		ConnectionType:              row.ConnectionType,
 		TransmitLinkSpeed:           row.TransmitLinkSpeed,
 		ReceiveLinkSpeed:            row.ReceiveLinkSpeed,
@@ -1,5 +1,8 @@
 // Code generated by 'go generate'; DO NOT EDIT.
 
+//go:build windows
+// +build windows
+
 package winipcfg
 
 import (
@@ -37,6 +40,7 @@ func errnoErr(e syscall.Errno) error {
 }
 
 var (
+	moddnsapi   = windows.NewLazySystemDLL("dnsapi.dll")
 	modiphlpapi = windows.NewLazySystemDLL("iphlpapi.dll")
 
 	procGetAdaptersAddresses            = modiphlpapi.NewProc("GetAdaptersAddresses")
@@ -49,6 +53,12 @@ var (
 	procGetIfTable2Ex                   = modiphlpapi.NewProc("GetIfTable2Ex")
 	procConvertInterfaceLuidToGuid      = modiphlpapi.NewProc("ConvertInterfaceLuidToGuid")
 	procConvertInterfaceGuidToLuid      = modiphlpapi.NewProc("ConvertInterfaceGuidToLuid")
+	procConvertInterfaceLuidToIndex     = modiphlpapi.NewProc("ConvertInterfaceLuidToIndex")
+	procConvertInterfaceIndexToLuid     = modiphlpapi.NewProc("ConvertInterfaceIndexToLuid")
+	procConvertInterfaceLuidToAlias     = modiphlpapi.NewProc("ConvertInterfaceLuidToAlias")
+	procConvertInterfaceAliasToLuid     = modiphlpapi.NewProc("ConvertInterfaceAliasToLuid")
+	procConvertInterfaceLuidToNameW     = modiphlpapi.NewProc("ConvertInterfaceLuidToNameW")
+	procConvertInterfaceNameToLuidW     = modiphlpapi.NewProc("ConvertInterfaceNameToLuidW")
 	procGetUnicastIpAddressTable        = modiphlpapi.NewProc("GetUnicastIpAddressTable")
 	procGetUnicastIpAddressEntry        = modiphlpapi.NewProc("GetUnicastIpAddressEntry")
 	procSetUnicastIpAddressEntry        = modiphlpapi.NewProc("SetUnicastIpAddressEntry")
@@ -69,6 +79,12 @@ var (
 	procNotifyUnicastIpAddressChange    = modiphlpapi.NewProc("NotifyUnicastIpAddressChange")
 	procNotifyRouteChange2              = modiphlpapi.NewProc("NotifyRouteChange2")
 	procCancelMibChangeNotify2          = modiphlpapi.NewProc("CancelMibChangeNotify2")
+	procGetCurrentThreadCompartmentId   = modiphlpapi.NewProc("GetCurrentThreadCompartmentId")
+	procSetCurrentThreadCompartmentId   = modiphlpapi.NewProc("SetCurrentThreadCompartmentId")
+	procGetSessionCompartmentId         = modiphlpapi.NewProc("GetSessionCompartmentId")
+	procGetDefaultCompartmentId         = modiphlpapi.NewProc("GetDefaultCompartmentId")
+	procSetInterfaceDnsSettings         = modiphlpapi.NewProc("SetInterfaceDnsSettings")
+	procDnsFlushResolverCache           = moddnsapi.NewProc("DnsFlushResolverCache")
 )
 
 func getAdaptersAddresses(Family uint32, Flags uint32, Reserved uintptr, AdapterAddresses *wtIpAdapterAddresses, SizePointer *uint32) (result uint32) {
@@ -130,6 +146,42 @@ func convertInterfaceGuidToLuid(InterfaceGuid *windows.GUID, InterfaceLuid *uint
 	return
 }
 
+func convertInterfaceLuidToIndex(InterfaceLuid *uint64, InterfaceIndex *uint32) (result int32) {
+	r0, _, _ := syscall.Syscall(procConvertInterfaceLuidToIndex.Addr(), 2, uintptr(unsafe.Pointer(InterfaceLuid)), uintptr(unsafe.Pointer(InterfaceIndex)), 0)
+	result = int32(r0)
+	return
+}
+
+func convertInterfaceIndexToLuid(InterfaceIndex uint32, InterfaceLuid *uint64) (result int32) {
+	r0, _, _ := syscall.Syscall(procConvertInterfaceIndexToLuid.Addr(), 2, uintptr(InterfaceIndex), uintptr(unsafe.Pointer(InterfaceLuid)), 0)
+	result = int32(r0)
+	return
+}
+
+func convertInterfaceLuidToAlias(InterfaceLuid *uint64, InterfaceAlias *uint16, Length uintptr) (result int32) {
+	r0, _, _ := syscall.Syscall(procConvertInterfaceLuidToAlias.Addr(), 3, uintptr(unsafe.Pointer(InterfaceLuid)), uintptr(unsafe.Pointer(InterfaceAlias)), uintptr(Length))
+	result = int32(r0)
+	return
+}
+
+func convertInterfaceAliasToLuid(InterfaceAlias *uint16, InterfaceLuid *uint64) (result int32) {
+	r0, _, _ := syscall.Syscall(procConvertInterfaceAliasToLuid.Addr(), 2, uintptr(unsafe.Pointer(InterfaceAlias)), uintptr(unsafe.Pointer(InterfaceLuid)), 0)
+	result = int32(r0)
+	return
+}
+
+func convertInterfaceLuidToName(InterfaceLuid *uint64, InterfaceName *uint16, Length uintptr) (result int32) {
+	r0, _, _ := syscall.Syscall(procConvertInterfaceLuidToNameW.Addr(), 3, uintptr(unsafe.Pointer(InterfaceLuid)), uintptr(unsafe.Pointer(InterfaceName)), uintptr(Length))
+	result = int32(r0)
+	return
+}
+
+func convertInterfaceNameToLuid(InterfaceName *uint16, InterfaceLuid *uint64) (result int32) {
+	r0, _, _ := syscall.Syscall(procConvertInterfaceNameToLuidW.Addr(), 2, uintptr(unsafe.Pointer(InterfaceName)), uintptr(unsafe.Pointer(InterfaceLuid)), 0)
+	result = int32(r0)
+	return
+}
+
 func getUnicastIpAddressTable(Family AddressFamily, Table unsafe.Pointer) (result int32) {
 	r0, _, _ := syscall.Syscall(procGetUnicastIpAddressTable.Addr(), 2, uintptr(Family), uintptr(Table), 0)
 	result = int32(r0)
@@ -267,3 +319,45 @@ func cancelMibChangeNotify2(NotificationHandle uintptr) (result int32) {
 	result = int32(r0)
 	return
 }
+
+func getCurrentThreadCompartmentId() (compartmentId uint32) {
+	r0, _, _ := syscall.Syscall(procGetCurrentThreadCompartmentId.Addr(), 0, 0, 0, 0)
+	compartmentId = uint32(r0)
+	return
+}
+
+func setCurrentThreadCompartmentId(CompartmentId uint32) (result int32) {
+	r0, _, _ := syscall.Syscall(procSetCurrentThreadCompartmentId.Addr(), 1, uintptr(CompartmentId), 0, 0)
+	result = int32(r0)
+	return
+}
+
+func getSessionCompartmentId(SessionId uint32) (compartmentId uint32) {
+	r0, _, _ := syscall.Syscall(procGetSessionCompartmentId.Addr(), 1, uintptr(SessionId), 0, 0)
+	compartmentId = uint32(r0)
+	return
+}
+
+func getDefaultCompartmentId() (compartmentId uint32) {
+	r0, _, _ := syscall.Syscall(procGetDefaultCompartmentId.Addr(), 0, 0, 0, 0)
+	compartmentId = uint32(r0)
+	return
+}
+
+func setInterfaceDnsSettingsByRef(Interface *windows.GUID, Settings *wtDnsInterfaceSettings) (result int32) {
+	r0, _, _ := syscall.Syscall(procSetInterfaceDnsSettings.Addr(), 2, uintptr(unsafe.Pointer(Interface)), uintptr(unsafe.Pointer(Settings)), 0)
+	result = int32(r0)
+	return
+}
+
+func setInterfaceDnsSettingsByValue(Interface1 uint32, Interface2 uint32, Interface3 uint32, Interface4 uint32, Settings *wtDnsInterfaceSettings) (result int32) {
+	r0, _, _ := syscall.Syscall6(procSetInterfaceDnsSettings.Addr(), 5, uintptr(Interface1), uintptr(Interface2), uintptr(Interface3), uintptr(Interface4), uintptr(unsafe.Pointer(Settings)), 0)
+	result = int32(r0)
+	return
+}
+
+func dnsFlushResolverCache() (result uint32) {
+	r0, _, _ := syscall.Syscall(procDnsFlushResolverCache.Addr(), 0, 0, 0, 0)
+	result = uint32(r0)
+	return
+}